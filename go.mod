@@ -4,9 +4,13 @@ go 1.14
 
 require (
 	github.com/ghodss/yaml v1.0.0
+	github.com/prometheus/client_golang v1.11.1
+	github.com/spf13/cobra v1.0.0
+	github.com/spf13/pflag v1.0.5
 	github.com/stretchr/testify v1.4.0
 	github.com/vektra/mockery v1.1.2
 	k8s.io/api v0.19.6
 	k8s.io/apimachinery v0.19.6
+	k8s.io/cli-runtime v0.19.6
 	k8s.io/client-go v0.19.6
 )
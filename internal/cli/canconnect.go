@@ -0,0 +1,81 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+
+	"github.com/aszecowka/netpolvalidator/internal"
+	"github.com/aszecowka/netpolvalidator/internal/connectivity"
+	"github.com/aszecowka/netpolvalidator/internal/output"
+)
+
+func newCanIConnectCommand(configFlags *genericclioptions.ConfigFlags, streams genericclioptions.IOStreams) *cobra.Command {
+	cfg := internal.ClusterStateConfig{}
+	ports := portOptions{}
+	cmd := &cobra.Command{
+		Use:   "can-i-connect SOURCE DESTINATION",
+		Short: "Check whether network policies allow traffic between two workloads",
+		Long: "Check whether network policies allow traffic between two workloads.\n" +
+			"Workloads are given as KIND/NAME, e.g. deployment/web, in the current namespace, or as KIND/NAMESPACE/NAME.",
+		Example: "  kubectl netpol can-i-connect deployment/frontend/web deployment/orders/api --port 8080",
+		Args:    cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := cfg.Validate(); err != nil {
+				return err
+			}
+			port, err := ports.toPort()
+			if err != nil {
+				return err
+			}
+			namespace, err := currentNamespace(configFlags)
+			if err != nil {
+				return err
+			}
+			from, err := ownerName(args[0], namespace)
+			if err != nil {
+				return err
+			}
+			to, err := ownerName(args[1], namespace)
+			if err != nil {
+				return err
+			}
+
+			clusterState, err := fetchClusterState(configFlags, cfg)
+			if err != nil {
+				return err
+			}
+			fromEndpoint, found := connectivity.FindEndpoint(*clusterState, from)
+			if !found {
+				return fmt.Errorf("workload %s not found", from)
+			}
+			toEndpoint, found := connectivity.FindEndpoint(*clusterState, to)
+			if !found {
+				return fmt.Errorf("workload %s not found", to)
+			}
+			verdict, err := connectivity.Check(*clusterState, fromEndpoint, toEndpoint, port)
+			if err != nil {
+				return err
+			}
+			return output.WriteVerdict(streams.Out, from, to, port, verdict)
+		},
+	}
+	ports.AddFlags(cmd.Flags())
+	cfg.AddFlags(cmd.Flags())
+	return cmd
+}
+
+// ownerName converts KIND/NAME or KIND/NAMESPACE/NAME to the owner name of a pod candidate.
+func ownerName(workload, namespace string) (string, error) {
+	parts := strings.Split(workload, "/")
+	switch len(parts) {
+	case 2:
+		return fmt.Sprintf("%s/%s/%s", strings.ToLower(parts[0]), namespace, parts[1]), nil
+	case 3:
+		return fmt.Sprintf("%s/%s/%s", strings.ToLower(parts[0]), parts[1], parts[2]), nil
+	default:
+		return "", fmt.Errorf("invalid workload %q, expected KIND/NAME or KIND/NAMESPACE/NAME", workload)
+	}
+}
@@ -0,0 +1,153 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+
+	"github.com/aszecowka/netpolvalidator/internal"
+	"github.com/aszecowka/netpolvalidator/internal/kubeconfig"
+	"github.com/aszecowka/netpolvalidator/internal/model"
+	"github.com/aszecowka/netpolvalidator/internal/netpol"
+	"github.com/aszecowka/netpolvalidator/internal/ns"
+	"github.com/aszecowka/netpolvalidator/internal/podcandidate"
+	"github.com/aszecowka/netpolvalidator/internal/rule"
+	"github.com/aszecowka/netpolvalidator/internal/state"
+)
+
+// loadClusters returns the cluster selected by kubectl flags, or clusters of the given kubeconfig contexts.
+func loadClusters(configFlags *genericclioptions.ConfigFlags, contexts []string) ([]kubeconfig.Cluster, error) {
+	return kubeconfig.LoadClusters(configFlags.ToRawKubeConfigLoader(), *configFlags.Context, contexts)
+}
+
+func newClientset(cluster kubeconfig.Cluster, qps float64, burst int) (*kubernetes.Clientset, error) {
+	config := rest.CopyConfig(cluster.Config)
+	config.QPS = float32(qps)
+	config.Burst = burst
+	return kubernetes.NewForConfig(config)
+}
+
+// newDynamicClient creates a client for custom resources, like Argo Rollouts.
+func newDynamicClient(cluster kubeconfig.Cluster, qps float64, burst int) (dynamic.Interface, error) {
+	config := rest.CopyConfig(cluster.Config)
+	config.QPS = float32(qps)
+	config.Burst = burst
+	return dynamic.NewForConfig(config)
+}
+
+// fetchClusterState fetches the state of the cluster selected by kubectl flags.
+func fetchClusterState(configFlags *genericclioptions.ConfigFlags, cfg internal.ClusterStateConfig) (*model.ClusterState, error) {
+	clusters, err := loadClusters(configFlags, nil)
+	if err != nil {
+		return nil, err
+	}
+	clientset, err := newClientset(clusters[0], cfg.QPS, cfg.Burst)
+	if err != nil {
+		return nil, err
+	}
+	dynamicClient, err := newDynamicClient(clusters[0], cfg.QPS, cfg.Burst)
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancelFunc := context.WithTimeout(context.Background(), cfg.Timeout)
+	defer cancelFunc()
+	return buildClusterState(ctx, clientset, dynamicClient, cfg)
+}
+
+func buildClusterState(ctx context.Context, clientset kubernetes.Interface, dynamicClient dynamic.Interface, cfg internal.ClusterStateConfig) (*model.ClusterState, error) {
+	clusterStateBuilder, err := newClusterStateBuilder(clientset, dynamicClient, cfg)
+	if err != nil {
+		return nil, err
+	}
+	return clusterStateBuilder.Build(ctx)
+}
+
+// newClusterStateBuilder creates a Builder fetching the cluster state from the API server, as configured by cfg.
+// loadCustomResources reads definitions of custom resources that create pods, if the path is set.
+func loadCustomResources(path string) ([]podcandidate.CustomResource, error) {
+	if path == "" {
+		return nil, nil
+	}
+	return podcandidate.LoadCustomResources(path)
+}
+
+func newClusterStateBuilder(clientset kubernetes.Interface, dynamicClient dynamic.Interface, cfg internal.ClusterStateConfig) (*state.Builder, error) {
+	nsService := ns.New(clientset.CoreV1().Namespaces())
+	netpolService := netpol.NewService(clientset.NetworkingV1())
+	served, err := podcandidate.DiscoverServedResources(clientset.Discovery())
+	if err != nil {
+		return nil, err
+	}
+	podCandidateProviders := make(map[string]state.PodCandidatesProvider)
+	for workloadType, fetcher := range podcandidate.NewFetchers(clientset, dynamicClient, served) {
+		podCandidateProviders[string(workloadType)] = fetcher
+	}
+	customResources, err := loadCustomResources(cfg.CustomResources)
+	if err != nil {
+		return nil, err
+	}
+	for _, cr := range customResources {
+		if _, found := podCandidateProviders[cr.Name]; found {
+			return nil, fmt.Errorf("custom resource %q has the same name as a built-in workload type", cr.Name)
+		}
+		fetcher, err := podcandidate.NewCustomResourceFetcher(dynamicClient, cr)
+		if err != nil {
+			return nil, err
+		}
+		podCandidateProviders[cr.Name] = fetcher
+	}
+
+	return state.NewBuilder(nsService, netpolService, podCandidateProviders, state.Options{
+		Workers:          cfg.Workers,
+		ClusterWideLists: cfg.ClusterWideLists,
+		PartialResults:   cfg.PartialResults,
+		Retry: wait.Backoff{
+			Steps:    cfg.Retries + 1,
+			Duration: cfg.RetryBackoff,
+			Factor:   2,
+			Jitter:   0.1,
+		},
+	}), nil
+}
+
+// currentNamespace returns the namespace set with -n, or the namespace of the current context.
+func currentNamespace(configFlags *genericclioptions.ConfigFlags) (string, error) {
+	namespace, _, err := configFlags.ToRawKubeConfigLoader().Namespace()
+	if err != nil {
+		return "", fmt.Errorf("while getting current namespace: %w", err)
+	}
+	return namespace, nil
+}
+
+func newValidators() map[string]rule.Validator {
+	validators := make(map[string]rule.Validator)
+	validators["label correctness"] = rule.NewLabelCorrectness()
+	validators["inactive workloads"] = rule.NewInactiveWorkloads()
+	validators["policy types"] = rule.NewPolicyTypes()
+	return validators
+}
+
+func validate(clusterState model.ClusterState) ([]model.Violation, error) {
+	validators := newValidators()
+	names := make([]string, 0, len(validators))
+	for name := range validators {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var allViolations []model.Violation
+	for _, name := range names {
+		violations, err := validators[name].Validate(clusterState)
+		if err != nil {
+			return nil, fmt.Errorf("while running %s validator: %w", name, err)
+		}
+		allViolations = append(allViolations, violations...)
+	}
+	return allViolations, nil
+}
@@ -0,0 +1,65 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+
+	"github.com/aszecowka/netpolvalidator/internal"
+	"github.com/aszecowka/netpolvalidator/internal/connectivity"
+	"github.com/aszecowka/netpolvalidator/internal/model"
+	"github.com/aszecowka/netpolvalidator/internal/output"
+	"github.com/aszecowka/netpolvalidator/internal/scope"
+)
+
+func newExplainCommand(configFlags *genericclioptions.ConfigFlags, streams genericclioptions.IOStreams) *cobra.Command {
+	cfg := internal.ClusterStateConfig{}
+	cmd := &cobra.Command{
+		Use:   "explain NETWORK_POLICY",
+		Short: "Describe which pods a network policy selects and which traffic it allows",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := cfg.Validate(); err != nil {
+				return err
+			}
+			namespace, err := currentNamespace(configFlags)
+			if err != nil {
+				return err
+			}
+			clusterState, err := fetchClusterState(configFlags, cfg)
+			if err != nil {
+				return err
+			}
+
+			name := args[0]
+			for _, np := range clusterState.NetworkPolicies[namespace] {
+				if np.Name != name {
+					continue
+				}
+				explanation, err := connectivity.Explain(*clusterState, np)
+				if err != nil {
+					return err
+				}
+				policyScope, err := scope.New([]string{namespace}, "", nil)
+				if err != nil {
+					return err
+				}
+				allViolations, err := validate(policyScope.Apply(*clusterState))
+				if err != nil {
+					return err
+				}
+				var violations []model.Violation
+				for _, v := range allViolations {
+					if v.NetworkPolicyName == name {
+						violations = append(violations, v)
+					}
+				}
+				return output.WriteExplanation(streams.Out, explanation, violations)
+			}
+			return fmt.Errorf("network policy %s/%s not found", namespace, name)
+		},
+	}
+	cfg.AddFlags(cmd.Flags())
+	return cmd
+}
@@ -0,0 +1,103 @@
+package cli
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+
+	"github.com/aszecowka/netpolvalidator/internal"
+	"github.com/aszecowka/netpolvalidator/internal/fix"
+	"github.com/aszecowka/netpolvalidator/internal/output"
+	"github.com/aszecowka/netpolvalidator/internal/scope"
+)
+
+func newFixCommand(configFlags *genericclioptions.ConfigFlags, streams genericclioptions.IOStreams) *cobra.Command {
+	cfg := internal.ClusterStateConfig{}
+	var allNamespaces bool
+	var outputType, outputDir string
+	cmd := &cobra.Command{
+		Use:   "fix",
+		Short: "Print network policies with violations fixed",
+		Long: "Print network policies from the current namespace, or from all namespaces with -A, with violations\n" +
+			"that have an unambiguous remedy fixed: typos in labels with a single close match, custom namespace labels\n" +
+			"replaceable by kubernetes.io/metadata.name and rules ignored because of policyTypes. Everything else is left unchanged.\n" +
+			"Network policies are written as YAML manifests, or as kubectl patch commands with JSON patches.\n" +
+			"Changes are listed on the standard error.",
+		Example: "  kubectl netpol fix -A --output-dir fixes\n" +
+			"  kubectl netpol fix -n orders -o json-patch | sh",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := internal.ValidateFixOutput(outputType); err != nil {
+				return err
+			}
+			if err := cfg.Validate(); err != nil {
+				return err
+			}
+			var namespaces []string
+			if !allNamespaces {
+				namespace, err := currentNamespace(configFlags)
+				if err != nil {
+					return err
+				}
+				namespaces = append(namespaces, namespace)
+			}
+			validationScope, err := scope.New(namespaces, "", nil)
+			if err != nil {
+				return err
+			}
+			clusterState, err := fetchClusterState(configFlags, cfg)
+			if err != nil {
+				return err
+			}
+
+			fixes := fix.Fix(validationScope.Apply(*clusterState))
+			for _, f := range fixes {
+				for _, c := range f.Changes {
+					fmt.Fprintf(streams.ErrOut, "%s/%s: %s\n", f.Fixed.Namespace, f.Fixed.Name, c.Description)
+				}
+			}
+			if outputDir != "" {
+				return writeFixes(outputDir, outputType, fixes)
+			}
+			if outputType == internal.OutputJSONPatch {
+				return output.WriteFixPatches(streams.Out, fixes)
+			}
+			return output.WriteFixedPolicies(streams.Out, fixes)
+		},
+	}
+	cmd.Flags().BoolVarP(&allNamespaces, "all-namespaces", "A", false, "fix network policies from all namespaces")
+	cmd.Flags().StringVarP(&outputType, "output", "o", internal.OutputYAML, fmt.Sprintf("output type. Possible values: [%s, %s]", internal.OutputYAML, internal.OutputJSONPatch))
+	cmd.Flags().StringVar(&outputDir, "output-dir", "", "(optional) directory to which a file per fixed network policy is written, named NAMESPACE.NAME.yaml or NAMESPACE.NAME.patch.json")
+	cfg.AddFlags(cmd.Flags())
+	return cmd
+}
+
+// writeFixes writes every fixed network policy to a separate file in the directory.
+func writeFixes(dir, outputType string, fixes []fix.PolicyFix) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("while creating output directory: %w", err)
+	}
+	for _, f := range fixes {
+		var name string
+		var content []byte
+		var err error
+		if outputType == internal.OutputJSONPatch {
+			name = fmt.Sprintf("%s.%s.patch.json", f.Fixed.Namespace, f.Fixed.Name)
+			content, err = output.FixPatchJSON(f)
+		} else {
+			name = fmt.Sprintf("%s.%s.yaml", f.Fixed.Namespace, f.Fixed.Name)
+			content, err = output.FixedPolicyYAML(f)
+		}
+		if err != nil {
+			return err
+		}
+		if err := ioutil.WriteFile(filepath.Join(dir, name), content, 0644); err != nil {
+			return fmt.Errorf("while writing %s: %w", name, err)
+		}
+	}
+	return nil
+}
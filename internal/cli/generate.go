@@ -0,0 +1,141 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+
+	"github.com/aszecowka/netpolvalidator/internal"
+	"github.com/aszecowka/netpolvalidator/internal/generate"
+	"github.com/aszecowka/netpolvalidator/internal/model"
+	"github.com/aszecowka/netpolvalidator/internal/output"
+	"github.com/aszecowka/netpolvalidator/internal/scope"
+	"github.com/aszecowka/netpolvalidator/internal/svc"
+)
+
+func newGenerateCommand(configFlags *genericclioptions.ConfigFlags, streams genericclioptions.IOStreams) *cobra.Command {
+	cfg := internal.ClusterStateConfig{}
+	var allNamespaces bool
+	var namespaces, excludeNamespaces []string
+	var outputDir string
+	cmd := &cobra.Command{
+		Use:   "generate",
+		Short: "Generate baseline network policies for namespaces without network policies",
+		Long: "Generate a starter set of network policies for the current namespace, or for all namespaces with -A,\n" +
+			"if they have no network policies yet: a default-deny policy, a policy allowing DNS queries, a policy per service\n" +
+			"allowing ingress traffic to its pods and a policy allowing egress traffic to pods of services from the same namespace.\n" +
+			"Network policies are written as a multi-document YAML, or with --output-dir as a kustomize directory per namespace.",
+		Example: "  kubectl netpol generate -A --exclude-namespaces 'kube-*' --output-dir baseline\n" +
+			"  kubectl kustomize baseline | kubectl apply -f -",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := cfg.Validate(); err != nil {
+				return err
+			}
+			if !allNamespaces {
+				namespace, err := currentNamespace(configFlags)
+				if err != nil {
+					return err
+				}
+				namespaces = append([]string{namespace}, namespaces...)
+			}
+			generateScope, err := scope.New(namespaces, "", excludeNamespaces)
+			if err != nil {
+				return err
+			}
+			clusterState, err := fetchClusterStateWithServices(configFlags, cfg)
+			if err != nil {
+				return err
+			}
+
+			generated, skipped := generate.Generate(generateScope.Apply(*clusterState))
+			for _, ns := range skipped {
+				fmt.Fprintf(streams.ErrOut, "%s: skipped, because its network policies or services were not inspected\n", ns)
+			}
+			for _, ns := range generated {
+				fmt.Fprintf(streams.ErrOut, "%s: generated %d network policies\n", ns.Namespace, len(ns.Policies))
+			}
+			if outputDir != "" {
+				return writeKustomizations(outputDir, generated)
+			}
+			return output.WriteGeneratedPolicies(streams.Out, generated)
+		},
+	}
+	cmd.Flags().BoolVarP(&allNamespaces, "all-namespaces", "A", false, "generate network policies for all namespaces")
+	cmd.Flags().StringSliceVar(&namespaces, "namespaces", nil, "(optional) comma-separated list of namespaces to generate network policies for, in addition to the one set with -n")
+	cmd.Flags().StringSliceVar(&excludeNamespaces, "exclude-namespaces", nil, "(optional) comma-separated list of globs of namespaces to skip, e.g. kube-*")
+	cmd.Flags().StringVar(&outputDir, "output-dir", "", "(optional) directory to which a kustomize directory per namespace is written, together with a kustomization.yaml including all of them")
+	cfg.AddFlags(cmd.Flags())
+	return cmd
+}
+
+// fetchClusterStateWithServices fetches the state of the cluster selected by kubectl flags, together with services.
+func fetchClusterStateWithServices(configFlags *genericclioptions.ConfigFlags, cfg internal.ClusterStateConfig) (*model.ClusterState, error) {
+	clusters, err := loadClusters(configFlags, nil)
+	if err != nil {
+		return nil, err
+	}
+	clientset, err := newClientset(clusters[0], cfg.QPS, cfg.Burst)
+	if err != nil {
+		return nil, err
+	}
+	dynamicClient, err := newDynamicClient(clusters[0], cfg.QPS, cfg.Burst)
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancelFunc := context.WithTimeout(context.Background(), cfg.Timeout)
+	defer cancelFunc()
+	clusterStateBuilder, err := newClusterStateBuilder(clientset, dynamicClient, cfg)
+	if err != nil {
+		return nil, err
+	}
+	return clusterStateBuilder.WithServices(svc.NewService(clientset.CoreV1())).Build(ctx)
+}
+
+// writeKustomizations writes generated network policies to a directory per namespace, each with a kustomization.yaml,
+// and a kustomization.yaml including all namespace directories to the root of the directory.
+func writeKustomizations(dir string, generated []generate.NamespacePolicies) error {
+	var nsDirs []string
+	for _, ns := range generated {
+		nsDir := filepath.Join(dir, ns.Namespace)
+		if err := os.MkdirAll(nsDir, 0755); err != nil {
+			return fmt.Errorf("while creating directory for namespace %s: %w", ns.Namespace, err)
+		}
+		var resources []string
+		for _, np := range ns.Policies {
+			content, err := output.GeneratedPolicyYAML(np)
+			if err != nil {
+				return err
+			}
+			name := np.Name + ".yaml"
+			if err := ioutil.WriteFile(filepath.Join(nsDir, name), content, 0644); err != nil {
+				return fmt.Errorf("while writing %s: %w", filepath.Join(ns.Namespace, name), err)
+			}
+			resources = append(resources, name)
+		}
+		if err := writeKustomization(nsDir, ns.Namespace, resources); err != nil {
+			return err
+		}
+		nsDirs = append(nsDirs, ns.Namespace)
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("while creating output directory: %w", err)
+	}
+	return writeKustomization(dir, "", nsDirs)
+}
+
+func writeKustomization(dir, namespace string, resources []string) error {
+	content, err := output.KustomizationYAML(namespace, resources)
+	if err != nil {
+		return err
+	}
+	if err := ioutil.WriteFile(filepath.Join(dir, "kustomization.yaml"), content, 0644); err != nil {
+		return fmt.Errorf("while writing kustomization.yaml to %s: %w", dir, err)
+	}
+	return nil
+}
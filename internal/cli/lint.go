@@ -0,0 +1,161 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+
+	"github.com/aszecowka/netpolvalidator/internal"
+	"github.com/aszecowka/netpolvalidator/internal/kubeconfig"
+	"github.com/aszecowka/netpolvalidator/internal/model"
+	"github.com/aszecowka/netpolvalidator/internal/output"
+	"github.com/aszecowka/netpolvalidator/internal/scope"
+)
+
+func newLintCommand(configFlags *genericclioptions.ConfigFlags, streams genericclioptions.IOStreams) *cobra.Command {
+	cfg := internal.Config{}
+	var allNamespaces bool
+	cmd := &cobra.Command{
+		Use:   "lint",
+		Short: "Report network policies that do not work as intended",
+		Long: "Report network policies that do not work as intended, e.g. select no pods.\n" +
+			"Network policies from all namespaces are validated, unless the scope is narrowed down with -n, --namespaces,\n" +
+			"--namespace-selector or --exclude-namespaces. Workloads from all namespaces are still inspected,\n" +
+			"so that selectors of network policies in scope are resolved correctly.",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := cfg.Validate(); err != nil {
+				return err
+			}
+			namespaces := cfg.Namespaces
+			if !allNamespaces && *configFlags.Namespace != "" {
+				namespaces = append([]string{*configFlags.Namespace}, namespaces...)
+			}
+			validationScope, err := scope.New(namespaces, cfg.NamespaceSelector, cfg.ExcludeNamespaces)
+			if err != nil {
+				return err
+			}
+			if cfg.Watch {
+				return watchCluster(configFlags, cfg, validationScope, streams)
+			}
+			return generateReport(configFlags, cfg, validationScope, streams.Out)
+		},
+	}
+	cmd.Flags().BoolVarP(&allNamespaces, "all-namespaces", "A", false, "ignore the namespace set with -n")
+	cfg.AddFlags(cmd.Flags())
+	return cmd
+}
+
+// generateReport validates network policies from namespaces in the given scope.
+func generateReport(configFlags *genericclioptions.ConfigFlags, cfg internal.Config, validationScope scope.Scope, out io.Writer) error {
+	clusters, err := loadClusters(configFlags, cfg.Contexts)
+	if err != nil {
+		return err
+	}
+
+	if len(clusters) == 1 {
+		report, err := scanCluster(cfg, clusters[0], validationScope)
+		if err != nil {
+			return fmt.Errorf("while validating cluster %s: %w", clusters[0].Name, err)
+		}
+		return printReports(cfg, []model.ClusterReport{report}, out)
+	}
+
+	// a failing cluster does not stop the audit of the others, it is reported together with them
+	var reports []model.ClusterReport
+	var failed []string
+	for _, cluster := range clusters {
+		report, err := scanCluster(cfg, cluster, validationScope)
+		if err != nil {
+			report = model.ClusterReport{Cluster: cluster.Name, Err: err}
+			failed = append(failed, cluster.Name)
+		}
+		reports = append(reports, report)
+	}
+
+	if err := printReports(cfg, reports, out); err != nil {
+		return err
+	}
+	if len(failed) > 0 {
+		return fmt.Errorf("while validating clusters: %d of %d clusters not validated: %s", len(failed), len(clusters), strings.Join(failed, ", "))
+	}
+	return nil
+}
+
+func scanCluster(cfg internal.Config, cluster kubeconfig.Cluster, validationScope scope.Scope) (model.ClusterReport, error) {
+	// create the clientset
+	clientset, err := newClientset(cluster, cfg.QPS, cfg.Burst)
+	if err != nil {
+		return model.ClusterReport{}, err
+	}
+	dynamicClient, err := newDynamicClient(cluster, cfg.QPS, cfg.Burst)
+	if err != nil {
+		return model.ClusterReport{}, err
+	}
+
+	ctx, cancelFunc := context.WithTimeout(context.Background(), cfg.Timeout)
+	defer cancelFunc()
+
+	built, err := buildClusterState(ctx, clientset, dynamicClient, cfg.ClusterStateConfig)
+	if err != nil {
+		return model.ClusterReport{}, err
+	}
+	clusterState := validationScope.Apply(*built)
+
+	allViolations, err := validate(clusterState)
+	if err != nil {
+		return model.ClusterReport{}, err
+	}
+
+	return model.ClusterReport{
+		Cluster:    cluster.Name,
+		State:      clusterState,
+		Violations: allViolations,
+	}, nil
+}
+
+func printReports(cfg internal.Config, reports []model.ClusterReport, out io.Writer) error {
+	if cfg.Output == internal.OutputMarkdown {
+		md := output.NewMarkdown()
+		var report io.Reader
+		var err error
+		if len(reports) == 1 {
+			report, err = md.Generate(context.Background(), reports[0].State, reports[0].Violations)
+		} else {
+			report, err = md.GenerateForClusters(context.Background(), reports)
+		}
+		if err != nil {
+			return err
+		}
+		_, err = io.Copy(out, report)
+		return err
+	}
+
+	for _, report := range reports {
+		fmt.Fprintf(out, "Cluster: %s\n", report.Cluster)
+		if report.Err != nil {
+			fmt.Fprintf(out, "Not validated: %s\n", report.Err)
+			continue
+		}
+		for namespaces, candidates := range report.State.PodCandidates {
+			fmt.Fprintf(out, "ns: %s, candidates: %d\n", namespaces, len(candidates))
+		}
+
+		fmt.Fprintf(out, "Found %d violations\n", len(report.Violations))
+		for _, v := range report.Violations {
+			fmt.Fprintln(out, v)
+		}
+
+		if len(report.State.CoverageGaps) > 0 {
+			fmt.Fprintf(out, "Not inspected %d resource types, the report may be incomplete\n", len(report.State.CoverageGaps))
+			for _, gap := range report.State.CoverageGaps {
+				fmt.Fprintln(out, gap)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,114 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+
+	"github.com/aszecowka/netpolvalidator/internal"
+	"github.com/aszecowka/netpolvalidator/internal/connectivity"
+	"github.com/aszecowka/netpolvalidator/internal/output"
+)
+
+// portOptions narrow connectivity checks to a single port.
+type portOptions struct {
+	port     int32
+	protocol string
+}
+
+func (o *portOptions) AddFlags(fs *pflag.FlagSet) {
+	fs.Int32Var(&o.port, "port", 0, "(optional) check traffic on this port only. By default, traffic on any port is checked")
+	fs.StringVar(&o.protocol, "protocol", string(v1.ProtocolTCP), "protocol of the port")
+}
+
+func (o portOptions) toPort() (*connectivity.Port, error) {
+	if o.port == 0 {
+		return nil, nil
+	}
+	protocol := v1.Protocol(strings.ToUpper(o.protocol))
+	switch protocol {
+	case v1.ProtocolTCP, v1.ProtocolUDP, v1.ProtocolSCTP:
+	default:
+		return nil, fmt.Errorf("invalid value for protocol parameter. Supported values: [%s, %s, %s]", v1.ProtocolTCP, v1.ProtocolUDP, v1.ProtocolSCTP)
+	}
+	return &connectivity.Port{Protocol: protocol, Number: o.port}, nil
+}
+
+func newMatrixCommand(configFlags *genericclioptions.ConfigFlags, streams genericclioptions.IOStreams) *cobra.Command {
+	cfg := internal.ClusterStateConfig{}
+	ports := portOptions{}
+	var allNamespaces bool
+	var outputType string
+	cmd := &cobra.Command{
+		Use:   "matrix",
+		Short: "Show which pods can talk to each other",
+		Long: "Show which pods from the current namespace, or from all namespaces with -A, can talk to each other\n" +
+			"according to network policies.",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := internal.ValidateOutput(outputType); err != nil {
+				return err
+			}
+			if err := cfg.Validate(); err != nil {
+				return err
+			}
+			port, err := ports.toPort()
+			if err != nil {
+				return err
+			}
+			var namespaces []string
+			if !allNamespaces {
+				namespace, err := currentNamespace(configFlags)
+				if err != nil {
+					return err
+				}
+				namespaces = append(namespaces, namespace)
+			}
+			return printMatrix(configFlags, cfg, namespaces, port, outputType, streams.Out)
+		},
+	}
+	cmd.Flags().BoolVarP(&allNamespaces, "all-namespaces", "A", false, "include pods from all namespaces")
+	cmd.Flags().StringVarP(&outputType, "output", "o", internal.OutputConsole, fmt.Sprintf("output type. Possible values: [%s, %s]", internal.OutputConsole, internal.OutputMarkdown))
+	ports.AddFlags(cmd.Flags())
+	cfg.AddFlags(cmd.Flags())
+	return cmd
+}
+
+func printMatrix(configFlags *genericclioptions.ConfigFlags, cfg internal.ClusterStateConfig, namespaces []string, port *connectivity.Port, outputType string, out io.Writer) error {
+	clusterState, err := fetchClusterState(configFlags, cfg)
+	if err != nil {
+		return err
+	}
+
+	endpoints := connectivity.Endpoints(*clusterState, namespaces...)
+	matrix := output.Matrix{Port: port.String(), Allowed: make([][]bool, len(endpoints)), Partial: make([][]bool, len(endpoints))}
+	for i, from := range endpoints {
+		matrix.Endpoints = append(matrix.Endpoints, from.Candidate.OwnerName())
+		matrix.Allowed[i] = make([]bool, len(endpoints))
+		matrix.Partial[i] = make([]bool, len(endpoints))
+		for j, to := range endpoints {
+			verdict, err := connectivity.Check(*clusterState, from, to, port)
+			if err != nil {
+				return fmt.Errorf("while checking traffic from %s to %s: %w", from.Candidate.OwnerName(), to.Candidate.OwnerName(), err)
+			}
+			matrix.Allowed[i][j] = verdict.Allowed()
+			matrix.Partial[i][j] = verdict.PartiallyAllowed()
+		}
+	}
+
+	if outputType == internal.OutputMarkdown {
+		report, err := output.NewMarkdown().GenerateMatrix(context.Background(), matrix)
+		if err != nil {
+			return err
+		}
+		_, err = io.Copy(out, report)
+		return err
+	}
+	return output.WriteMatrix(out, matrix)
+}
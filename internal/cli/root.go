@@ -0,0 +1,30 @@
+package cli
+
+import (
+	"github.com/spf13/cobra"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+)
+
+// NewRootCommand creates the kubectl-netpol command. It accepts kubectl's standard flags, like --kubeconfig,
+// --context and -n/--namespace, so it can be used as a kubectl plugin: kubectl netpol lint.
+func NewRootCommand(streams genericclioptions.IOStreams) *cobra.Command {
+	configFlags := genericclioptions.NewConfigFlags(true)
+	cmd := &cobra.Command{
+		Use:          "kubectl-netpol",
+		Short:        "Validate Kubernetes network policies",
+		SilenceUsage: true,
+	}
+	configFlags.AddFlags(cmd.PersistentFlags())
+
+	cmd.AddCommand(
+		newLintCommand(configFlags, streams),
+		newMatrixCommand(configFlags, streams),
+		newCanIConnectCommand(configFlags, streams),
+		newExplainCommand(configFlags, streams),
+		newFixCommand(configFlags, streams),
+		newGenerateCommand(configFlags, streams),
+		newSynthesizeCommand(configFlags, streams),
+		newWebhookCommand(configFlags, streams),
+	)
+	return cmd
+}
@@ -0,0 +1,75 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+
+	"github.com/aszecowka/netpolvalidator/internal"
+	"github.com/aszecowka/netpolvalidator/internal/flow"
+	"github.com/aszecowka/netpolvalidator/internal/output"
+)
+
+func newSynthesizeCommand(configFlags *genericclioptions.ConfigFlags, streams genericclioptions.IOStreams) *cobra.Command {
+	cfg := internal.ClusterStateConfig{}
+	var flowsFile, flowFormat, outputDir string
+	cmd := &cobra.Command{
+		Use:   "synthesize",
+		Short: "Synthesize network policies allowing exactly the traffic observed in flow logs",
+		Long: "Synthesize least-privilege network policies from exported flow logs: Hubble JSON (hubble observe -o json),\n" +
+			"Calico flow logs in JSON or a CSV of src-ns,src-pod,dst-ns,dst-pod,port,proto. Pods are mapped to workloads\n" +
+			"by their labels, or by their names when flows have no labels. Every workload gets an ingress policy allowing\n" +
+			"traffic from its observed sources and an egress policy allowing traffic to its observed destinations.\n" +
+			"Network policies are written as a multi-document YAML, or with --output-dir as a kustomize directory per namespace.\n" +
+			"Observed connections that current network policies would block, and flows that could not be mapped, are listed\n" +
+			"on the standard error.",
+		Example: "  hubble observe -o json --since 24h > flows.json\n" +
+			"  kubectl netpol synthesize --flows flows.json --output-dir observed",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if flowsFile == "" {
+				return fmt.Errorf("flows parameter is required")
+			}
+			if err := flow.ValidateFormat(flowFormat); err != nil {
+				return err
+			}
+			if err := cfg.Validate(); err != nil {
+				return err
+			}
+			flows, err := flow.Load(flowsFile, flowFormat)
+			if err != nil {
+				return err
+			}
+			clusterState, err := fetchClusterState(configFlags, cfg)
+			if err != nil {
+				return err
+			}
+
+			connections, unresolved := flow.Resolve(*clusterState, flows)
+			for _, u := range unresolved {
+				fmt.Fprintf(streams.ErrOut, "Warning: %s\n", u)
+			}
+			synthesized, skipped := flow.Synthesize(connections)
+			for _, c := range skipped {
+				fmt.Fprintf(streams.ErrOut, "Warning: connection %s skipped, because a workload has no labels to select it by\n", c)
+			}
+			blocked, err := flow.FindBlocked(*clusterState, connections)
+			if err != nil {
+				return err
+			}
+			if err := output.WriteBlockedConnections(streams.ErrOut, blocked); err != nil {
+				return err
+			}
+			if outputDir != "" {
+				return writeKustomizations(outputDir, synthesized)
+			}
+			return output.WriteGeneratedPolicies(streams.Out, synthesized)
+		},
+	}
+	cmd.Flags().StringVar(&flowsFile, "flows", "", "file with exported flow logs")
+	cmd.Flags().StringVar(&flowFormat, "flow-format", flow.FormatHubble, fmt.Sprintf("format of the flows file. Possible values: [%s, %s, %s]", flow.FormatHubble, flow.FormatCalico, flow.FormatCSV))
+	cmd.Flags().StringVar(&outputDir, "output-dir", "", "(optional) directory to which a kustomize directory per namespace is written, together with a kustomization.yaml including all of them")
+	cfg.AddFlags(cmd.Flags())
+	return cmd
+}
@@ -0,0 +1,99 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/kubernetes/scheme"
+	typedcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/client-go/tools/record"
+
+	"github.com/aszecowka/netpolvalidator/internal"
+	"github.com/aszecowka/netpolvalidator/internal/event"
+	"github.com/aszecowka/netpolvalidator/internal/metrics"
+	"github.com/aszecowka/netpolvalidator/internal/scope"
+	"github.com/aszecowka/netpolvalidator/internal/watch"
+)
+
+const eventComponent = "netpolvalidator"
+
+func watchCluster(configFlags *genericclioptions.ConfigFlags, cfg internal.Config, validationScope scope.Scope, streams genericclioptions.IOStreams) error {
+	clusters, err := loadClusters(configFlags, cfg.Contexts)
+	if err != nil {
+		return err
+	}
+	if len(clusters) > 1 {
+		return fmt.Errorf("watch mode supports a single cluster only, but contexts %s select %d clusters", strings.Join(cfg.Contexts, ","), len(clusters))
+	}
+	clientset, err := newClientset(clusters[0], cfg.QPS, cfg.Burst)
+	if err != nil {
+		return err
+	}
+	dynamicClient, err := newDynamicClient(clusters[0], cfg.QPS, cfg.Burst)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancelFunc := context.WithCancel(context.Background())
+	defer cancelFunc()
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-signals
+		cancelFunc()
+	}()
+
+	logger := log.New(streams.Out, "", log.LstdFlags)
+	listeners := []watch.Listener{watch.NewLogListener(logger)}
+	if cfg.MetricsAddress != "" {
+		collector, err := metrics.NewCollector(prometheus.DefaultRegisterer)
+		if err != nil {
+			return err
+		}
+		listeners = append(listeners, collector)
+		serveMetrics(cfg.MetricsAddress, logger)
+	}
+
+	if cfg.Events || cfg.Annotate {
+		var recorder record.EventRecorder
+		if cfg.Events {
+			broadcaster := record.NewBroadcaster()
+			defer broadcaster.Shutdown()
+			broadcaster.StartRecordingToSink(&typedcorev1.EventSinkImpl{Interface: clientset.CoreV1().Events("")})
+			recorder = broadcaster.NewRecorder(scheme.Scheme, v1.EventSource{Component: eventComponent})
+		}
+		listeners = append(listeners, event.NewListener(recorder, clientset.NetworkingV1(), cfg.Annotate, logger))
+	}
+
+	customResources, err := loadCustomResources(cfg.CustomResources)
+	if err != nil {
+		return err
+	}
+	watcher, err := watch.New(clientset, dynamicClient, customResources, newValidators(), validationScope, cfg.Debounce, logger, listeners...)
+	if err != nil {
+		return err
+	}
+	logger.Printf("watching cluster %s", clusters[0].Name)
+	return watcher.Run(ctx)
+}
+
+func serveMetrics(address string, logger *log.Logger) {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.Handler())
+	go func() {
+		logger.Printf("serving metrics on %s", address)
+		if err := http.ListenAndServe(address, mux); err != nil {
+			logger.Printf("while serving metrics: %s", err)
+		}
+	}()
+}
@@ -0,0 +1,61 @@
+package cli_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+
+	"github.com/aszecowka/netpolvalidator/internal/cli"
+)
+
+func TestWatchRejectsManyClusters(t *testing.T) {
+	// GIVEN
+	streams, _, _, _ := genericclioptions.NewTestIOStreams()
+	sut := cli.NewRootCommand(streams)
+	sut.SetArgs([]string{"lint", "--watch", "--contexts", "all", "--kubeconfig", fixKubeconfig(t)})
+	// WHEN
+	err := sut.Execute()
+	// THEN
+	require.EqualError(t, err, "watch mode supports a single cluster only, but contexts all select 2 clusters")
+}
+
+func fixKubeconfig(t *testing.T) string {
+	t.Helper()
+	dir, err := ioutil.TempDir("", "kubeconfig")
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		require.NoError(t, os.RemoveAll(dir))
+	})
+
+	path := filepath.Join(dir, "config")
+	require.NoError(t, ioutil.WriteFile(path, []byte(`
+apiVersion: v1
+kind: Config
+current-context: staging
+clusters:
+- name: prod
+  cluster:
+    server: https://prod.example.com
+- name: staging
+  cluster:
+    server: https://staging.example.com
+users:
+- name: admin
+  user:
+    token: token
+contexts:
+- name: prod
+  context:
+    cluster: prod
+    user: admin
+- name: staging
+  context:
+    cluster: staging
+    user: admin
+`), 0600))
+	return path
+}
@@ -0,0 +1,102 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/spf13/cobra"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/dynamic/dynamicinformer"
+	"k8s.io/client-go/informers"
+
+	"github.com/aszecowka/netpolvalidator/internal"
+	"github.com/aszecowka/netpolvalidator/internal/podcandidate"
+	"github.com/aszecowka/netpolvalidator/internal/state"
+	"github.com/aszecowka/netpolvalidator/internal/webhook"
+)
+
+func newWebhookCommand(configFlags *genericclioptions.ConfigFlags, streams genericclioptions.IOStreams) *cobra.Command {
+	cfg := internal.WebhookConfig{}
+	cmd := &cobra.Command{
+		Use:   "webhook",
+		Short: "Serve a validating admission webhook for network policies",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := cfg.Validate(); err != nil {
+				return err
+			}
+			return serveWebhook(configFlags, cfg, streams)
+		},
+	}
+	cfg.AddFlags(cmd.Flags())
+	return cmd
+}
+
+func serveWebhook(configFlags *genericclioptions.ConfigFlags, cfg internal.WebhookConfig, streams genericclioptions.IOStreams) error {
+	clusters, err := loadClusters(configFlags, nil)
+	if err != nil {
+		return err
+	}
+	clientset, err := newClientset(clusters[0], cfg.QPS, cfg.Burst)
+	if err != nil {
+		return err
+	}
+	dynamicClient, err := newDynamicClient(clusters[0], cfg.QPS, cfg.Burst)
+	if err != nil {
+		return err
+	}
+	served, err := podcandidate.DiscoverServedResources(clientset.Discovery())
+	if err != nil {
+		return err
+	}
+	customResources, err := loadCustomResources(cfg.CustomResources)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancelFunc := context.WithCancel(context.Background())
+	defer cancelFunc()
+	factory := informers.NewSharedInformerFactory(clientset, 0)
+	dynamicFactory := dynamicinformer.NewDynamicSharedInformerFactory(dynamicClient, 0)
+	builder, _, err := state.NewCachedBuilder(factory, dynamicFactory, served, customResources)
+	if err != nil {
+		return err
+	}
+	factory.Start(ctx.Done())
+	dynamicFactory.Start(ctx.Done())
+	for informerType, synced := range factory.WaitForCacheSync(ctx.Done()) {
+		if !synced {
+			return fmt.Errorf("while waiting for %v informer cache to sync", informerType)
+		}
+	}
+	for resource, synced := range dynamicFactory.WaitForCacheSync(ctx.Done()) {
+		if !synced {
+			return fmt.Errorf("while waiting for %s informer cache to sync", resource.String())
+		}
+	}
+
+	logger := log.New(streams.Out, "", log.LstdFlags)
+	mux := http.NewServeMux()
+	mux.Handle("/validate", webhook.NewHandler(builder, newValidators(), cfg.DenySeverity, logger))
+	server := &http.Server{Addr: cfg.Address, Handler: mux}
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-signals
+		if err := server.Shutdown(context.Background()); err != nil {
+			logger.Printf("while shutting down webhook server: %s", err)
+		}
+	}()
+
+	logger.Printf("serving admission webhook for cluster %s on %s", clusters[0].Name, cfg.Address)
+	if err := server.ListenAndServeTLS(cfg.TLSCertFile, cfg.TLSKeyFile); err != nil && err != http.ErrServerClosed {
+		return err
+	}
+	return nil
+}
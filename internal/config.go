@@ -1,49 +1,212 @@
 package internal
 
 import (
-	"flag"
 	"fmt"
-	"path/filepath"
+	"time"
 
-	"k8s.io/client-go/util/homedir"
+	"github.com/spf13/pflag"
+
+	"github.com/aszecowka/netpolvalidator/internal/kubeconfig"
+	"github.com/aszecowka/netpolvalidator/internal/model"
 )
 
 const (
 	OutputConsole  = "console"
 	OutputMarkdown = "markdown"
+
+	OutputYAML      = "yaml"
+	OutputJSONPatch = "json-patch"
+
+	DenySeverityNone = "none"
+
+	defaultWorkers      = 10
+	defaultTimeout      = 10 * time.Second
+	defaultQPS          = 20
+	defaultBurst        = 40
+	defaultRetries      = 3
+	defaultRetryBackoff = 500 * time.Millisecond
+	defaultDebounce     = 2 * time.Second
+
+	defaultWebhookAddress = ":8443"
+
+	customResourcesUsage = "(optional) path to a YAML file with custom resources that create pods, e.g. Knative Services, together with paths to labels of their pods"
 )
 
+// ClusterStateConfig configures how the cluster state is fetched from the API server.
+type ClusterStateConfig struct {
+	Workers          int
+	ClusterWideLists bool
+	PartialResults   bool
+	Timeout          time.Duration
+	QPS              float64
+	Burst            int
+	Retries          int
+	RetryBackoff     time.Duration
+	// CustomResources is a path to a file with definitions of custom resources that create pods.
+	CustomResources string
+}
+
+func (c *ClusterStateConfig) AddFlags(fs *pflag.FlagSet) {
+	fs.IntVar(&c.Workers, "workers", defaultWorkers, "maximum number of concurrent requests sent to the API server while fetching cluster state")
+	fs.BoolVar(&c.ClusterWideLists, "cluster-wide-lists", true, "list resources from all namespaces with a single call. Falls back to per-namespace calls for resources that cannot be listed cluster-wide")
+	fs.BoolVar(&c.PartialResults, "partial-results", false, "do not fail when some resources cannot be listed, e.g. because of missing RBAC permissions. Such resources are reported as coverage gaps")
+	fs.DurationVar(&c.Timeout, "timeout", defaultTimeout, "maximum time for fetching cluster state")
+	fs.Float64Var(&c.QPS, "qps", defaultQPS, "maximum queries per second sent to the API server")
+	fs.IntVar(&c.Burst, "burst", defaultBurst, "maximum burst of queries sent to the API server")
+	fs.IntVar(&c.Retries, "retries", defaultRetries, "number of retries of calls failed with transient errors, like throttling, server errors or connection resets")
+	fs.DurationVar(&c.RetryBackoff, "retry-backoff", defaultRetryBackoff, "initial delay before retrying a failed call. It doubles with every retry")
+	fs.StringVar(&c.CustomResources, "custom-resources", "", customResourcesUsage)
+}
+
+func (c ClusterStateConfig) Validate() error {
+	if c.Workers < 1 {
+		return fmt.Errorf("invalid value for workers parameter. It has to be greater than 0")
+	}
+
+	if c.Timeout <= 0 {
+		return fmt.Errorf("invalid value for timeout parameter. It has to be greater than 0")
+	}
+
+	if c.QPS <= 0 || c.Burst < 1 {
+		return fmt.Errorf("invalid value for qps or burst parameter. Both have to be greater than 0")
+	}
+
+	if c.Retries < 0 || c.RetryBackoff < 0 {
+		return fmt.Errorf("invalid value for retries or retry-backoff parameter. Both cannot be negative")
+	}
+
+	return nil
+}
+
+// Config configures the lint command.
 type Config struct {
-	Output     string
-	Kubeconfig string
+	ClusterStateConfig
+	Output   string
+	Contexts []string
+	// Namespaces, NamespaceSelector and ExcludeNamespaces narrow down namespaces whose network policies are validated.
+	Namespaces        []string
+	NamespaceSelector string
+	ExcludeNamespaces []string
+	Watch             bool
+	Debounce          time.Duration
+	MetricsAddress    string
+	Events            bool
+	Annotate          bool
+}
+
+func (c *Config) AddFlags(fs *pflag.FlagSet) {
+	c.ClusterStateConfig.AddFlags(fs)
+	fs.StringVarP(&c.Output, "output", "o", OutputConsole, fmt.Sprintf("output type. Possible values: [%s, %s]", OutputConsole, OutputMarkdown))
+	fs.StringSliceVar(&c.Contexts, "contexts", nil, fmt.Sprintf("(optional) comma-separated list of kubeconfig contexts to validate in one run, or '%s' for every context", kubeconfig.AllContexts))
+	fs.StringSliceVar(&c.Namespaces, "namespaces", nil, "(optional) comma-separated list of namespaces whose network policies are validated, in addition to the one set with -n")
+	fs.StringVar(&c.NamespaceSelector, "namespace-selector", "", "(optional) label selector of namespaces whose network policies are validated, e.g. team=orders")
+	fs.StringSliceVar(&c.ExcludeNamespaces, "exclude-namespaces", nil, "(optional) comma-separated list of globs of namespaces whose network policies are not validated, e.g. kube-*")
+	fs.BoolVar(&c.Watch, "watch", false, "keep running and validate the cluster again whenever relevant resources change")
+	fs.DurationVar(&c.Debounce, "debounce", defaultDebounce, "in watch mode, how long to collect changes before validating the cluster again")
+	fs.StringVar(&c.MetricsAddress, "metrics-address", "", "(optional) in watch mode, address on which Prometheus metrics are served under /metrics, e.g. :9090")
+	fs.BoolVar(&c.Events, "events", false, "in watch mode, record a Kubernetes Event on the network policy for every new violation")
+	fs.BoolVar(&c.Annotate, "annotate", false, "in watch mode, keep a summary of violations in an annotation on every offending network policy")
 }
 
 func (c Config) Validate() error {
-	switch c.Output {
+	if err := ValidateOutput(c.Output); err != nil {
+		return err
+	}
+
+	if err := c.ClusterStateConfig.Validate(); err != nil {
+		return err
+	}
+
+	if c.Watch && len(c.Contexts) > 1 {
+		return fmt.Errorf("watch mode supports a single cluster only")
+	}
+
+	if c.Watch && c.Debounce < 0 {
+		return fmt.Errorf("invalid value for debounce parameter. It cannot be negative")
+	}
+
+	return nil
+}
+
+func ValidateOutput(output string) error {
+	switch output {
 	case OutputConsole, OutputMarkdown:
+		return nil
 	default:
 		return fmt.Errorf("invalid value for output parameter. Supported values: [%s, %s]", OutputConsole, OutputMarkdown)
 	}
+}
+
+// ValidateFixOutput validates the output type of the fix command.
+func ValidateFixOutput(output string) error {
+	switch output {
+	case OutputYAML, OutputJSONPatch:
+		return nil
+	default:
+		return fmt.Errorf("invalid value for output parameter. Supported values: [%s, %s]", OutputYAML, OutputJSONPatch)
+	}
+}
+
+// WebhookConfig configures the webhook command, which serves a ValidatingAdmissionWebhook for network policies.
+type WebhookConfig struct {
+	QPS         float64
+	Burst       int
+	Address     string
+	TLSCertFile string
+	TLSKeyFile  string
+	// CustomResources is a path to a file with definitions of custom resources that create pods.
+	CustomResources string
+	// DenySeverity is the lowest severity of violations that deny admission. Empty value means that violations never deny it.
+	DenySeverity model.Severity
+}
+
+func (c *WebhookConfig) AddFlags(fs *pflag.FlagSet) {
+	c.DenySeverity = model.SeverityError
+	fs.Float64Var(&c.QPS, "qps", defaultQPS, "maximum queries per second sent to the API server")
+	fs.IntVar(&c.Burst, "burst", defaultBurst, "maximum burst of queries sent to the API server")
+	fs.StringVar(&c.Address, "address", defaultWebhookAddress, "address on which admission reviews are served under /validate")
+	fs.StringVar(&c.TLSCertFile, "tls-cert-file", "", "path to the x509 certificate for HTTPS")
+	fs.StringVar(&c.TLSKeyFile, "tls-key-file", "", "path to the x509 private key matching tls-cert-file")
+	fs.StringVar(&c.CustomResources, "custom-resources", "", customResourcesUsage)
+	fs.Var(&denySeverityValue{target: &c.DenySeverity}, "deny-severity", fmt.Sprintf("lowest severity of violations that deny admission, other violations are returned as warnings. Possible values: [%s, %s, %s]", model.SeverityError, model.SeverityWarning, DenySeverityNone))
+}
+
+func (c WebhookConfig) Validate() error {
+	if c.TLSCertFile == "" || c.TLSKeyFile == "" {
+		return fmt.Errorf("tls-cert-file and tls-key-file parameters are required")
+	}
 
-	if c.Kubeconfig == "" {
-		return fmt.Errorf("missing kubeconfig")
+	if c.QPS <= 0 || c.Burst < 1 {
+		return fmt.Errorf("invalid value for qps or burst parameter. Both have to be greater than 0")
 	}
 
 	return nil
 }
 
-func Load() (Config, error) {
-	cfg := Config{}
-	flag.StringVar(&cfg.Output, "output", OutputConsole, fmt.Sprintf("output type. Possible values: [%s, %s]", OutputConsole, OutputMarkdown))
+// denySeverityValue parses the deny-severity flag, DenySeverityNone is stored as empty severity.
+type denySeverityValue struct {
+	target *model.Severity
+}
 
-	if home := homedir.HomeDir(); home != "" {
-		flag.StringVar(&cfg.Kubeconfig, "kubeconfig", filepath.Join(home, ".kube", "config"), "(optional) absolute path to the kubeconfig file")
-	} else {
-		flag.StringVar(&cfg.Kubeconfig, "kubeconfig", "", "absolute path to the kubeconfig file")
+func (v *denySeverityValue) String() string {
+	if v.target == nil || *v.target == "" {
+		return DenySeverityNone
 	}
-	flag.Parse()
-	if err := cfg.Validate(); err != nil {
-		return Config{}, err
+	return string(*v.target)
+}
+
+func (v *denySeverityValue) Set(value string) error {
+	switch value {
+	case string(model.SeverityError), string(model.SeverityWarning):
+		*v.target = model.Severity(value)
+	case DenySeverityNone:
+		*v.target = ""
+	default:
+		return fmt.Errorf("supported values: [%s, %s, %s]", model.SeverityError, model.SeverityWarning, DenySeverityNone)
 	}
-	return cfg, nil
+	return nil
+}
+
+func (v *denySeverityValue) Type() string {
+	return "severity"
 }
@@ -0,0 +1,382 @@
+package connectivity
+
+import (
+	"fmt"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+	netv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	"github.com/aszecowka/netpolvalidator/internal/model"
+)
+
+// allPorts describes all ports of a protocol, e.g. TCP/all.
+const allPorts = "all"
+
+// Endpoint is a pod candidate together with the namespace it runs in.
+type Endpoint struct {
+	Namespace v1.Namespace
+	Candidate model.PodCandidate
+}
+
+// Port narrows the check to traffic on a given port. Nil Port means traffic on any port.
+type Port struct {
+	Protocol v1.Protocol
+	Number   int32
+}
+
+func (p *Port) String() string {
+	if p == nil {
+		return "any port"
+	}
+	return fmt.Sprintf("%s/%d", p.Protocol, p.Number)
+}
+
+// Direction tells which network policies decided about traffic in one direction.
+type Direction struct {
+	// Selecting holds names of network policies of this direction that select the pod. When there are none,
+	// the pod is not isolated and all traffic is allowed.
+	Selecting []string
+	// Allowing holds names of selecting network policies with a rule that allows the traffic.
+	Allowing []string
+	// PartiallyAllowing holds names of selecting network policies with a rule that allows the traffic on some ports only.
+	// It is filled only when the check is not narrowed down to a single port and no policy allows the traffic on all ports.
+	PartiallyAllowing []string
+	// Ports holds ports that PartiallyAllowing policies allow the traffic on, e.g. TCP/8080.
+	Ports []string
+}
+
+func (d Direction) Allowed() bool {
+	return len(d.Selecting) == 0 || len(d.Allowing) > 0
+}
+
+// PartiallyAllowed reports whether the traffic is allowed on some ports only.
+func (d Direction) PartiallyAllowed() bool {
+	return !d.Allowed() && len(d.PartiallyAllowing) > 0
+}
+
+// Verdict tells whether traffic from one endpoint to another is allowed by network policies.
+type Verdict struct {
+	Egress  Direction
+	Ingress Direction
+}
+
+func (v Verdict) Allowed() bool {
+	return v.Egress.Allowed() && v.Ingress.Allowed()
+}
+
+// PartiallyAllowed reports whether the traffic is not allowed on all ports, but both directions allow it on some
+// common ports, see Ports.
+func (v Verdict) PartiallyAllowed() bool {
+	return !v.Allowed() && len(v.Ports()) > 0
+}
+
+// Ports returns ports on which both directions allow the traffic, when at least one of them allows it on some ports only.
+// A direction allowing all ports of a protocol, e.g. TCP/all, keeps ports of that protocol allowed by the other one.
+// Named ports are compared by name, as ports of containers are not part of the cluster state.
+func (v Verdict) Ports() []string {
+	egress, ingress := v.Egress, v.Ingress
+	switch {
+	case v.Allowed():
+		return nil
+	case egress.Allowed() && ingress.PartiallyAllowed():
+		return ingress.Ports
+	case ingress.Allowed() && egress.PartiallyAllowed():
+		return egress.Ports
+	case !egress.PartiallyAllowed() || !ingress.PartiallyAllowed():
+		return nil
+	}
+	var out []string
+	for _, e := range egress.Ports {
+		for _, i := range ingress.Ports {
+			if common, ok := intersectPorts(e, i); ok {
+				out = appendUnique(out, common)
+			}
+		}
+	}
+	return out
+}
+
+// intersectPorts returns the port both descriptions, in the format of describePorts, refer to.
+func intersectPorts(a, b string) (string, bool) {
+	protocolA, portA := splitPort(a)
+	protocolB, portB := splitPort(b)
+	switch {
+	case protocolA != protocolB:
+		return "", false
+	case portA == allPorts:
+		return b, true
+	case portB == allPorts, portA == portB:
+		return a, true
+	default:
+		return "", false
+	}
+}
+
+func splitPort(description string) (protocol, port string) {
+	parts := strings.SplitN(description, "/", 2)
+	if len(parts) < 2 {
+		return parts[0], ""
+	}
+	return parts[0], parts[1]
+}
+
+// Check evaluates network policies from the cluster state for traffic from one endpoint to another.
+// Traffic has to be allowed by egress policies of the source and by ingress policies of the destination.
+// IP blocks are not evaluated, because pod IPs are not part of the cluster state.
+func Check(state model.ClusterState, from, to Endpoint, port *Port) (Verdict, error) {
+	egress, err := checkDirection(state.NetworkPolicies[from.Namespace.Name], netv1.PolicyTypeEgress, from, to, port)
+	if err != nil {
+		return Verdict{}, err
+	}
+	ingress, err := checkDirection(state.NetworkPolicies[to.Namespace.Name], netv1.PolicyTypeIngress, to, from, port)
+	if err != nil {
+		return Verdict{}, err
+	}
+	return Verdict{Egress: egress, Ingress: ingress}, nil
+}
+
+// Endpoints returns pod candidates from the given namespaces, or from all namespaces when none are given.
+func Endpoints(state model.ClusterState, namespaces ...string) []Endpoint {
+	wanted := make(map[string]struct{}, len(namespaces))
+	for _, ns := range namespaces {
+		wanted[ns] = struct{}{}
+	}
+	var out []Endpoint
+	for _, ns := range state.Namespaces {
+		if _, found := wanted[ns.Name]; len(wanted) > 0 && !found {
+			continue
+		}
+		for _, pc := range state.PodCandidates[ns.Name] {
+			out = append(out, Endpoint{Namespace: ns, Candidate: pc})
+		}
+	}
+	return out
+}
+
+// FindEndpoint returns the pod candidate with the given owner name, e.g. deployment/orders/a.
+func FindEndpoint(state model.ClusterState, ownerName string) (Endpoint, bool) {
+	for _, e := range Endpoints(state) {
+		if e.Candidate.OwnerName() == ownerName {
+			return e, true
+		}
+	}
+	return Endpoint{}, false
+}
+
+// Selects reports whether the network policy selects pods of the endpoint.
+func Selects(np netv1.NetworkPolicy, e Endpoint) (bool, error) {
+	if np.Namespace != e.Namespace.Name {
+		return false, nil
+	}
+	return candidateMatchesSelector(&np.Spec.PodSelector, e.Candidate)
+}
+
+// PeerMatches reports whether the peer of the network policy rule matches pods of the endpoint.
+func PeerMatches(np netv1.NetworkPolicy, peer netv1.NetworkPolicyPeer, e Endpoint) (bool, error) {
+	if peer.PodSelector == nil && peer.NamespaceSelector == nil {
+		return false, nil
+	}
+	if peer.NamespaceSelector == nil {
+		if np.Namespace != e.Namespace.Name {
+			return false, nil
+		}
+	} else {
+		matches, err := matchesSelector(peer.NamespaceSelector, e.Namespace.Labels)
+		if err != nil || !matches {
+			return false, err
+		}
+	}
+	if peer.PodSelector == nil {
+		return true, nil
+	}
+	return candidateMatchesSelector(peer.PodSelector, e.Candidate)
+}
+
+// PolicyTypes returns policy types of the network policy with defaults applied.
+func PolicyTypes(np netv1.NetworkPolicy) []netv1.PolicyType {
+	if len(np.Spec.PolicyTypes) > 0 {
+		return np.Spec.PolicyTypes
+	}
+	out := []netv1.PolicyType{netv1.PolicyTypeIngress}
+	if len(np.Spec.Egress) > 0 {
+		out = append(out, netv1.PolicyTypeEgress)
+	}
+	return out
+}
+
+// DescribePorts returns a short description of the ports of a network policy rule.
+func DescribePorts(ports []netv1.NetworkPolicyPort) string {
+	if len(ports) == 0 {
+		return "all ports"
+	}
+	return strings.Join(describePorts(ports), ", ")
+}
+
+func describePorts(ports []netv1.NetworkPolicyPort) []string {
+	var out []string
+	for _, p := range ports {
+		protocol := v1.ProtocolTCP
+		if p.Protocol != nil {
+			protocol = *p.Protocol
+		}
+		if p.Port == nil {
+			out = append(out, fmt.Sprintf("%s/%s", protocol, allPorts))
+			continue
+		}
+		out = append(out, fmt.Sprintf("%s/%s", protocol, p.Port.String()))
+	}
+	return out
+}
+
+func checkDirection(policies []netv1.NetworkPolicy, policyType netv1.PolicyType, pod, peer Endpoint, port *Port) (Direction, error) {
+	out := Direction{}
+	for _, np := range policies {
+		if !hasPolicyType(np, policyType) {
+			continue
+		}
+		selects, err := Selects(np, pod)
+		if err != nil {
+			return Direction{}, err
+		}
+		if !selects {
+			continue
+		}
+		out.Selecting = append(out.Selecting, np.Name)
+
+		allowsAll, allowedPorts, err := allows(np, policyType, peer, port)
+		if err != nil {
+			return Direction{}, err
+		}
+		switch {
+		case allowsAll:
+			out.Allowing = append(out.Allowing, np.Name)
+		case len(allowedPorts) > 0:
+			out.PartiallyAllowing = append(out.PartiallyAllowing, np.Name)
+			out.Ports = appendUnique(out.Ports, allowedPorts...)
+		}
+	}
+	if len(out.Allowing) > 0 {
+		out.PartiallyAllowing, out.Ports = nil, nil
+	}
+	return out, nil
+}
+
+// allows reports whether a rule of the network policy allows traffic with the peer on the port. When port is nil,
+// rules restricted to some ports do not allow all traffic, so their ports are returned instead.
+func allows(np netv1.NetworkPolicy, policyType netv1.PolicyType, peer Endpoint, port *Port) (bool, []string, error) {
+	type rule struct {
+		peers []netv1.NetworkPolicyPeer
+		ports []netv1.NetworkPolicyPort
+	}
+	var rules []rule
+	if policyType == netv1.PolicyTypeIngress {
+		for _, r := range np.Spec.Ingress {
+			rules = append(rules, rule{peers: r.From, ports: r.Ports})
+		}
+	} else {
+		for _, r := range np.Spec.Egress {
+			rules = append(rules, rule{peers: r.To, ports: r.Ports})
+		}
+	}
+
+	var allowedPorts []string
+	for _, r := range rules {
+		if !portsMatch(r.ports, port) {
+			continue
+		}
+		matches, err := peersMatch(np, r.peers, peer)
+		if err != nil {
+			return false, nil, err
+		}
+		if !matches {
+			continue
+		}
+		if port != nil || len(r.ports) == 0 {
+			return true, nil, nil
+		}
+		allowedPorts = appendUnique(allowedPorts, describePorts(r.ports)...)
+	}
+	return false, allowedPorts, nil
+}
+
+func peersMatch(np netv1.NetworkPolicy, peers []netv1.NetworkPolicyPeer, peer Endpoint) (bool, error) {
+	// empty list of peers matches all sources or destinations
+	if len(peers) == 0 {
+		return true, nil
+	}
+	for _, p := range peers {
+		matches, err := PeerMatches(np, p, peer)
+		if err != nil {
+			return false, err
+		}
+		if matches {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func appendUnique(list []string, items ...string) []string {
+	for _, item := range items {
+		found := false
+		for _, existing := range list {
+			if existing == item {
+				found = true
+				break
+			}
+		}
+		if !found {
+			list = append(list, item)
+		}
+	}
+	return list
+}
+
+func portsMatch(ports []netv1.NetworkPolicyPort, port *Port) bool {
+	if len(ports) == 0 || port == nil {
+		return true
+	}
+	for _, p := range ports {
+		protocol := v1.ProtocolTCP
+		if p.Protocol != nil {
+			protocol = *p.Protocol
+		}
+		if protocol != port.Protocol {
+			continue
+		}
+		// named ports cannot be resolved without container specs, so they are assumed to match
+		if p.Port == nil || p.Port.Type == intstr.String || p.Port.IntVal == port.Number {
+			return true
+		}
+	}
+	return false
+}
+
+func hasPolicyType(np netv1.NetworkPolicy, policyType netv1.PolicyType) bool {
+	for _, t := range PolicyTypes(np) {
+		if t == policyType {
+			return true
+		}
+	}
+	return false
+}
+
+func matchesSelector(selector *metav1.LabelSelector, set map[string]string) (bool, error) {
+	s, err := metav1.LabelSelectorAsSelector(selector)
+	if err != nil {
+		return false, fmt.Errorf("while creating label selector: %w", err)
+	}
+	return s.Matches(labels.Set(set)), nil
+}
+
+func candidateMatchesSelector(selector *metav1.LabelSelector, pc model.PodCandidate) (bool, error) {
+	s, err := metav1.LabelSelectorAsSelector(selector)
+	if err != nil {
+		return false, fmt.Errorf("while creating label selector: %w", err)
+	}
+	return pc.Matches(s), nil
+}
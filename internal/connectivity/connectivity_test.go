@@ -0,0 +1,183 @@
+package connectivity_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	netv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	"github.com/aszecowka/netpolvalidator/internal/connectivity"
+	"github.com/aszecowka/netpolvalidator/internal/model"
+)
+
+func TestCheck(t *testing.T) {
+	fixPort := intstr.FromInt(8080)
+	allowFromFrontend := fixNetPol("orders", "allow-from-frontend", map[string]string{"app": "a"})
+	allowFromFrontend.Spec.Ingress = []netv1.NetworkPolicyIngressRule{{
+		From: []netv1.NetworkPolicyPeer{{
+			NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"name": "frontend"}},
+			PodSelector:       &metav1.LabelSelector{MatchLabels: map[string]string{"app": "web"}},
+		}},
+		Ports: []netv1.NetworkPolicyPort{{Port: &fixPort}},
+	}}
+	denyEgress := fixNetPol("frontend", "deny-egress", map[string]string{})
+	denyEgress.Spec.PolicyTypes = []netv1.PolicyType{netv1.PolicyTypeEgress}
+	otherPort := intstr.FromInt(9090)
+	egressToOtherPort := fixNetPol("frontend", "egress-to-other-port", map[string]string{})
+	egressToOtherPort.Spec.PolicyTypes = []netv1.PolicyType{netv1.PolicyTypeEgress}
+	egressToOtherPort.Spec.Egress = []netv1.NetworkPolicyEgressRule{{Ports: []netv1.NetworkPolicyPort{{Port: &otherPort}}}}
+	egressToBothPorts := egressToOtherPort.DeepCopy()
+	egressToBothPorts.Name = "egress-to-both-ports"
+	egressToBothPorts.Spec.Egress[0].Ports = append(egressToBothPorts.Spec.Egress[0].Ports, netv1.NetworkPolicyPort{Port: &fixPort})
+
+	testCases := map[string]struct {
+		givenPolicies            map[string][]netv1.NetworkPolicy
+		givenPort                *connectivity.Port
+		expected                 connectivity.Verdict
+		expectedPartiallyAllowed bool
+		expectedPorts            []string
+	}{
+		"allowed without network policies": {
+			expected: connectivity.Verdict{},
+		},
+		"allowed by ingress rule": {
+			givenPolicies: map[string][]netv1.NetworkPolicy{"orders": {allowFromFrontend}},
+			givenPort:     &connectivity.Port{Protocol: v1.ProtocolTCP, Number: 8080},
+			expected: connectivity.Verdict{Ingress: connectivity.Direction{
+				Selecting: []string{"allow-from-frontend"},
+				Allowing:  []string{"allow-from-frontend"},
+			}},
+		},
+		"denied on other port": {
+			givenPolicies: map[string][]netv1.NetworkPolicy{"orders": {allowFromFrontend}},
+			givenPort:     &connectivity.Port{Protocol: v1.ProtocolTCP, Number: 9090},
+			expected: connectivity.Verdict{Ingress: connectivity.Direction{
+				Selecting: []string{"allow-from-frontend"},
+			}},
+		},
+		"partially allowed on any port by rule restricted to some ports": {
+			givenPolicies: map[string][]netv1.NetworkPolicy{"orders": {allowFromFrontend}},
+			expected: connectivity.Verdict{Ingress: connectivity.Direction{
+				Selecting:         []string{"allow-from-frontend"},
+				PartiallyAllowing: []string{"allow-from-frontend"},
+				Ports:             []string{"TCP/8080"},
+			}},
+			expectedPartiallyAllowed: true,
+			expectedPorts:            []string{"TCP/8080"},
+		},
+		"partially allowed on ports allowed in both directions": {
+			givenPolicies: map[string][]netv1.NetworkPolicy{"orders": {allowFromFrontend}, "frontend": {*egressToBothPorts}},
+			expected: connectivity.Verdict{
+				Egress: connectivity.Direction{
+					Selecting:         []string{"egress-to-both-ports"},
+					PartiallyAllowing: []string{"egress-to-both-ports"},
+					Ports:             []string{"TCP/9090", "TCP/8080"},
+				},
+				Ingress: connectivity.Direction{
+					Selecting:         []string{"allow-from-frontend"},
+					PartiallyAllowing: []string{"allow-from-frontend"},
+					Ports:             []string{"TCP/8080"},
+				},
+			},
+			expectedPartiallyAllowed: true,
+			expectedPorts:            []string{"TCP/8080"},
+		},
+		"denied when directions allow different ports": {
+			givenPolicies: map[string][]netv1.NetworkPolicy{"orders": {allowFromFrontend}, "frontend": {egressToOtherPort}},
+			expected: connectivity.Verdict{
+				Egress: connectivity.Direction{
+					Selecting:         []string{"egress-to-other-port"},
+					PartiallyAllowing: []string{"egress-to-other-port"},
+					Ports:             []string{"TCP/9090"},
+				},
+				Ingress: connectivity.Direction{
+					Selecting:         []string{"allow-from-frontend"},
+					PartiallyAllowing: []string{"allow-from-frontend"},
+					Ports:             []string{"TCP/8080"},
+				},
+			},
+		},
+		"denied by egress isolation": {
+			givenPolicies: map[string][]netv1.NetworkPolicy{"orders": {allowFromFrontend}, "frontend": {denyEgress}},
+			expected: connectivity.Verdict{
+				Egress: connectivity.Direction{Selecting: []string{"deny-egress"}},
+				Ingress: connectivity.Direction{
+					Selecting:         []string{"allow-from-frontend"},
+					PartiallyAllowing: []string{"allow-from-frontend"},
+					Ports:             []string{"TCP/8080"},
+				},
+			},
+		},
+	}
+
+	for tn, tc := range testCases {
+		t.Run(tn, func(t *testing.T) {
+			// GIVEN
+			givenState := fixState()
+			givenState.NetworkPolicies = tc.givenPolicies
+			from, found := connectivity.FindEndpoint(givenState, "deployment/frontend/web")
+			require.True(t, found)
+			to, found := connectivity.FindEndpoint(givenState, "deployment/orders/a")
+			require.True(t, found)
+			// WHEN
+			actual, err := connectivity.Check(givenState, from, to, tc.givenPort)
+			// THEN
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, actual)
+			assert.Equal(t, tc.expected.Allowed(), actual.Allowed())
+			assert.Equal(t, tc.expectedPartiallyAllowed, actual.PartiallyAllowed())
+			assert.Equal(t, tc.expectedPorts, actual.Ports())
+		})
+	}
+}
+
+func TestExplain(t *testing.T) {
+	// GIVEN
+	givenNetPol := fixNetPol("orders", "allow-from-frontend", map[string]string{"app": "a"})
+	givenNetPol.Spec.Ingress = []netv1.NetworkPolicyIngressRule{{
+		From: []netv1.NetworkPolicyPeer{
+			{NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"name": "frontend"}}},
+			{PodSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "b"}}},
+		},
+	}}
+	// WHEN
+	actual, err := connectivity.Explain(fixState(), givenNetPol)
+	// THEN
+	require.NoError(t, err)
+	assert.Equal(t, []netv1.PolicyType{netv1.PolicyTypeIngress}, actual.PolicyTypes)
+	assert.Equal(t, []string{"deployment/orders/a"}, actual.Selected)
+	assert.Empty(t, actual.Egress)
+	assert.Equal(t, []connectivity.RuleExplanation{{
+		Ports: "all ports",
+		Peers: []connectivity.PeerExplanation{
+			{Description: "all pods in namespaces [name=frontend]", Matching: []string{"deployment/frontend/web"}},
+			{Description: "pods [app=b] in namespace orders"},
+		},
+	}}, actual.Ingress)
+}
+
+func fixState() model.ClusterState {
+	return model.ClusterState{
+		Namespaces: []v1.Namespace{
+			{ObjectMeta: metav1.ObjectMeta{Name: "frontend", Labels: map[string]string{"name": "frontend"}}},
+			{ObjectMeta: metav1.ObjectMeta{Name: "orders", Labels: map[string]string{"name": "orders"}}},
+		},
+		PodCandidates: map[string][]model.PodCandidate{
+			"frontend": {{Owner: model.Owner{Kind: "deployment", Namespace: "frontend", Name: "web"}, Labels: map[string]string{"app": "web"}}},
+			"orders":   {{Owner: model.Owner{Kind: "deployment", Namespace: "orders", Name: "a"}, Labels: map[string]string{"app": "a"}}},
+		},
+	}
+}
+
+func fixNetPol(namespace, name string, podSelector map[string]string) netv1.NetworkPolicy {
+	return netv1.NetworkPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+		Spec: netv1.NetworkPolicySpec{
+			PodSelector: metav1.LabelSelector{MatchLabels: podSelector},
+		},
+	}
+}
@@ -0,0 +1,103 @@
+package connectivity
+
+import (
+	"fmt"
+	"strings"
+
+	netv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/aszecowka/netpolvalidator/internal/model"
+)
+
+// Explanation describes what a network policy does in terms of pod candidates from the cluster state.
+type Explanation struct {
+	NetworkPolicy netv1.NetworkPolicy
+	PolicyTypes   []netv1.PolicyType
+	// Selected holds owner names of pod candidates selected by the network policy.
+	Selected []string
+	Ingress  []RuleExplanation
+	Egress   []RuleExplanation
+}
+
+type RuleExplanation struct {
+	Ports string
+	// Peers is empty when the rule matches all sources or destinations.
+	Peers []PeerExplanation
+}
+
+type PeerExplanation struct {
+	Description string
+	// Matching holds owner names of pod candidates matched by the peer.
+	Matching []string
+}
+
+// Explain describes which pod candidates the network policy selects and which ones its rules allow.
+func Explain(state model.ClusterState, np netv1.NetworkPolicy) (Explanation, error) {
+	out := Explanation{
+		NetworkPolicy: np,
+		PolicyTypes:   PolicyTypes(np),
+	}
+	endpoints := Endpoints(state)
+	for _, e := range endpoints {
+		selects, err := Selects(np, e)
+		if err != nil {
+			return Explanation{}, fmt.Errorf("while checking pod selector: %w", err)
+		}
+		if selects {
+			out.Selected = append(out.Selected, e.Candidate.OwnerName())
+		}
+	}
+
+	for _, r := range np.Spec.Ingress {
+		explained, err := explainRule(np, r.From, r.Ports, endpoints)
+		if err != nil {
+			return Explanation{}, fmt.Errorf("while explaining ingress rule: %w", err)
+		}
+		out.Ingress = append(out.Ingress, explained)
+	}
+	for _, r := range np.Spec.Egress {
+		explained, err := explainRule(np, r.To, r.Ports, endpoints)
+		if err != nil {
+			return Explanation{}, fmt.Errorf("while explaining egress rule: %w", err)
+		}
+		out.Egress = append(out.Egress, explained)
+	}
+	return out, nil
+}
+
+func explainRule(np netv1.NetworkPolicy, peers []netv1.NetworkPolicyPeer, ports []netv1.NetworkPolicyPort, endpoints []Endpoint) (RuleExplanation, error) {
+	out := RuleExplanation{Ports: DescribePorts(ports)}
+	for _, peer := range peers {
+		explained := PeerExplanation{Description: DescribePeer(np, peer)}
+		for _, e := range endpoints {
+			matches, err := PeerMatches(np, peer, e)
+			if err != nil {
+				return RuleExplanation{}, err
+			}
+			if matches {
+				explained.Matching = append(explained.Matching, e.Candidate.OwnerName())
+			}
+		}
+		out.Peers = append(out.Peers, explained)
+	}
+	return out, nil
+}
+
+// DescribePeer returns a short description of the network policy rule peer.
+func DescribePeer(np netv1.NetworkPolicy, peer netv1.NetworkPolicyPeer) string {
+	if peer.IPBlock != nil {
+		if len(peer.IPBlock.Except) == 0 {
+			return fmt.Sprintf("ip block %s", peer.IPBlock.CIDR)
+		}
+		return fmt.Sprintf("ip block %s except %s", peer.IPBlock.CIDR, strings.Join(peer.IPBlock.Except, ", "))
+	}
+	namespaces := fmt.Sprintf("namespace %s", np.Namespace)
+	if peer.NamespaceSelector != nil {
+		namespaces = fmt.Sprintf("namespaces [%s]", metav1.FormatLabelSelector(peer.NamespaceSelector))
+	}
+	if peer.PodSelector == nil {
+		return fmt.Sprintf("all pods in %s", namespaces)
+	}
+	return fmt.Sprintf("pods [%s] in %s", metav1.FormatLabelSelector(peer.PodSelector), namespaces)
+}
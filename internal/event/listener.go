@@ -0,0 +1,120 @@
+package event
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+	netv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	typednetv1 "k8s.io/client-go/kubernetes/typed/networking/v1"
+	"k8s.io/client-go/tools/record"
+
+	"github.com/aszecowka/netpolvalidator/internal/model"
+	"github.com/aszecowka/netpolvalidator/internal/watch"
+)
+
+// AnnotationViolations holds the summary of violations found for the annotated network policy.
+const AnnotationViolations = "netpolvalidator/violations"
+
+// listener records a Kubernetes Event for every new violation against the offending network policy
+// when recorder is set, and optionally keeps the summary of its violations in the AnnotationViolations annotation.
+type listener struct {
+	recorder record.EventRecorder
+	client   typednetv1.NetworkPoliciesGetter
+	annotate bool
+	logger   *log.Logger
+}
+
+func NewListener(recorder record.EventRecorder, client typednetv1.NetworkPoliciesGetter, annotate bool, logger *log.Logger) *listener {
+	return &listener{
+		recorder: recorder,
+		client:   client,
+		annotate: annotate,
+		logger:   logger,
+	}
+}
+
+func (l *listener) OnScan(scan watch.Scan) {
+	if l.recorder != nil {
+		l.recordEvents(scan)
+	}
+	if l.annotate {
+		l.annotatePolicies(scan)
+	}
+}
+
+func (l *listener) recordEvents(scan watch.Scan) {
+	for _, v := range scan.Added {
+		np, found := findNetworkPolicy(scan.State, v.Namespace, v.NetworkPolicyName)
+		if !found {
+			continue
+		}
+		l.recorder.Event(&np, v1.EventTypeWarning, v.Reason, v.Message)
+	}
+}
+
+func (l *listener) annotatePolicies(scan watch.Scan) {
+	byPolicy := make(map[types.NamespacedName][]model.Violation)
+	for _, v := range scan.Violations {
+		key := types.NamespacedName{Namespace: v.Namespace, Name: v.NetworkPolicyName}
+		byPolicy[key] = append(byPolicy[key], v)
+	}
+	for _, ns := range scan.State.Namespaces {
+		for _, np := range scan.State.NetworkPolicies[ns.Name] {
+			summary := summarize(byPolicy[types.NamespacedName{Namespace: np.Namespace, Name: np.Name}])
+			if err := l.ensureAnnotation(np, summary); err != nil {
+				l.logger.Printf("while annotating network policy %s/%s: %s", np.Namespace, np.Name, err)
+			}
+		}
+	}
+}
+
+// ensureAnnotation sets the summary annotation, or removes it when summary is empty, unless the policy is already up to date.
+func (l *listener) ensureAnnotation(np netv1.NetworkPolicy, summary string) error {
+	current, found := np.Annotations[AnnotationViolations]
+	if current == summary && (found || summary == "") {
+		return nil
+	}
+
+	var value interface{}
+	if summary != "" {
+		value = summary
+	}
+	patch, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"annotations": map[string]interface{}{
+				AnnotationViolations: value,
+			},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("while creating patch: %w", err)
+	}
+	_, err = l.client.NetworkPolicies(np.Namespace).Patch(context.Background(), np.Name, types.MergePatchType, patch, metav1.PatchOptions{})
+	return err
+}
+
+func summarize(violations []model.Violation) string {
+	if len(violations) == 0 {
+		return ""
+	}
+	messages := make([]string, 0, len(violations))
+	for _, v := range violations {
+		messages = append(messages, v.Message)
+	}
+	return fmt.Sprintf("%d violation(s): %s", len(violations), strings.Join(messages, "; "))
+}
+
+func findNetworkPolicy(state model.ClusterState, namespace, name string) (netv1.NetworkPolicy, bool) {
+	for _, np := range state.NetworkPolicies[namespace] {
+		if np.Name == name {
+			return np, true
+		}
+	}
+	return netv1.NetworkPolicy{}, false
+}
@@ -0,0 +1,113 @@
+package event_test
+
+import (
+	"bytes"
+	"context"
+	"log"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	netv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/record"
+
+	"github.com/aszecowka/netpolvalidator/internal/event"
+	"github.com/aszecowka/netpolvalidator/internal/model"
+	"github.com/aszecowka/netpolvalidator/internal/watch"
+)
+
+func TestListener(t *testing.T) {
+	givenViolation := model.Violation{
+		Namespace:         "orders",
+		NetworkPolicyName: "ingress-to-a",
+		Type:              model.ViolationInvalidLabel,
+		Message:           "no pods matching pod selector",
+		Reason:            "NoMatchingPods",
+	}
+
+	t.Run("records events for new violations", func(t *testing.T) {
+		// GIVEN
+		givenNetPol := fixNetPol("ingress-to-a", nil)
+		fakeRecorder := record.NewFakeRecorder(10)
+		sut := event.NewListener(fakeRecorder, fake.NewSimpleClientset().NetworkingV1(), false, log.New(&bytes.Buffer{}, "", 0))
+		// WHEN
+		sut.OnScan(watch.Scan{
+			State:      fixState(givenNetPol),
+			Violations: []model.Violation{givenViolation},
+			Added:      []model.Violation{givenViolation},
+		})
+		// THEN
+		require.Len(t, fakeRecorder.Events, 1)
+		assert.Equal(t, "Warning NoMatchingPods no pods matching pod selector", <-fakeRecorder.Events)
+	})
+
+	t.Run("annotates policies with violations", func(t *testing.T) {
+		// GIVEN
+		givenNetPol := fixNetPol("ingress-to-a", nil)
+		fakeClientset := fake.NewSimpleClientset(&givenNetPol)
+		sut := event.NewListener(record.NewFakeRecorder(10), fakeClientset.NetworkingV1(), true, log.New(&bytes.Buffer{}, "", 0))
+		// WHEN
+		sut.OnScan(watch.Scan{
+			State:      fixState(givenNetPol),
+			Violations: []model.Violation{givenViolation},
+		})
+		// THEN
+		actual, err := fakeClientset.NetworkingV1().NetworkPolicies("orders").Get(context.Background(), "ingress-to-a", metav1.GetOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, "1 violation(s): no pods matching pod selector", actual.Annotations[event.AnnotationViolations])
+	})
+
+	t.Run("removes annotation when violations are resolved", func(t *testing.T) {
+		// GIVEN
+		givenNetPol := fixNetPol("ingress-to-a", map[string]string{
+			event.AnnotationViolations: "1 violation(s): no pods matching pod selector",
+			"team":                     "orders",
+		})
+		fakeClientset := fake.NewSimpleClientset(&givenNetPol)
+		sut := event.NewListener(record.NewFakeRecorder(10), fakeClientset.NetworkingV1(), true, log.New(&bytes.Buffer{}, "", 0))
+		// WHEN
+		sut.OnScan(watch.Scan{
+			State:    fixState(givenNetPol),
+			Resolved: []model.Violation{givenViolation},
+		})
+		// THEN
+		actual, err := fakeClientset.NetworkingV1().NetworkPolicies("orders").Get(context.Background(), "ingress-to-a", metav1.GetOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, map[string]string{"team": "orders"}, actual.Annotations)
+	})
+
+	t.Run("does not patch up to date policies", func(t *testing.T) {
+		// GIVEN
+		givenNetPol := fixNetPol("ingress-to-a", nil)
+		fakeClientset := fake.NewSimpleClientset(&givenNetPol)
+		sut := event.NewListener(record.NewFakeRecorder(10), fakeClientset.NetworkingV1(), true, log.New(&bytes.Buffer{}, "", 0))
+		// WHEN
+		sut.OnScan(watch.Scan{
+			State: fixState(givenNetPol),
+		})
+		// THEN
+		assert.Empty(t, fakeClientset.Actions())
+	})
+}
+
+func fixState(policies ...netv1.NetworkPolicy) model.ClusterState {
+	return model.ClusterState{
+		Namespaces: []v1.Namespace{{ObjectMeta: metav1.ObjectMeta{Name: "orders"}}},
+		NetworkPolicies: map[string][]netv1.NetworkPolicy{
+			"orders": policies,
+		},
+	}
+}
+
+func fixNetPol(name string, annotations map[string]string) netv1.NetworkPolicy {
+	return netv1.NetworkPolicy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        name,
+			Namespace:   "orders",
+			Annotations: annotations,
+		},
+	}
+}
@@ -0,0 +1,151 @@
+package fix
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+	netv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	"github.com/aszecowka/netpolvalidator/internal/model"
+	"github.com/aszecowka/netpolvalidator/internal/rule"
+)
+
+// Change replaces a single field of a network policy.
+type Change struct {
+	// Path is a JSON pointer to the field, e.g. /spec/ingress/0/from/1/podSelector.
+	Path  string
+	Value interface{}
+	// Description tells what was changed and why, e.g. Ingress rule [1:2] pod selector: replaced app=componet-a with app=component-a.
+	Description string
+}
+
+// PolicyFix is a network policy with changes that fix its violations.
+type PolicyFix struct {
+	Original netv1.NetworkPolicy
+	Fixed    netv1.NetworkPolicy
+	Changes  []Change
+}
+
+// Fix returns fixes of violations of network policies from the cluster state that have an unambiguous remedy:
+//   - selectors matching nothing because of a typo, when a single existing label is the closest one,
+//   - namespace selectors with a custom label matching nothing, when a namespace with the selected name exists,
+//   - rules ignored because policyTypes does not include their direction.
+//
+// Network policies without such violations are skipped. Fixes are sorted by namespace and name.
+func Fix(state model.ClusterState) []PolicyFix {
+	var out []PolicyFix
+	for _, policiesForGivenNamespace := range state.NetworkPolicies {
+		for _, np := range policiesForGivenNamespace {
+			f := fixNetworkPolicy(np, state.Namespaces, state.PodCandidates)
+			if len(f.Changes) > 0 {
+				out = append(out, f)
+			}
+		}
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Original.Namespace != out[j].Original.Namespace {
+			return out[i].Original.Namespace < out[j].Original.Namespace
+		}
+		return out[i].Original.Name < out[j].Original.Name
+	})
+	return out
+}
+
+func fixNetworkPolicy(np netv1.NetworkPolicy, namespaces []v1.Namespace, podCandidates map[string][]model.PodCandidate) PolicyFix {
+	f := PolicyFix{Original: np, Fixed: *np.DeepCopy()}
+	spec := &f.Fixed.Spec
+
+	if labelFix, found := rule.SuggestPodSelectorFix(spec.PodSelector, podCandidates[np.Namespace]); found {
+		spec.PodSelector = labelFix.Apply(spec.PodSelector)
+		f.add("/spec/podSelector", spec.PodSelector, fmt.Sprintf("pod selector: replaced %s", labelFix))
+	}
+	for idxIngress := range spec.Ingress {
+		for idxFrom := range spec.Ingress[idxIngress].From {
+			path := fmt.Sprintf("/spec/ingress/%d/from/%d", idxIngress, idxFrom)
+			position := fmt.Sprintf("%s rule [%d:%d]", model.Ingress, idxIngress+1, idxFrom+1)
+			f.fixPeer(&spec.Ingress[idxIngress].From[idxFrom], path, position, namespaces, podCandidates)
+		}
+	}
+	for idxEgress := range spec.Egress {
+		for idxTo := range spec.Egress[idxEgress].To {
+			path := fmt.Sprintf("/spec/egress/%d/to/%d", idxEgress, idxTo)
+			position := fmt.Sprintf("%s rule [%d:%d]", model.Egress, idxEgress+1, idxTo+1)
+			f.fixPeer(&spec.Egress[idxEgress].To[idxTo], path, position, namespaces, podCandidates)
+		}
+	}
+
+	if ignored := rule.IgnoredPolicyTypes(f.Fixed); len(ignored) > 0 {
+		var added []string
+		for _, policyType := range ignored {
+			added = append(added, string(policyType))
+		}
+		spec.PolicyTypes = append(append([]netv1.PolicyType(nil), spec.PolicyTypes...), ignored...)
+		f.add("/spec/policyTypes", spec.PolicyTypes, fmt.Sprintf("policyTypes: added %s, so that their rules are not ignored", strings.Join(added, ", ")))
+	}
+	return f
+}
+
+// fixPeer fixes the namespace selector of the peer first, so that its pod selector is fixed against pods
+// from namespaces the fixed selector matches.
+func (f *PolicyFix) fixPeer(peer *netv1.NetworkPolicyPeer, path, position string, namespaces []v1.Namespace, podCandidates map[string][]model.PodCandidate) {
+	inScope := podCandidates[f.Original.Namespace]
+	if peer.NamespaceSelector != nil {
+		if fixed, description, found := fixNamespaceSelector(*peer.NamespaceSelector, namespaces); found {
+			peer.NamespaceSelector = &fixed
+			f.add(path+"/namespaceSelector", fixed, fmt.Sprintf("%s namespace selector: replaced %s", position, description))
+		}
+		inScope = nil
+		for _, ns := range matchingNamespaces(*peer.NamespaceSelector, namespaces) {
+			inScope = append(inScope, podCandidates[ns.Name]...)
+		}
+	}
+	if peer.PodSelector != nil {
+		if labelFix, found := rule.SuggestPodSelectorFix(*peer.PodSelector, inScope); found {
+			fixed := labelFix.Apply(*peer.PodSelector)
+			peer.PodSelector = &fixed
+			f.add(path+"/podSelector", fixed, fmt.Sprintf("%s pod selector: replaced %s", position, labelFix))
+		}
+	}
+}
+
+// fixNamespaceSelector replaces a single custom label matching no namespaces, e.g. name=payments, with the label
+// Kubernetes sets to the name of the namespace, or fixes a typo in one of the selector's labels.
+func fixNamespaceSelector(selector metav1.LabelSelector, namespaces []v1.Namespace) (metav1.LabelSelector, string, bool) {
+	if len(matchingNamespaces(selector, namespaces)) > 0 {
+		return metav1.LabelSelector{}, "", false
+	}
+	if len(selector.MatchLabels) == 1 && len(selector.MatchExpressions) == 0 {
+		for key, value := range selector.MatchLabels {
+			byName := metav1.LabelSelector{MatchLabels: map[string]string{model.LabelMetadataName: value}}
+			if key != model.LabelMetadataName && len(matchingNamespaces(byName, namespaces)) == 1 {
+				return byName, rule.LabelFix{Key: key, Value: value, NewKey: model.LabelMetadataName, NewValue: value}.String(), true
+			}
+		}
+	}
+	if labelFix, found := rule.SuggestNamespaceSelectorFix(selector, namespaces); found {
+		return labelFix.Apply(selector), labelFix.String(), true
+	}
+	return metav1.LabelSelector{}, "", false
+}
+
+func matchingNamespaces(labelSelector metav1.LabelSelector, namespaces []v1.Namespace) []v1.Namespace {
+	selector, err := metav1.LabelSelectorAsSelector(&labelSelector)
+	if err != nil {
+		return nil
+	}
+	var out []v1.Namespace
+	for _, ns := range namespaces {
+		if selector.Matches(labels.Set(ns.Labels)) {
+			out = append(out, ns)
+		}
+	}
+	return out
+}
+
+func (f *PolicyFix) add(path string, value interface{}, description string) {
+	f.Changes = append(f.Changes, Change{Path: path, Value: value, Description: description})
+}
@@ -0,0 +1,225 @@
+package fix_test
+
+import (
+	"testing"
+
+	"github.com/ghodss/yaml"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	netv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/aszecowka/netpolvalidator/internal/fix"
+	"github.com/aszecowka/netpolvalidator/internal/model"
+)
+
+func TestFix(t *testing.T) {
+	testCases := map[string]struct {
+		givenNetPol          string
+		expectedFixed        string
+		expectedDescriptions []string
+		expectedPaths        []string
+	}{
+		"typo in pod selector": {
+			givenNetPol: `
+metadata:
+  name: web
+  namespace: orders
+spec:
+  podSelector:
+    matchLabels:
+      app: componet-a
+`,
+			expectedFixed: `
+metadata:
+  name: web
+  namespace: orders
+spec:
+  podSelector:
+    matchLabels:
+      app: component-a
+`,
+			expectedDescriptions: []string{"pod selector: replaced app=componet-a with app=component-a"},
+			expectedPaths:        []string{"/spec/podSelector"},
+		},
+		"custom namespace label and typo in pod selector of peer": {
+			givenNetPol: `
+metadata:
+  name: web
+  namespace: orders
+spec:
+  podSelector:
+    matchLabels:
+      app: component-a
+  ingress:
+  - from:
+    - namespaceSelector:
+        matchLabels:
+          name: payments
+      podSelector:
+        matchExpressions:
+        - key: app
+          operator: In
+          values: [payment-api]
+`,
+			expectedFixed: `
+metadata:
+  name: web
+  namespace: orders
+spec:
+  podSelector:
+    matchLabels:
+      app: component-a
+  ingress:
+  - from:
+    - namespaceSelector:
+        matchLabels:
+          kubernetes.io/metadata.name: payments
+      podSelector:
+        matchExpressions:
+        - key: app
+          operator: In
+          values: [payments-api]
+`,
+			expectedDescriptions: []string{
+				"Ingress rule [1:1] namespace selector: replaced name=payments with kubernetes.io/metadata.name=payments",
+				"Ingress rule [1:1] pod selector: replaced app=payment-api with app=payments-api",
+			},
+			expectedPaths: []string{"/spec/ingress/0/from/0/namespaceSelector", "/spec/ingress/0/from/0/podSelector"},
+		},
+		"egress rules ignored because of policy types": {
+			givenNetPol: `
+metadata:
+  name: web
+  namespace: orders
+spec:
+  podSelector:
+    matchLabels:
+      app: component-a
+  policyTypes: [Ingress]
+  egress:
+  - to:
+    - podSelector:
+        matchLabels:
+          app: component-b
+`,
+			expectedFixed: `
+metadata:
+  name: web
+  namespace: orders
+spec:
+  podSelector:
+    matchLabels:
+      app: component-a
+  policyTypes: [Ingress, Egress]
+  egress:
+  - to:
+    - podSelector:
+        matchLabels:
+          app: component-b
+`,
+			expectedDescriptions: []string{"policyTypes: added Egress, so that their rules are not ignored"},
+			expectedPaths:        []string{"/spec/policyTypes"},
+		},
+	}
+
+	for tn, tc := range testCases {
+		t.Run(tn, func(t *testing.T) {
+			// GIVEN
+			givenNetPol := getNetPol(t, tc.givenNetPol)
+			givenState := fixClusterState(givenNetPol)
+			// WHEN
+			actual := fix.Fix(givenState)
+			// THEN
+			require.Len(t, actual, 1)
+			assert.Equal(t, givenNetPol, actual[0].Original)
+			assert.Equal(t, getNetPol(t, tc.expectedFixed), actual[0].Fixed)
+			var actualDescriptions, actualPaths []string
+			for _, c := range actual[0].Changes {
+				actualDescriptions = append(actualDescriptions, c.Description)
+				actualPaths = append(actualPaths, c.Path)
+			}
+			assert.Equal(t, tc.expectedDescriptions, actualDescriptions)
+			assert.Equal(t, tc.expectedPaths, actualPaths)
+		})
+	}
+
+	t.Run("skips ambiguous and correct network policies", func(t *testing.T) {
+		// GIVEN
+		givenState := fixClusterState(
+			getNetPol(t, `
+metadata:
+  name: correct
+  namespace: orders
+spec:
+  podSelector:
+    matchLabels:
+      app: component-a
+  policyTypes: [Ingress]
+`),
+			getNetPol(t, `
+metadata:
+  name: ambiguous
+  namespace: orders
+spec:
+  podSelector:
+    matchLabels:
+      app: component
+`),
+			getNetPol(t, `
+metadata:
+  name: unknown-namespace
+  namespace: orders
+spec:
+  podSelector: {}
+  egress:
+  - to:
+    - namespaceSelector:
+        matchLabels:
+          name: shipping
+`),
+		)
+		// WHEN
+		actual := fix.Fix(givenState)
+		// THEN
+		assert.Empty(t, actual)
+	})
+}
+
+func fixClusterState(policies ...netv1.NetworkPolicy) model.ClusterState {
+	return model.ClusterState{
+		Namespaces: []v1.Namespace{
+			fixNs("orders"),
+			fixNs("payments"),
+		},
+		NetworkPolicies: map[string][]netv1.NetworkPolicy{
+			"orders": policies,
+		},
+		PodCandidates: map[string][]model.PodCandidate{
+			"orders": {
+				{Owner: model.Owner{Kind: "deployment", Namespace: "orders", Name: "component-a"}, Labels: map[string]string{"app": "component-a"}},
+				{Owner: model.Owner{Kind: "deployment", Namespace: "orders", Name: "component-b"}, Labels: map[string]string{"app": "component-b"}},
+			},
+			"payments": {
+				{Owner: model.Owner{Kind: "deployment", Namespace: "payments", Name: "payments-api"}, Labels: map[string]string{"app": "payments-api"}},
+			},
+		},
+	}
+}
+
+func fixNs(name string) v1.Namespace {
+	return v1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   name,
+			Labels: map[string]string{"kubernetes.io/metadata.name": name},
+		},
+	}
+}
+
+func getNetPol(t *testing.T, in string) netv1.NetworkPolicy {
+	np := netv1.NetworkPolicy{}
+	err := yaml.Unmarshal([]byte(in), &np)
+	require.NoError(t, err)
+	return np
+}
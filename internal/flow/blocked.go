@@ -0,0 +1,31 @@
+package flow
+
+import (
+	"fmt"
+
+	"github.com/aszecowka/netpolvalidator/internal/connectivity"
+	"github.com/aszecowka/netpolvalidator/internal/model"
+)
+
+// BlockedConnection is an observed connection that network policies from the cluster state would block.
+type BlockedConnection struct {
+	Connection Connection
+	Verdict    connectivity.Verdict
+}
+
+// FindBlocked returns observed connections that network policies from the cluster state do not allow, in the order
+// of the given connections.
+func FindBlocked(state model.ClusterState, connections []Connection) ([]BlockedConnection, error) {
+	var out []BlockedConnection
+	for _, c := range connections {
+		port := c.Port
+		verdict, err := connectivity.Check(state, c.Source, c.Destination, &port)
+		if err != nil {
+			return nil, fmt.Errorf("while checking connection %s: %w", c, err)
+		}
+		if !verdict.Allowed() {
+			out = append(out, BlockedConnection{Connection: c, Verdict: verdict})
+		}
+	}
+	return out, nil
+}
@@ -0,0 +1,48 @@
+package flow_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aszecowka/netpolvalidator/internal/connectivity"
+	"github.com/aszecowka/netpolvalidator/internal/flow"
+)
+
+func TestFindBlocked(t *testing.T) {
+	// GIVEN
+	givenState := fixClusterState(getNetPol(t, `
+metadata:
+  name: allow-web-8080
+  namespace: payments
+spec:
+  podSelector:
+    matchLabels:
+      app: payments-api
+  ingress:
+  - from:
+    - namespaceSelector:
+        matchLabels:
+          kubernetes.io/metadata.name: orders
+      podSelector:
+        matchLabels:
+          app: web
+    ports:
+    - port: 8080
+`))
+	givenConnections, _ := flow.Resolve(givenState, []flow.Flow{
+		fixFlow("orders", "web-5d4f8b7c9-x2x9z", "payments", "payments-api-7c9d5b6f4-abcde", 8080),
+		fixFlow("orders", "web-5d4f8b7c9-x2x9z", "payments", "payments-api-7c9d5b6f4-abcde", 9090),
+		fixFlow("orders", "web-5d4f8b7c9-x2x9z", "orders", "webhook-server-6b8f9c7d5-q8kzs", 8443),
+	})
+	// WHEN
+	actual, err := flow.FindBlocked(givenState, givenConnections)
+	// THEN
+	require.NoError(t, err)
+	require.Len(t, actual, 1)
+	assert.Equal(t, "deployment/orders/web -> deployment/payments/payments-api on TCP/9090", actual[0].Connection.String())
+	assert.Equal(t, connectivity.Verdict{
+		Ingress: connectivity.Direction{Selecting: []string{"allow-web-8080"}},
+	}, actual[0].Verdict)
+}
@@ -0,0 +1,75 @@
+package flow
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// calicoWorkloadEndpoint is the Calico endpoint type of pods.
+const calicoWorkloadEndpoint = "wep"
+
+type calicoFlowLog struct {
+	SourceNamespace string        `json:"source_namespace"`
+	SourceName      string        `json:"source_name_aggr"`
+	SourceType      string        `json:"source_type"`
+	SourceLabels    *calicoLabels `json:"source_labels"`
+	DestNamespace   string        `json:"dest_namespace"`
+	DestName        string        `json:"dest_name_aggr"`
+	DestType        string        `json:"dest_type"`
+	DestLabels      *calicoLabels `json:"dest_labels"`
+	DestPort        json.Number   `json:"dest_port"`
+	Proto           string        `json:"proto"`
+}
+
+type calicoLabels struct {
+	Labels []string `json:"labels"`
+}
+
+// ParseCalico parses Calico flow logs in JSON format, one log per line. Pod names are usually aggregated by Calico,
+// e.g. web-5d4f8b7c9-*, so endpoints are mapped to workloads by their labels. Logs with an endpoint other than a pod
+// and logs with a protocol network policies do not support are skipped.
+func ParseCalico(r io.Reader) ([]Flow, error) {
+	var out []Flow
+	decoder := json.NewDecoder(r)
+	for idx := 1; ; idx++ {
+		log := calicoFlowLog{}
+		if err := decoder.Decode(&log); err == io.EOF {
+			return out, nil
+		} else if err != nil {
+			return nil, fmt.Errorf("while decoding flow log %d: %w", idx, err)
+		}
+		if log.SourceType != calicoWorkloadEndpoint || log.DestType != calicoWorkloadEndpoint {
+			continue
+		}
+		protocol, ok := parseProtocol(log.Proto)
+		if !ok {
+			continue
+		}
+		port, err := log.DestPort.Int64()
+		if err != nil {
+			return nil, fmt.Errorf("while parsing destination port of flow log %d: %w", idx, err)
+		}
+		out = append(out, Flow{
+			Source:      Endpoint{Namespace: log.SourceNamespace, Pod: log.SourceName, Labels: log.SourceLabels.toMap()},
+			Destination: Endpoint{Namespace: log.DestNamespace, Pod: log.DestName, Labels: log.DestLabels.toMap()},
+			Protocol:    protocol,
+			Port:        int32(port),
+		})
+	}
+}
+
+func (cl *calicoLabels) toMap() map[string]string {
+	if cl == nil || len(cl.Labels) == 0 {
+		return nil
+	}
+	out := make(map[string]string)
+	for _, label := range cl.Labels {
+		kv := strings.SplitN(label, "=", 2)
+		if len(kv) == 2 {
+			out[kv[0]] = kv[1]
+		}
+	}
+	return out
+}
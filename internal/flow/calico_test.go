@@ -0,0 +1,37 @@
+package flow_test
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+
+	"github.com/aszecowka/netpolvalidator/internal/flow"
+)
+
+func TestParseCalico(t *testing.T) {
+	// GIVEN
+	f, err := os.Open("testdata/calico.json")
+	require.NoError(t, err)
+	defer f.Close()
+	// WHEN
+	actual, err := flow.ParseCalico(f)
+	// THEN
+	require.NoError(t, err)
+	assert.Equal(t, []flow.Flow{
+		{
+			Source:      flow.Endpoint{Namespace: "orders", Pod: "web-5d4f8b7c9-*", Labels: map[string]string{"app": "web", "pod-template-hash": "5d4f8b7c9"}},
+			Destination: flow.Endpoint{Namespace: "payments", Pod: "payments-api-7c9d5b6f4-*", Labels: map[string]string{"app": "payments-api"}},
+			Protocol:    v1.ProtocolTCP,
+			Port:        8080,
+		},
+		{
+			Source:      flow.Endpoint{Namespace: "orders", Pod: "web-5d4f8b7c9-*"},
+			Destination: flow.Endpoint{Namespace: "kube-system", Pod: "coredns-558bd4d5db-*"},
+			Protocol:    v1.ProtocolUDP,
+			Port:        53,
+		},
+	}, actual)
+}
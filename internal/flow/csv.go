@@ -0,0 +1,47 @@
+package flow
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+const csvColumns = 6
+
+// ParseCSV parses flows given as src-ns,src-pod,dst-ns,dst-pod,port,proto. A header line is optional.
+// Pods are mapped to workloads by their names, because labels are not given.
+func ParseCSV(r io.Reader) ([]Flow, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = csvColumns
+	reader.TrimLeadingSpace = true
+	reader.Comment = '#'
+
+	var out []Flow
+	for idx := 1; ; idx++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			return out, nil
+		} else if err != nil {
+			return nil, fmt.Errorf("while reading record %d: %w", idx, err)
+		}
+		if idx == 1 && strings.EqualFold(record[0], "src-ns") {
+			continue
+		}
+		port, err := strconv.ParseInt(record[4], 10, 32)
+		if err != nil || port <= 0 {
+			return nil, fmt.Errorf("invalid port %q in record %d", record[4], idx)
+		}
+		protocol, ok := parseProtocol(record[5])
+		if !ok {
+			return nil, fmt.Errorf("unsupported protocol %q in record %d", record[5], idx)
+		}
+		out = append(out, Flow{
+			Source:      Endpoint{Namespace: record[0], Pod: record[1]},
+			Destination: Endpoint{Namespace: record[2], Pod: record[3]},
+			Protocol:    protocol,
+			Port:        int32(port),
+		})
+	}
+}
@@ -0,0 +1,61 @@
+package flow_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+
+	"github.com/aszecowka/netpolvalidator/internal/flow"
+)
+
+func TestParseCSV(t *testing.T) {
+	t.Run("flows with header and comments", func(t *testing.T) {
+		// WHEN
+		actual, err := flow.Load("testdata/flows.csv", flow.FormatCSV)
+		// THEN
+		require.NoError(t, err)
+		assert.Equal(t, []flow.Flow{
+			{
+				Source:      flow.Endpoint{Namespace: "orders", Pod: "web-5d4f8b7c9-x2x9z"},
+				Destination: flow.Endpoint{Namespace: "payments", Pod: "payments-api-7c9d5b6f4-abcde"},
+				Protocol:    v1.ProtocolTCP,
+				Port:        8080,
+			},
+			{
+				Source:      flow.Endpoint{Namespace: "orders", Pod: "web-5d4f8b7c9-x2x9z"},
+				Destination: flow.Endpoint{Namespace: "kube-system", Pod: "coredns-558bd4d5db-q8kzs"},
+				Protocol:    v1.ProtocolUDP,
+				Port:        53,
+			},
+		}, actual)
+	})
+
+	testCases := map[string]struct {
+		given         string
+		expectedError string
+	}{
+		"invalid port": {
+			given:         "orders,web,payments,api,http,TCP",
+			expectedError: `invalid port "http" in record 1`,
+		},
+		"unsupported protocol": {
+			given:         "orders,web,payments,api,8080,ICMP",
+			expectedError: `unsupported protocol "ICMP" in record 1`,
+		},
+		"missing column": {
+			given:         "orders,web,payments,api,8080",
+			expectedError: "while reading record 1: record on line 1: wrong number of fields",
+		},
+	}
+	for tn, tc := range testCases {
+		t.Run(tn, func(t *testing.T) {
+			// WHEN
+			_, err := flow.ParseCSV(strings.NewReader(tc.given))
+			// THEN
+			require.EqualError(t, err, tc.expectedError)
+		})
+	}
+}
@@ -0,0 +1,84 @@
+package flow
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+)
+
+const (
+	FormatHubble = "hubble"
+	FormatCalico = "calico"
+	FormatCSV    = "csv"
+)
+
+// Endpoint is a pod observed in a flow. Labels are empty when the flow export does not include them, e.g. for CSV.
+type Endpoint struct {
+	Namespace string
+	Pod       string
+	Labels    map[string]string
+}
+
+func (e Endpoint) String() string {
+	return fmt.Sprintf("%s/%s", e.Namespace, e.Pod)
+}
+
+// Flow is a connection observed from one pod to another.
+type Flow struct {
+	Source      Endpoint
+	Destination Endpoint
+	Protocol    v1.Protocol
+	Port        int32
+}
+
+// Load reads flows from the file in the given format.
+func Load(path, format string) ([]Flow, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("while opening flows file: %w", err)
+	}
+	defer f.Close()
+
+	var flows []Flow
+	switch format {
+	case FormatHubble:
+		flows, err = ParseHubble(f)
+	case FormatCalico:
+		flows, err = ParseCalico(f)
+	case FormatCSV:
+		flows, err = ParseCSV(f)
+	default:
+		return nil, ValidateFormat(format)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("while parsing flows from %s: %w", path, err)
+	}
+	return flows, nil
+}
+
+// ValidateFormat validates the format of a flows file.
+func ValidateFormat(format string) error {
+	switch format {
+	case FormatHubble, FormatCalico, FormatCSV:
+		return nil
+	default:
+		return fmt.Errorf("invalid value for flow-format parameter. Supported values: [%s, %s, %s]", FormatHubble, FormatCalico, FormatCSV)
+	}
+}
+
+// parseProtocol parses a protocol given by name, e.g. tcp, or by IANA number, e.g. 6.
+// It reports false for protocols network policies do not support, like ICMP.
+func parseProtocol(in string) (v1.Protocol, bool) {
+	switch strings.ToUpper(strings.TrimSpace(in)) {
+	case "TCP", "6":
+		return v1.ProtocolTCP, true
+	case "UDP", "17":
+		return v1.ProtocolUDP, true
+	case "SCTP", "132":
+		return v1.ProtocolSCTP, true
+	default:
+		return "", false
+	}
+}
@@ -0,0 +1,109 @@
+package flow
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+)
+
+// hubbleLabelPrefix marks Kubernetes pod labels among labels of a Cilium identity, e.g. k8s:app=web.
+const hubbleLabelPrefix = "k8s:"
+
+type hubbleRecord struct {
+	Flow *hubbleFlow `json:"flow"`
+}
+
+type hubbleFlow struct {
+	Source      *hubbleEndpoint `json:"source"`
+	Destination *hubbleEndpoint `json:"destination"`
+	L4          *struct {
+		TCP  *hubblePorts `json:"TCP"`
+		UDP  *hubblePorts `json:"UDP"`
+		SCTP *hubblePorts `json:"SCTP"`
+	} `json:"l4"`
+	IsReply *bool `json:"is_reply"`
+}
+
+type hubbleEndpoint struct {
+	Namespace string   `json:"namespace"`
+	PodName   string   `json:"pod_name"`
+	Labels    []string `json:"labels"`
+}
+
+type hubblePorts struct {
+	DestinationPort int32 `json:"destination_port"`
+}
+
+// ParseHubble parses flows exported by `hubble observe -o json`, either wrapped in a "flow" field or not.
+// Replies, flows without L4 ports and flows with an endpoint outside of the cluster are skipped.
+func ParseHubble(r io.Reader) ([]Flow, error) {
+	var out []Flow
+	decoder := json.NewDecoder(r)
+	for idx := 1; ; idx++ {
+		var raw json.RawMessage
+		if err := decoder.Decode(&raw); err == io.EOF {
+			return out, nil
+		} else if err != nil {
+			return nil, fmt.Errorf("while decoding record %d: %w", idx, err)
+		}
+		record := hubbleRecord{}
+		if err := json.Unmarshal(raw, &record); err != nil {
+			return nil, fmt.Errorf("while decoding record %d: %w", idx, err)
+		}
+		if record.Flow == nil {
+			record.Flow = &hubbleFlow{}
+			if err := json.Unmarshal(raw, record.Flow); err != nil {
+				return nil, fmt.Errorf("while decoding record %d: %w", idx, err)
+			}
+		}
+		if f, ok := record.Flow.toFlow(); ok {
+			out = append(out, f)
+		}
+	}
+}
+
+func (hf hubbleFlow) toFlow() (Flow, bool) {
+	if hf.IsReply != nil && *hf.IsReply {
+		return Flow{}, false
+	}
+	if hf.Source == nil || hf.Destination == nil || hf.L4 == nil {
+		return Flow{}, false
+	}
+	f := Flow{Source: hf.Source.toEndpoint(), Destination: hf.Destination.toEndpoint()}
+	if f.Source.Pod == "" || f.Destination.Pod == "" {
+		return Flow{}, false
+	}
+	switch {
+	case hf.L4.TCP != nil:
+		f.Protocol, f.Port = v1.ProtocolTCP, hf.L4.TCP.DestinationPort
+	case hf.L4.UDP != nil:
+		f.Protocol, f.Port = v1.ProtocolUDP, hf.L4.UDP.DestinationPort
+	case hf.L4.SCTP != nil:
+		f.Protocol, f.Port = v1.ProtocolSCTP, hf.L4.SCTP.DestinationPort
+	default:
+		return Flow{}, false
+	}
+	return f, f.Port > 0
+}
+
+// toEndpoint keeps only pod labels, without labels Cilium adds to identities, e.g. io.kubernetes.pod.namespace.
+func (he hubbleEndpoint) toEndpoint() Endpoint {
+	e := Endpoint{Namespace: he.Namespace, Pod: he.PodName}
+	for _, label := range he.Labels {
+		if !strings.HasPrefix(label, hubbleLabelPrefix) {
+			continue
+		}
+		kv := strings.SplitN(strings.TrimPrefix(label, hubbleLabelPrefix), "=", 2)
+		if len(kv) != 2 || kv[0] == "io.kubernetes.pod.namespace" || strings.HasPrefix(kv[0], "io.cilium.k8s.") {
+			continue
+		}
+		if e.Labels == nil {
+			e.Labels = make(map[string]string)
+		}
+		e.Labels[kv[0]] = kv[1]
+	}
+	return e
+}
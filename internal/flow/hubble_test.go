@@ -0,0 +1,47 @@
+package flow_test
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+
+	"github.com/aszecowka/netpolvalidator/internal/flow"
+)
+
+func TestParseHubble(t *testing.T) {
+	t.Run("skips replies, flows without ports and flows outside of the cluster", func(t *testing.T) {
+		// GIVEN
+		f, err := os.Open("testdata/hubble.json")
+		require.NoError(t, err)
+		defer f.Close()
+		// WHEN
+		actual, err := flow.ParseHubble(f)
+		// THEN
+		require.NoError(t, err)
+		assert.Equal(t, []flow.Flow{
+			{
+				Source:      flow.Endpoint{Namespace: "orders", Pod: "web-5d4f8b7c9-x2x9z", Labels: map[string]string{"app": "web"}},
+				Destination: flow.Endpoint{Namespace: "payments", Pod: "payments-api-7c9d5b6f4-abcde", Labels: map[string]string{"app": "payments-api"}},
+				Protocol:    v1.ProtocolTCP,
+				Port:        8080,
+			},
+			{
+				Source:      flow.Endpoint{Namespace: "orders", Pod: "web-5d4f8b7c9-x2x9z", Labels: map[string]string{"app": "web"}},
+				Destination: flow.Endpoint{Namespace: "kube-system", Pod: "coredns-558bd4d5db-q8kzs", Labels: map[string]string{"k8s-app": "kube-dns"}},
+				Protocol:    v1.ProtocolUDP,
+				Port:        53,
+			},
+		}, actual)
+	})
+
+	t.Run("invalid JSON", func(t *testing.T) {
+		// WHEN
+		_, err := flow.ParseHubble(strings.NewReader(`{"flow":`))
+		// THEN
+		require.EqualError(t, err, "while decoding record 1: unexpected EOF")
+	})
+}
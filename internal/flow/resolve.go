@@ -0,0 +1,147 @@
+package flow
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+
+	"github.com/aszecowka/netpolvalidator/internal/connectivity"
+	"github.com/aszecowka/netpolvalidator/internal/model"
+)
+
+// Connection is observed traffic between two pod candidates on a single port. Many flows, e.g. from different pods
+// of the same deployment, result in one connection.
+type Connection struct {
+	Source      connectivity.Endpoint
+	Destination connectivity.Endpoint
+	Port        connectivity.Port
+}
+
+func (c Connection) String() string {
+	return fmt.Sprintf("%s -> %s on %s", c.Source.Candidate.OwnerName(), c.Destination.Candidate.OwnerName(), c.Port.String())
+}
+
+// Unresolved is a flow with an endpoint that does not belong to any pod candidate from the cluster state.
+type Unresolved struct {
+	Flow     Flow
+	Endpoint Endpoint
+}
+
+func (u Unresolved) String() string {
+	return fmt.Sprintf("no workload found for pod %s in flow %s -> %s on %s/%d", u.Endpoint, u.Flow.Source, u.Flow.Destination, u.Flow.Protocol, u.Flow.Port)
+}
+
+// Resolve maps endpoints of flows to pod candidates from the cluster state and returns unique connections sorted by
+// source, destination and port, together with flows that could not be mapped.
+//
+// An endpoint with labels belongs to the pod candidate from its namespace whose labels are the most specific subset of
+// the endpoint labels. Otherwise, it belongs to the pod candidate whose name, or the name of a workload controlling its
+// pods, is the longest prefix of the pod name, e.g. web-5d4f8b7c9-x2x9z belongs to deployment/orders/web.
+func Resolve(state model.ClusterState, flows []Flow) ([]Connection, []Unresolved) {
+	namespaces := make(map[string]v1.Namespace, len(state.Namespaces))
+	for _, ns := range state.Namespaces {
+		namespaces[ns.Name] = ns
+	}
+
+	var connections []Connection
+	var unresolved []Unresolved
+	seen := make(map[string]bool)
+	for _, f := range flows {
+		src, found := resolveEndpoint(namespaces, state.PodCandidates, f.Source)
+		if !found {
+			unresolved = append(unresolved, Unresolved{Flow: f, Endpoint: f.Source})
+			continue
+		}
+		dst, found := resolveEndpoint(namespaces, state.PodCandidates, f.Destination)
+		if !found {
+			unresolved = append(unresolved, Unresolved{Flow: f, Endpoint: f.Destination})
+			continue
+		}
+		c := Connection{Source: src, Destination: dst, Port: connectivity.Port{Protocol: f.Protocol, Number: f.Port}}
+		if key := c.String(); !seen[key] {
+			seen[key] = true
+			connections = append(connections, c)
+		}
+	}
+
+	sort.Slice(connections, func(i, j int) bool {
+		a, b := connections[i], connections[j]
+		if a.Source.Candidate.OwnerName() != b.Source.Candidate.OwnerName() {
+			return a.Source.Candidate.OwnerName() < b.Source.Candidate.OwnerName()
+		}
+		if a.Destination.Candidate.OwnerName() != b.Destination.Candidate.OwnerName() {
+			return a.Destination.Candidate.OwnerName() < b.Destination.Candidate.OwnerName()
+		}
+		return lessPort(a.Port, b.Port)
+	})
+	return connections, unresolved
+}
+
+func resolveEndpoint(namespaces map[string]v1.Namespace, podCandidates map[string][]model.PodCandidate, e Endpoint) (connectivity.Endpoint, bool) {
+	ns, found := namespaces[e.Namespace]
+	if !found {
+		return connectivity.Endpoint{}, false
+	}
+	candidates := podCandidates[e.Namespace]
+	if pc, found := byLabels(candidates, e.Labels); found {
+		return connectivity.Endpoint{Namespace: ns, Candidate: pc}, true
+	}
+	if pc, found := byPodName(candidates, e.Pod); found {
+		return connectivity.Endpoint{Namespace: ns, Candidate: pc}, true
+	}
+	return connectivity.Endpoint{}, false
+}
+
+// byLabels returns the candidate with the most labels, all of which the pod has. A tie is not resolved.
+func byLabels(candidates []model.PodCandidate, podLabels map[string]string) (model.PodCandidate, bool) {
+	var best model.PodCandidate
+	bestCount, ties := 0, 0
+	for _, pc := range candidates {
+		if len(pc.Labels) == 0 || len(pc.Labels) < bestCount || !isSubset(pc.Labels, podLabels) {
+			continue
+		}
+		if len(pc.Labels) == bestCount {
+			ties++
+			continue
+		}
+		best, bestCount, ties = pc, len(pc.Labels), 0
+	}
+	return best, bestCount > 0 && ties == 0
+}
+
+// byPodName returns the candidate with the longest name that the pod name starts with. Names of workloads controlling
+// the pods, e.g. replicaset/orders/web-5d4f8b7c9, are taken into account too.
+func byPodName(candidates []model.PodCandidate, pod string) (model.PodCandidate, bool) {
+	var best model.PodCandidate
+	bestLength := 0
+	for _, pc := range candidates {
+		names := []string{pc.Owner.Name}
+		for _, controller := range pc.ControlledBy {
+			names = append(names, controller[strings.LastIndex(controller, "/")+1:])
+		}
+		for _, name := range names {
+			if len(name) > bestLength && (pod == name || strings.HasPrefix(pod, name+"-")) {
+				best, bestLength = pc, len(name)
+			}
+		}
+	}
+	return best, bestLength > 0
+}
+
+func isSubset(subset, set map[string]string) bool {
+	for key, value := range subset {
+		if actual, found := set[key]; !found || actual != value {
+			return false
+		}
+	}
+	return true
+}
+
+func lessPort(a, b connectivity.Port) bool {
+	if a.Protocol != b.Protocol {
+		return a.Protocol < b.Protocol
+	}
+	return a.Number < b.Number
+}
@@ -0,0 +1,133 @@
+package flow_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	netv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/aszecowka/netpolvalidator/internal/flow"
+	"github.com/aszecowka/netpolvalidator/internal/model"
+)
+
+func TestResolve(t *testing.T) {
+	testCases := map[string]struct {
+		givenSource      flow.Endpoint
+		expectedResolved bool
+	}{
+		"by labels": {
+			givenSource:      flow.Endpoint{Namespace: "orders", Pod: "unknown", Labels: map[string]string{"app": "web", "pod-template-hash": "5d4f8b7c9"}},
+			expectedResolved: true,
+		},
+		"by pod name": {
+			givenSource:      flow.Endpoint{Namespace: "orders", Pod: "web-5d4f8b7c9-x2x9z"},
+			expectedResolved: true,
+		},
+		"by aggregated pod name": {
+			givenSource:      flow.Endpoint{Namespace: "orders", Pod: "web-5d4f8b7c9-*"},
+			expectedResolved: true,
+		},
+		"by name of controlling workload": {
+			givenSource:      flow.Endpoint{Namespace: "orders", Pod: "web-canary-x2x9z"},
+			expectedResolved: true,
+		},
+		"unknown pod": {
+			givenSource: flow.Endpoint{Namespace: "orders", Pod: "webhook-x2x9z"},
+		},
+		"unknown namespace": {
+			givenSource: flow.Endpoint{Namespace: "shipping", Pod: "web-5d4f8b7c9-x2x9z"},
+		},
+	}
+
+	for tn, tc := range testCases {
+		t.Run(tn, func(t *testing.T) {
+			// GIVEN
+			givenFlow := flow.Flow{
+				Source:      tc.givenSource,
+				Destination: flow.Endpoint{Namespace: "payments", Pod: "payments-api-7c9d5b6f4-abcde"},
+				Protocol:    v1.ProtocolTCP,
+				Port:        8080,
+			}
+			// WHEN
+			actualConnections, actualUnresolved := flow.Resolve(fixClusterState(), []flow.Flow{givenFlow})
+			// THEN
+			if !tc.expectedResolved {
+				assert.Empty(t, actualConnections)
+				assert.Equal(t, []flow.Unresolved{{Flow: givenFlow, Endpoint: tc.givenSource}}, actualUnresolved)
+				return
+			}
+			assert.Empty(t, actualUnresolved)
+			var actual []string
+			for _, c := range actualConnections {
+				actual = append(actual, c.String())
+			}
+			assert.Equal(t, []string{"deployment/orders/web -> deployment/payments/payments-api on TCP/8080"}, actual)
+		})
+	}
+
+	t.Run("deduplicates and sorts connections", func(t *testing.T) {
+		// GIVEN
+		webToAPI := func(pod string, port int32) flow.Flow {
+			return flow.Flow{
+				Source:      flow.Endpoint{Namespace: "orders", Pod: pod},
+				Destination: flow.Endpoint{Namespace: "payments", Pod: "payments-api-7c9d5b6f4-abcde"},
+				Protocol:    v1.ProtocolTCP,
+				Port:        port,
+			}
+		}
+		givenFlows := []flow.Flow{
+			webToAPI("web-5d4f8b7c9-x2x9z", 9090),
+			webToAPI("web-5d4f8b7c9-x2x9z", 8080),
+			webToAPI("web-5d4f8b7c9-p7k2m", 8080),
+			webToAPI("checkout-x2x9z", 8080),
+		}
+		// WHEN
+		actualConnections, actualUnresolved := flow.Resolve(fixClusterState(), givenFlows)
+		// THEN
+		assert.Empty(t, actualUnresolved)
+		var actual []string
+		for _, c := range actualConnections {
+			actual = append(actual, c.String())
+		}
+		assert.Equal(t, []string{
+			"deployment/orders/web -> deployment/payments/payments-api on TCP/8080",
+			"deployment/orders/web -> deployment/payments/payments-api on TCP/9090",
+			"pod/orders/checkout-x2x9z -> deployment/payments/payments-api on TCP/8080",
+		}, actual)
+	})
+}
+
+func fixClusterState(policies ...netv1.NetworkPolicy) model.ClusterState {
+	state := model.ClusterState{
+		Namespaces: []v1.Namespace{fixNs("orders"), fixNs("payments")},
+		NetworkPolicies: map[string][]netv1.NetworkPolicy{
+			"orders":   nil,
+			"payments": nil,
+		},
+		PodCandidates: map[string][]model.PodCandidate{
+			"orders": {
+				{Owner: model.Owner{Kind: "deployment", Namespace: "orders", Name: "web"}, Labels: map[string]string{"app": "web"}, ControlledBy: []string{"deployment/orders/web-canary"}},
+				{Owner: model.Owner{Kind: "deployment", Namespace: "orders", Name: "webhook-server"}, Labels: map[string]string{"app": "webhook"}},
+				{Owner: model.Owner{Kind: "pod", Namespace: "orders", Name: "checkout-x2x9z"}},
+			},
+			"payments": {
+				{Owner: model.Owner{Kind: "deployment", Namespace: "payments", Name: "payments-api"}, Labels: map[string]string{"app": "payments-api"}},
+			},
+		},
+	}
+	for _, np := range policies {
+		state.NetworkPolicies[np.Namespace] = append(state.NetworkPolicies[np.Namespace], np)
+	}
+	return state
+}
+
+func fixNs(name string) v1.Namespace {
+	return v1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   name,
+			Labels: map[string]string{"kubernetes.io/metadata.name": name},
+		},
+	}
+}
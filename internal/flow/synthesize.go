@@ -0,0 +1,120 @@
+package flow
+
+import (
+	"sort"
+
+	netv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	"github.com/aszecowka/netpolvalidator/internal/connectivity"
+	"github.com/aszecowka/netpolvalidator/internal/generate"
+	"github.com/aszecowka/netpolvalidator/internal/model"
+)
+
+const (
+	allowIngressPrefix = "allow-observed-ingress-to-"
+	allowEgressPrefix  = "allow-observed-egress-from-"
+)
+
+// Synthesize returns network policies that allow exactly the observed connections and nothing more:
+//   - a policy per destination workload allowing ingress traffic from its observed sources on observed ports,
+//   - a policy per source workload allowing egress traffic to its observed destinations on observed ports.
+//
+// Workloads are selected by labels of their pod templates, so connections of workloads without labels cannot be
+// allowed precisely and are returned as skipped. Namespaces are sorted by name.
+func Synthesize(connections []Connection) ([]generate.NamespacePolicies, []Connection) {
+	policies := make(map[string]*netv1.NetworkPolicy)
+	var skipped []Connection
+	for _, c := range connections {
+		if len(c.Source.Candidate.Labels) == 0 || len(c.Destination.Candidate.Labels) == 0 {
+			skipped = append(skipped, c)
+			continue
+		}
+		ingress := policyFor(policies, c.Destination, allowIngressPrefix, netv1.PolicyTypeIngress)
+		ingress.Spec.Ingress = addIngress(ingress.Spec.Ingress, peerFor(c.Source, c.Destination), c.Port)
+		egress := policyFor(policies, c.Source, allowEgressPrefix, netv1.PolicyTypeEgress)
+		egress.Spec.Egress = addEgress(egress.Spec.Egress, peerFor(c.Destination, c.Source), c.Port)
+	}
+
+	byNamespace := make(map[string][]netv1.NetworkPolicy)
+	for _, np := range policies {
+		byNamespace[np.Namespace] = append(byNamespace[np.Namespace], *np)
+	}
+	var out []generate.NamespacePolicies
+	for ns, nps := range byNamespace {
+		sort.Slice(nps, func(i, j int) bool { return nps[i].Name < nps[j].Name })
+		out = append(out, generate.NamespacePolicies{Namespace: ns, Policies: nps})
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Namespace < out[j].Namespace })
+	return out, skipped
+}
+
+func policyFor(policies map[string]*netv1.NetworkPolicy, e connectivity.Endpoint, prefix string, policyType netv1.PolicyType) *netv1.NetworkPolicy {
+	owner := e.Candidate.Owner
+	key := prefix + owner.String()
+	if np, found := policies[key]; found {
+		return np
+	}
+	np := &netv1.NetworkPolicy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      prefix + owner.Kind + "-" + owner.Name,
+			Namespace: owner.Namespace,
+			Labels:    map[string]string{model.LabelManagedBy: model.ManagedBy},
+		},
+		Spec: netv1.NetworkPolicySpec{
+			PodSelector: metav1.LabelSelector{MatchLabels: e.Candidate.Labels},
+			PolicyTypes: []netv1.PolicyType{policyType},
+		},
+	}
+	policies[key] = np
+	return np
+}
+
+// peerFor selects the peer by labels of its pods, and by its namespace name when it runs in another namespace than the pod.
+func peerFor(peer, pod connectivity.Endpoint) netv1.NetworkPolicyPeer {
+	out := netv1.NetworkPolicyPeer{PodSelector: &metav1.LabelSelector{MatchLabels: peer.Candidate.Labels}}
+	if peer.Namespace.Name != pod.Namespace.Name {
+		out.NamespaceSelector = &metav1.LabelSelector{MatchLabels: map[string]string{model.LabelMetadataName: peer.Namespace.Name}}
+	}
+	return out
+}
+
+// addIngress adds the port to the rule of the peer, relying on connections being sorted by port.
+func addIngress(rules []netv1.NetworkPolicyIngressRule, peer netv1.NetworkPolicyPeer, port connectivity.Port) []netv1.NetworkPolicyIngressRule {
+	for idx := range rules {
+		if peerEqual(rules[idx].From[0], peer) {
+			rules[idx].Ports = append(rules[idx].Ports, newPort(port))
+			return rules
+		}
+	}
+	return append(rules, netv1.NetworkPolicyIngressRule{From: []netv1.NetworkPolicyPeer{peer}, Ports: []netv1.NetworkPolicyPort{newPort(port)}})
+}
+
+// addEgress adds the port to the rule of the peer, relying on connections being sorted by port.
+func addEgress(rules []netv1.NetworkPolicyEgressRule, peer netv1.NetworkPolicyPeer, port connectivity.Port) []netv1.NetworkPolicyEgressRule {
+	for idx := range rules {
+		if peerEqual(rules[idx].To[0], peer) {
+			rules[idx].Ports = append(rules[idx].Ports, newPort(port))
+			return rules
+		}
+	}
+	return append(rules, netv1.NetworkPolicyEgressRule{To: []netv1.NetworkPolicyPeer{peer}, Ports: []netv1.NetworkPolicyPort{newPort(port)}})
+}
+
+func peerEqual(a, b netv1.NetworkPolicyPeer) bool {
+	return selectorString(a.PodSelector) == selectorString(b.PodSelector) && selectorString(a.NamespaceSelector) == selectorString(b.NamespaceSelector)
+}
+
+func selectorString(selector *metav1.LabelSelector) string {
+	if selector == nil {
+		return "<nil>"
+	}
+	return metav1.FormatLabelSelector(selector)
+}
+
+func newPort(port connectivity.Port) netv1.NetworkPolicyPort {
+	protocol := port.Protocol
+	number := intstr.FromInt(int(port.Number))
+	return netv1.NetworkPolicyPort{Protocol: &protocol, Port: &number}
+}
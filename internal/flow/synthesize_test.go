@@ -0,0 +1,135 @@
+package flow_test
+
+import (
+	"testing"
+
+	"github.com/ghodss/yaml"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	netv1 "k8s.io/api/networking/v1"
+
+	"github.com/aszecowka/netpolvalidator/internal/flow"
+	"github.com/aszecowka/netpolvalidator/internal/generate"
+)
+
+func TestSynthesize(t *testing.T) {
+	// GIVEN
+	givenFlows := []flow.Flow{
+		fixFlow("orders", "web-5d4f8b7c9-x2x9z", "payments", "payments-api-7c9d5b6f4-abcde", 9090),
+		fixFlow("orders", "web-5d4f8b7c9-x2x9z", "payments", "payments-api-7c9d5b6f4-abcde", 8080),
+		fixFlow("orders", "web-5d4f8b7c9-x2x9z", "orders", "webhook-server-6b8f9c7d5-q8kzs", 8443),
+		fixFlow("orders", "checkout-x2x9z", "payments", "payments-api-7c9d5b6f4-abcde", 8080),
+	}
+	givenConnections, _ := flow.Resolve(fixClusterState(), givenFlows)
+	// WHEN
+	actual, actualSkipped := flow.Synthesize(givenConnections)
+	// THEN
+	require.Len(t, actualSkipped, 1)
+	assert.Equal(t, "pod/orders/checkout-x2x9z -> deployment/payments/payments-api on TCP/8080", actualSkipped[0].String())
+	assert.Equal(t, []generate.NamespacePolicies{
+		{
+			Namespace: "orders",
+			Policies: []netv1.NetworkPolicy{
+				getNetPol(t, `
+metadata:
+  name: allow-observed-egress-from-deployment-web
+  namespace: orders
+  labels:
+    app.kubernetes.io/managed-by: netpolvalidator
+spec:
+  podSelector:
+    matchLabels:
+      app: web
+  policyTypes: [Egress]
+  egress:
+  - to:
+    - podSelector:
+        matchLabels:
+          app: webhook
+    ports:
+    - protocol: TCP
+      port: 8443
+  - to:
+    - podSelector:
+        matchLabels:
+          app: payments-api
+      namespaceSelector:
+        matchLabels:
+          kubernetes.io/metadata.name: payments
+    ports:
+    - protocol: TCP
+      port: 8080
+    - protocol: TCP
+      port: 9090
+`),
+				getNetPol(t, `
+metadata:
+  name: allow-observed-ingress-to-deployment-webhook-server
+  namespace: orders
+  labels:
+    app.kubernetes.io/managed-by: netpolvalidator
+spec:
+  podSelector:
+    matchLabels:
+      app: webhook
+  policyTypes: [Ingress]
+  ingress:
+  - from:
+    - podSelector:
+        matchLabels:
+          app: web
+    ports:
+    - protocol: TCP
+      port: 8443
+`),
+			},
+		},
+		{
+			Namespace: "payments",
+			Policies: []netv1.NetworkPolicy{
+				getNetPol(t, `
+metadata:
+  name: allow-observed-ingress-to-deployment-payments-api
+  namespace: payments
+  labels:
+    app.kubernetes.io/managed-by: netpolvalidator
+spec:
+  podSelector:
+    matchLabels:
+      app: payments-api
+  policyTypes: [Ingress]
+  ingress:
+  - from:
+    - podSelector:
+        matchLabels:
+          app: web
+      namespaceSelector:
+        matchLabels:
+          kubernetes.io/metadata.name: orders
+    ports:
+    - protocol: TCP
+      port: 8080
+    - protocol: TCP
+      port: 9090
+`),
+			},
+		},
+	}, actual)
+}
+
+func fixFlow(srcNamespace, srcPod, dstNamespace, dstPod string, port int32) flow.Flow {
+	return flow.Flow{
+		Source:      flow.Endpoint{Namespace: srcNamespace, Pod: srcPod},
+		Destination: flow.Endpoint{Namespace: dstNamespace, Pod: dstPod},
+		Protocol:    v1.ProtocolTCP,
+		Port:        port,
+	}
+}
+
+func getNetPol(t *testing.T, in string) netv1.NetworkPolicy {
+	np := netv1.NetworkPolicy{}
+	err := yaml.Unmarshal([]byte(in), &np)
+	require.NoError(t, err)
+	return np
+}
@@ -0,0 +1,201 @@
+package generate
+
+import (
+	"fmt"
+	"sort"
+
+	v1 "k8s.io/api/core/v1"
+	netv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	"github.com/aszecowka/netpolvalidator/internal/model"
+)
+
+const (
+	DefaultDenyName           = "default-deny"
+	AllowDNSName              = "allow-dns"
+	AllowEgressToServicesName = "allow-egress-to-services"
+	allowIngressPrefix        = "allow-ingress-to-"
+
+	labelDNS     = "k8s-app"
+	dnsApp       = "kube-dns"
+	dnsNamespace = "kube-system"
+	dnsPort      = 53
+)
+
+// NamespacePolicies holds network policies generated for a single namespace.
+type NamespacePolicies struct {
+	Namespace string
+	Policies  []netv1.NetworkPolicy
+}
+
+// Generate returns a starter set of network policies for every namespace from the cluster state that has no network
+// policies yet. Every set consists of:
+//   - a policy denying all ingress and egress traffic,
+//   - a policy allowing DNS queries to kube-dns,
+//   - a policy per service allowing ingress traffic to its ports, from the cluster for ClusterIP services
+//     and from anywhere for NodePort and LoadBalancer services,
+//   - a policy allowing egress traffic to pods of services from the same namespace.
+//
+// Only services that select pod candidates are taken into account. Namespaces whose network policies or services
+// were not inspected, because of a coverage gap, may already be protected or would have traffic to their services
+// denied, so they are skipped and returned separately.
+// Namespaces are sorted by name.
+func Generate(state model.ClusterState) (generated []NamespacePolicies, skipped []string) {
+	targets := servicesSelectingWorkloads(state)
+
+	var namespaces []string
+	for ns, policies := range state.NetworkPolicies {
+		if len(policies) > 0 {
+			continue
+		}
+		if notInspected(state.CoverageGaps, ns) {
+			skipped = append(skipped, ns)
+			continue
+		}
+		namespaces = append(namespaces, ns)
+	}
+	sort.Strings(namespaces)
+	sort.Strings(skipped)
+
+	for _, ns := range namespaces {
+		policies := []netv1.NetworkPolicy{defaultDeny(ns), allowDNS(ns)}
+		var inNamespace []v1.Service
+		for _, svc := range targets {
+			if svc.Namespace == ns {
+				policies = append(policies, allowIngressToService(svc))
+				inNamespace = append(inNamespace, svc)
+			}
+		}
+		if len(inNamespace) > 0 {
+			policies = append(policies, allowEgressToServices(ns, inNamespace))
+		}
+		generated = append(generated, NamespacePolicies{Namespace: ns, Policies: policies})
+	}
+	return generated, skipped
+}
+
+// notInspected reports whether network policies or services of the namespace could not be listed.
+func notInspected(gaps []model.CoverageGap, ns string) bool {
+	for _, gap := range gaps {
+		if (gap.Kind == model.KindNetworkPolicy || gap.Kind == model.KindService) && (gap.Namespace == ns || gap.Namespace == "") {
+			return true
+		}
+	}
+	return false
+}
+
+// servicesSelectingWorkloads returns services with a selector that matches pod candidates from their namespace,
+// sorted by namespace and name.
+func servicesSelectingWorkloads(state model.ClusterState) []v1.Service {
+	var out []v1.Service
+	for ns, services := range state.Services {
+		for _, svc := range services {
+			if len(svc.Spec.Selector) == 0 || svc.Spec.Type == v1.ServiceTypeExternalName || len(svc.Spec.Ports) == 0 {
+				continue
+			}
+			selector := labels.SelectorFromSet(svc.Spec.Selector)
+			for _, pc := range state.PodCandidates[ns] {
+				if pc.Matches(selector) {
+					out = append(out, svc)
+					break
+				}
+			}
+		}
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Namespace != out[j].Namespace {
+			return out[i].Namespace < out[j].Namespace
+		}
+		return out[i].Name < out[j].Name
+	})
+	return out
+}
+
+func defaultDeny(ns string) netv1.NetworkPolicy {
+	np := newNetworkPolicy(ns, DefaultDenyName)
+	np.Spec.PolicyTypes = []netv1.PolicyType{netv1.PolicyTypeIngress, netv1.PolicyTypeEgress}
+	return np
+}
+
+func allowDNS(ns string) netv1.NetworkPolicy {
+	np := newNetworkPolicy(ns, AllowDNSName)
+	np.Spec.PolicyTypes = []netv1.PolicyType{netv1.PolicyTypeEgress}
+	np.Spec.Egress = []netv1.NetworkPolicyEgressRule{{
+		To: []netv1.NetworkPolicyPeer{{
+			NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{model.LabelMetadataName: dnsNamespace}},
+			PodSelector:       &metav1.LabelSelector{MatchLabels: map[string]string{labelDNS: dnsApp}},
+		}},
+		Ports: []netv1.NetworkPolicyPort{
+			newPort(v1.ProtocolUDP, intstr.FromInt(dnsPort)),
+			newPort(v1.ProtocolTCP, intstr.FromInt(dnsPort)),
+		},
+	}}
+	return np
+}
+
+func allowIngressToService(svc v1.Service) netv1.NetworkPolicy {
+	np := newNetworkPolicy(svc.Namespace, allowIngressPrefix+svc.Name)
+	np.Spec.PodSelector = metav1.LabelSelector{MatchLabels: svc.Spec.Selector}
+	np.Spec.PolicyTypes = []netv1.PolicyType{netv1.PolicyTypeIngress}
+	rule := netv1.NetworkPolicyIngressRule{Ports: targetPorts(svc)}
+	if svc.Spec.Type != v1.ServiceTypeNodePort && svc.Spec.Type != v1.ServiceTypeLoadBalancer {
+		rule.From = []netv1.NetworkPolicyPeer{{NamespaceSelector: &metav1.LabelSelector{}}}
+	}
+	np.Spec.Ingress = []netv1.NetworkPolicyIngressRule{rule}
+	return np
+}
+
+// allowEgressToServices allows egress traffic to pods of the given services from the namespace. Egress to services
+// from other namespaces is not allowed, as a baseline should not let every workload reach every service in the cluster.
+func allowEgressToServices(ns string, services []v1.Service) netv1.NetworkPolicy {
+	np := newNetworkPolicy(ns, AllowEgressToServicesName)
+	np.Spec.PolicyTypes = []netv1.PolicyType{netv1.PolicyTypeEgress}
+	for _, svc := range services {
+		np.Spec.Egress = append(np.Spec.Egress, netv1.NetworkPolicyEgressRule{
+			To:    []netv1.NetworkPolicyPeer{{PodSelector: &metav1.LabelSelector{MatchLabels: svc.Spec.Selector}}},
+			Ports: targetPorts(svc),
+		})
+	}
+	return np
+}
+
+// targetPorts returns ports of pods the service sends traffic to. Network policies apply to traffic after
+// the service IP is translated to the pod IP, so the service port itself is used only when the target port is not set.
+func targetPorts(svc v1.Service) []netv1.NetworkPolicyPort {
+	var out []netv1.NetworkPolicyPort
+	seen := make(map[string]bool)
+	for _, p := range svc.Spec.Ports {
+		protocol := p.Protocol
+		if protocol == "" {
+			protocol = v1.ProtocolTCP
+		}
+		target := p.TargetPort
+		if target.Type == intstr.Int && target.IntVal == 0 {
+			target = intstr.FromInt(int(p.Port))
+		}
+		key := fmt.Sprintf("%s/%s", protocol, target.String())
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		out = append(out, newPort(protocol, target))
+	}
+	return out
+}
+
+func newPort(protocol v1.Protocol, port intstr.IntOrString) netv1.NetworkPolicyPort {
+	return netv1.NetworkPolicyPort{Protocol: &protocol, Port: &port}
+}
+
+func newNetworkPolicy(ns, name string) netv1.NetworkPolicy {
+	return netv1.NetworkPolicy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: ns,
+			Labels:    map[string]string{model.LabelManagedBy: model.ManagedBy},
+		},
+	}
+}
@@ -0,0 +1,176 @@
+package generate_test
+
+import (
+	"testing"
+
+	"github.com/ghodss/yaml"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	netv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	"github.com/aszecowka/netpolvalidator/internal/generate"
+	"github.com/aszecowka/netpolvalidator/internal/model"
+)
+
+func TestGenerate(t *testing.T) {
+	// GIVEN
+	givenState := model.ClusterState{
+		NetworkPolicies: map[string][]netv1.NetworkPolicy{
+			"orders":   nil,
+			"payments": {{ObjectMeta: metav1.ObjectMeta{Name: "existing", Namespace: "payments"}}},
+		},
+		PodCandidates: map[string][]model.PodCandidate{
+			"orders":   {{Labels: map[string]string{"app": "web"}}},
+			"payments": {{Labels: map[string]string{"app": "api"}}},
+		},
+		Services: map[string][]v1.Service{
+			"orders": {
+				fixService("orders", "web", v1.ServiceTypeLoadBalancer, map[string]string{"app": "web"},
+					v1.ServicePort{Port: 80, TargetPort: intstr.FromString("http")},
+					v1.ServicePort{Port: 443, TargetPort: intstr.FromString("http")}),
+				fixService("orders", "no-pods", v1.ServiceTypeClusterIP, map[string]string{"app": "gone"}, v1.ServicePort{Port: 80}),
+				fixService("orders", "external", v1.ServiceTypeExternalName, nil, v1.ServicePort{Port: 80}),
+			},
+			"payments": {
+				fixService("payments", "api", v1.ServiceTypeClusterIP, map[string]string{"app": "api"}, v1.ServicePort{Port: 8080, Protocol: v1.ProtocolTCP}),
+			},
+		},
+	}
+	// WHEN
+	actual, skipped := generate.Generate(givenState)
+	// THEN
+	assert.Empty(t, skipped)
+	require.Len(t, actual, 1)
+	assert.Equal(t, "orders", actual[0].Namespace)
+	assert.Equal(t, []netv1.NetworkPolicy{
+		getNetPol(t, `
+metadata:
+  name: default-deny
+  namespace: orders
+  labels:
+    app.kubernetes.io/managed-by: netpolvalidator
+spec:
+  podSelector: {}
+  policyTypes: [Ingress, Egress]
+`),
+		getNetPol(t, `
+metadata:
+  name: allow-dns
+  namespace: orders
+  labels:
+    app.kubernetes.io/managed-by: netpolvalidator
+spec:
+  podSelector: {}
+  policyTypes: [Egress]
+  egress:
+  - to:
+    - namespaceSelector:
+        matchLabels:
+          kubernetes.io/metadata.name: kube-system
+      podSelector:
+        matchLabels:
+          k8s-app: kube-dns
+    ports:
+    - protocol: UDP
+      port: 53
+    - protocol: TCP
+      port: 53
+`),
+		getNetPol(t, `
+metadata:
+  name: allow-ingress-to-web
+  namespace: orders
+  labels:
+    app.kubernetes.io/managed-by: netpolvalidator
+spec:
+  podSelector:
+    matchLabels:
+      app: web
+  policyTypes: [Ingress]
+  ingress:
+  - ports:
+    - protocol: TCP
+      port: http
+`),
+		getNetPol(t, `
+metadata:
+  name: allow-egress-to-services
+  namespace: orders
+  labels:
+    app.kubernetes.io/managed-by: netpolvalidator
+spec:
+  podSelector: {}
+  policyTypes: [Egress]
+  egress:
+  - to:
+    - podSelector:
+        matchLabels:
+          app: web
+    ports:
+    - protocol: TCP
+      port: http
+`),
+	}, actual[0].Policies)
+}
+
+func TestGenerateIngressFromCluster(t *testing.T) {
+	// GIVEN
+	givenState := model.ClusterState{
+		NetworkPolicies: map[string][]netv1.NetworkPolicy{"payments": nil},
+		PodCandidates: map[string][]model.PodCandidate{
+			"payments": {{Labels: map[string]string{"app": "api"}}},
+		},
+		Services: map[string][]v1.Service{
+			"payments": {fixService("payments", "api", v1.ServiceTypeClusterIP, map[string]string{"app": "api"}, v1.ServicePort{Port: 8080})},
+		},
+	}
+	// WHEN
+	actual, _ := generate.Generate(givenState)
+	// THEN
+	require.Len(t, actual, 1)
+	require.Len(t, actual[0].Policies, 4)
+	assert.Equal(t, "allow-ingress-to-api", actual[0].Policies[2].Name)
+	assert.Equal(t, []netv1.NetworkPolicyPeer{{NamespaceSelector: &metav1.LabelSelector{}}}, actual[0].Policies[2].Spec.Ingress[0].From)
+}
+
+func TestGenerateSkipsNamespacesNotInspected(t *testing.T) {
+	// GIVEN
+	givenState := model.ClusterState{
+		NetworkPolicies: map[string][]netv1.NetworkPolicy{"orders": nil, "payments": nil, "shipping": nil},
+		CoverageGaps: []model.CoverageGap{
+			{Namespace: "payments", Kind: model.KindNetworkPolicy, Reason: "Forbidden"},
+			{Namespace: "shipping", Kind: model.KindService, Reason: "Forbidden"},
+			{Namespace: "orders", Kind: "deployment", Reason: "Forbidden"},
+		},
+	}
+	// WHEN
+	actual, skipped := generate.Generate(givenState)
+	// THEN
+	assert.Equal(t, []string{"payments", "shipping"}, skipped)
+	require.Len(t, actual, 1)
+	assert.Equal(t, "orders", actual[0].Namespace)
+}
+
+func fixService(namespace, name string, serviceType v1.ServiceType, selector map[string]string, ports ...v1.ServicePort) v1.Service {
+	return v1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+		},
+		Spec: v1.ServiceSpec{
+			Type:     serviceType,
+			Selector: selector,
+			Ports:    ports,
+		},
+	}
+}
+
+func getNetPol(t *testing.T, in string) netv1.NetworkPolicy {
+	np := netv1.NetworkPolicy{}
+	err := yaml.Unmarshal([]byte(in), &np)
+	require.NoError(t, err)
+	return np
+}
@@ -0,0 +1,80 @@
+package kubeconfig
+
+import (
+	"fmt"
+	"sort"
+
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+const (
+	// AllContexts selects every context defined in the kubeconfig file.
+	AllContexts = "all"
+	// InCluster is the name of the cluster the tool runs in, when it uses its ServiceAccount instead of kubeconfig.
+	InCluster = "in-cluster"
+)
+
+type Cluster struct {
+	Name   string
+	Config *rest.Config
+}
+
+// LoadClusters returns configuration of clusters for given kubeconfig contexts, read with the loader, e.g. the one
+// of kubectl flags, which honors --kubeconfig and $KUBECONFIG.
+// No contexts means the single cluster the loader points to: the one of the given context, of the current context
+// or, when kubeconfig has no current context, the cluster the tool runs in.
+// A single AllContexts entry means every context from kubeconfig.
+func LoadClusters(loader clientcmd.ClientConfig, context string, contexts []string) ([]Cluster, error) {
+	if context != "" && len(contexts) > 0 {
+		return nil, fmt.Errorf("context and contexts parameters cannot be used together")
+	}
+	raw, err := loader.RawConfig()
+	if err != nil {
+		return nil, fmt.Errorf("while loading kubeconfig: %w", err)
+	}
+
+	switch {
+	case len(contexts) == 0:
+		return loadSelectedCluster(loader, context, raw.CurrentContext)
+	case len(contexts) == 1 && contexts[0] == AllContexts:
+		contexts = nil
+		for name := range raw.Contexts {
+			contexts = append(contexts, name)
+		}
+		sort.Strings(contexts)
+	}
+
+	var out []Cluster
+	for _, name := range contexts {
+		if _, found := raw.Contexts[name]; !found {
+			return nil, fmt.Errorf("context %s not found in kubeconfig", name)
+		}
+		cfg, err := clientcmd.NewNonInteractiveClientConfig(raw, name, &clientcmd.ConfigOverrides{}, loader.ConfigAccess()).ClientConfig()
+		if err != nil {
+			return nil, fmt.Errorf("while creating client configuration for context %s: %w", name, err)
+		}
+		out = append(out, Cluster{Name: name, Config: cfg})
+	}
+	return out, nil
+}
+
+// loadSelectedCluster returns the cluster the loader points to, with overrides of the loader applied.
+func loadSelectedCluster(loader clientcmd.ClientConfig, context, currentContext string) ([]Cluster, error) {
+	name := context
+	if name == "" {
+		name = currentContext
+	}
+	if name == "" {
+		if _, err := rest.InClusterConfig(); err != nil {
+			return nil, fmt.Errorf("current context is not set in kubeconfig and in-cluster configuration is not available: %w", err)
+		}
+		name = InCluster
+	}
+	// without kubeconfig, the loader falls back to the in-cluster configuration
+	cfg, err := loader.ClientConfig()
+	if err != nil {
+		return nil, fmt.Errorf("while loading client configuration: %w", err)
+	}
+	return []Cluster{{Name: name, Config: cfg}}, nil
+}
@@ -0,0 +1,137 @@
+package kubeconfig_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/client-go/tools/clientcmd"
+
+	"github.com/aszecowka/netpolvalidator/internal/kubeconfig"
+)
+
+func TestLoadClusters(t *testing.T) {
+	path := fixKubeconfig(t)
+	loader := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(&clientcmd.ClientConfigLoadingRules{ExplicitPath: path}, &clientcmd.ConfigOverrides{})
+
+	t.Run("current context", func(t *testing.T) {
+		// WHEN
+		actual, err := kubeconfig.LoadClusters(loader, "", nil)
+		// THEN
+		require.NoError(t, err)
+		require.Len(t, actual, 1)
+		assert.Equal(t, "staging", actual[0].Name)
+		assert.Equal(t, "https://staging.example.com", actual[0].Config.Host)
+	})
+
+	t.Run("context selected with kubectl flags", func(t *testing.T) {
+		// GIVEN
+		givenLoader := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(&clientcmd.ClientConfigLoadingRules{ExplicitPath: path}, &clientcmd.ConfigOverrides{CurrentContext: "prod"})
+		// WHEN
+		actual, err := kubeconfig.LoadClusters(givenLoader, "prod", nil)
+		// THEN
+		require.NoError(t, err)
+		require.Len(t, actual, 1)
+		assert.Equal(t, "prod", actual[0].Name)
+		assert.Equal(t, "https://prod.example.com", actual[0].Config.Host)
+	})
+
+	t.Run("context together with contexts", func(t *testing.T) {
+		// WHEN
+		_, err := kubeconfig.LoadClusters(loader, "prod", []string{"staging"})
+		// THEN
+		require.EqualError(t, err, "context and contexts parameters cannot be used together")
+	})
+
+	t.Run("selected contexts", func(t *testing.T) {
+		// WHEN
+		actual, err := kubeconfig.LoadClusters(loader, "", []string{"prod"})
+		// THEN
+		require.NoError(t, err)
+		require.Len(t, actual, 1)
+		assert.Equal(t, "prod", actual[0].Name)
+		assert.Equal(t, "https://prod.example.com", actual[0].Config.Host)
+	})
+
+	t.Run("all contexts", func(t *testing.T) {
+		// WHEN
+		actual, err := kubeconfig.LoadClusters(loader, "", []string{kubeconfig.AllContexts})
+		// THEN
+		require.NoError(t, err)
+		require.Len(t, actual, 2)
+		assert.Equal(t, "prod", actual[0].Name)
+		assert.Equal(t, "staging", actual[1].Name)
+	})
+
+	t.Run("unknown context", func(t *testing.T) {
+		// WHEN
+		_, err := kubeconfig.LoadClusters(loader, "", []string{"dev"})
+		// THEN
+		require.EqualError(t, err, "context dev not found in kubeconfig")
+	})
+
+	t.Run("kubeconfig from KUBECONFIG environment variable", func(t *testing.T) {
+		// GIVEN
+		defer os.Setenv(clientcmd.RecommendedConfigPathEnvVar, os.Getenv(clientcmd.RecommendedConfigPathEnvVar))
+		require.NoError(t, os.Setenv(clientcmd.RecommendedConfigPathEnvVar, path))
+		givenLoader := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(clientcmd.NewDefaultClientConfigLoadingRules(), &clientcmd.ConfigOverrides{})
+		// WHEN
+		actual, err := kubeconfig.LoadClusters(givenLoader, "", []string{kubeconfig.AllContexts})
+		// THEN
+		require.NoError(t, err)
+		require.Len(t, actual, 2)
+		assert.Equal(t, "https://prod.example.com", actual[0].Config.Host)
+	})
+
+	t.Run("missing kubeconfig outside of cluster", func(t *testing.T) {
+		// GIVEN
+		if os.Getenv("KUBERNETES_SERVICE_HOST") != "" {
+			t.Skip("running inside a cluster")
+		}
+		givenLoader := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(&clientcmd.ClientConfigLoadingRules{}, &clientcmd.ConfigOverrides{})
+		// WHEN
+		_, err := kubeconfig.LoadClusters(givenLoader, "", nil)
+		// THEN
+		require.EqualError(t, err, "current context is not set in kubeconfig and in-cluster configuration is not available: unable to load in-cluster configuration, KUBERNETES_SERVICE_HOST and KUBERNETES_SERVICE_PORT must be defined")
+	})
+}
+
+func fixKubeconfig(t *testing.T) string {
+	t.Helper()
+	dir, err := ioutil.TempDir("", "kubeconfig")
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		require.NoError(t, os.RemoveAll(dir))
+	})
+
+	path := filepath.Join(dir, "config")
+	require.NoError(t, ioutil.WriteFile(path, []byte(`
+apiVersion: v1
+kind: Config
+current-context: staging
+clusters:
+- name: prod
+  cluster:
+    server: https://prod.example.com
+- name: staging
+  cluster:
+    server: https://staging.example.com
+users:
+- name: admin
+  user:
+    token: token
+contexts:
+- name: prod
+  context:
+    cluster: prod
+    user: admin
+- name: staging
+  context:
+    cluster: staging
+    user: admin
+`), 0600))
+	return path
+}
@@ -0,0 +1,111 @@
+package metrics
+
+import (
+	"fmt"
+
+	"github.com/prometheus/client_golang/prometheus"
+	netv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	"github.com/aszecowka/netpolvalidator/internal/model"
+	"github.com/aszecowka/netpolvalidator/internal/watch"
+)
+
+const metricsNamespace = "netpolvalidator"
+
+// collector exposes results of watch mode scans as Prometheus metrics.
+type collector struct {
+	violations           *prometheus.GaugeVec
+	unprotectedWorkloads *prometheus.GaugeVec
+	networkPolicies      *prometheus.GaugeVec
+	lastSuccessfulScan   prometheus.Gauge
+	scanDuration         prometheus.Gauge
+}
+
+func NewCollector(registerer prometheus.Registerer) (*collector, error) {
+	c := &collector{
+		violations: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: metricsNamespace,
+			Name:      "violations",
+			Help:      "Number of violations found by the last scan.",
+		}, []string{"namespace", "rule", "type", "severity"}),
+		unprotectedWorkloads: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: metricsNamespace,
+			Name:      "unprotected_workloads",
+			Help:      "Number of workloads not selected by any network policy.",
+		}, []string{"namespace"}),
+		networkPolicies: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: metricsNamespace,
+			Name:      "network_policies",
+			Help:      "Number of network policies.",
+		}, []string{"namespace"}),
+		lastSuccessfulScan: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: metricsNamespace,
+			Name:      "last_successful_scan_timestamp_seconds",
+			Help:      "Unix time of the last successful scan.",
+		}),
+		scanDuration: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: metricsNamespace,
+			Name:      "scan_duration_seconds",
+			Help:      "Duration of the last successful scan.",
+		}),
+	}
+	for _, collector := range []prometheus.Collector{c.violations, c.unprotectedWorkloads, c.networkPolicies, c.lastSuccessfulScan, c.scanDuration} {
+		if err := registerer.Register(collector); err != nil {
+			return nil, fmt.Errorf("while registering metrics: %w", err)
+		}
+	}
+	return c, nil
+}
+
+func (c *collector) OnScan(scan watch.Scan) {
+	c.violations.Reset()
+	for rule, violations := range scan.ViolationsByValidator {
+		for _, v := range violations {
+			c.violations.WithLabelValues(v.Namespace, rule, string(v.Type), string(v.Type.Severity())).Inc()
+		}
+	}
+
+	c.unprotectedWorkloads.Reset()
+	c.networkPolicies.Reset()
+	for _, ns := range scan.State.Namespaces {
+		// namespaces out of the validation scope have no entry, their workloads are inspected only to resolve selectors
+		policies, inScope := scan.State.NetworkPolicies[ns.Name]
+		if !inScope {
+			continue
+		}
+		c.networkPolicies.WithLabelValues(ns.Name).Set(float64(len(policies)))
+		c.unprotectedWorkloads.WithLabelValues(ns.Name).Set(float64(countUnprotected(scan.State.PodCandidates[ns.Name], policies)))
+	}
+
+	c.lastSuccessfulScan.Set(float64(scan.Finished.Unix()))
+	c.scanDuration.Set(scan.Duration.Seconds())
+}
+
+// countUnprotected counts pod candidates that are not selected by the pod selector of any of given policies.
+func countUnprotected(podCandidates []model.PodCandidate, policies []netv1.NetworkPolicy) int {
+	var selectors []labels.Selector
+	for _, np := range policies {
+		selector, err := metav1.LabelSelectorAsSelector(&np.Spec.PodSelector)
+		if err != nil {
+			continue
+		}
+		selectors = append(selectors, selector)
+	}
+
+	unprotected := 0
+	for _, pc := range podCandidates {
+		protected := false
+		for _, selector := range selectors {
+			if pc.Matches(selector) {
+				protected = true
+				break
+			}
+		}
+		if !protected {
+			unprotected++
+		}
+	}
+	return unprotected
+}
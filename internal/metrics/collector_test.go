@@ -0,0 +1,111 @@
+package metrics_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	netv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/aszecowka/netpolvalidator/internal/metrics"
+	"github.com/aszecowka/netpolvalidator/internal/model"
+	"github.com/aszecowka/netpolvalidator/internal/watch"
+)
+
+func TestCollector(t *testing.T) {
+	// GIVEN
+	registry := prometheus.NewRegistry()
+	sut, err := metrics.NewCollector(registry)
+	require.NoError(t, err)
+
+	givenScan := watch.Scan{
+		State: model.ClusterState{
+			Namespaces: []v1.Namespace{fixNs("orders"), fixNs("payments"), fixNs("kube-system")},
+			NetworkPolicies: map[string][]netv1.NetworkPolicy{
+				"orders":   {fixNetPol("orders", "ingress-to-a", "orders-a"), fixNetPol("orders", "ingress-to-c", "orders-c")},
+				"payments": nil,
+			},
+			PodCandidates: map[string][]model.PodCandidate{
+				"orders": {
+					{Owner: model.Owner{Kind: "deployment", Namespace: "orders", Name: "orders-a"}, Labels: map[string]string{"app": "orders-a"}},
+					{Owner: model.Owner{Kind: "deployment", Namespace: "orders", Name: "orders-b"}, Labels: map[string]string{"app": "orders-b"}},
+				},
+				"payments": {
+					{Owner: model.Owner{Kind: "deployment", Namespace: "payments", Name: "payments-a"}, Labels: map[string]string{"app": "payments-a"}},
+				},
+				// out of the validation scope
+				"kube-system": {
+					{Owner: model.Owner{Kind: "deployment", Namespace: "kube-system", Name: "coredns"}, Labels: map[string]string{"k8s-app": "kube-dns"}},
+				},
+			},
+		},
+		ViolationsByValidator: map[string][]model.Violation{
+			"label correctness": {
+				{Namespace: "orders", NetworkPolicyName: "ingress-to-c", Type: model.ViolationInvalidLabel, Message: "no pods matching pod selector"},
+			},
+		},
+		Finished: time.Unix(1600000000, 0),
+		Duration: 1500 * time.Millisecond,
+	}
+
+	// WHEN
+	sut.OnScan(givenScan)
+
+	// THEN
+	expected := `
+# HELP netpolvalidator_last_successful_scan_timestamp_seconds Unix time of the last successful scan.
+# TYPE netpolvalidator_last_successful_scan_timestamp_seconds gauge
+netpolvalidator_last_successful_scan_timestamp_seconds 1.6e+09
+# HELP netpolvalidator_network_policies Number of network policies.
+# TYPE netpolvalidator_network_policies gauge
+netpolvalidator_network_policies{namespace="orders"} 2
+netpolvalidator_network_policies{namespace="payments"} 0
+# HELP netpolvalidator_scan_duration_seconds Duration of the last successful scan.
+# TYPE netpolvalidator_scan_duration_seconds gauge
+netpolvalidator_scan_duration_seconds 1.5
+# HELP netpolvalidator_unprotected_workloads Number of workloads not selected by any network policy.
+# TYPE netpolvalidator_unprotected_workloads gauge
+netpolvalidator_unprotected_workloads{namespace="orders"} 1
+netpolvalidator_unprotected_workloads{namespace="payments"} 1
+# HELP netpolvalidator_violations Number of violations found by the last scan.
+# TYPE netpolvalidator_violations gauge
+netpolvalidator_violations{namespace="orders",rule="label correctness",severity="error",type="Invalid Label"} 1
+`
+	require.NoError(t, testutil.GatherAndCompare(registry, strings.NewReader(expected)))
+
+	// WHEN
+	givenScan.ViolationsByValidator = nil
+	sut.OnScan(givenScan)
+
+	// THEN
+	count, err := testutil.GatherAndCount(registry, "netpolvalidator_violations")
+	require.NoError(t, err)
+	require.Equal(t, 0, count)
+}
+
+func fixNs(name string) v1.Namespace {
+	return v1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: name,
+		},
+	}
+}
+
+func fixNetPol(namespace, name, app string) netv1.NetworkPolicy {
+	return netv1.NetworkPolicy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+		},
+		Spec: netv1.NetworkPolicySpec{
+			PodSelector: metav1.LabelSelector{
+				MatchLabels: map[string]string{"app": app},
+			},
+		},
+	}
+}
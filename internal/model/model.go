@@ -5,26 +5,183 @@ import (
 
 	v1 "k8s.io/api/core/v1"
 	networkingv1 "k8s.io/api/networking/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/selection"
 )
 
 const (
-	ViolationInvalidLabel ViolationType = "Invalid Label"
-	Ingress               RuleType      = "Ingress"
-	Egress                RuleType      = "Egress"
+	KindNamespace     = "namespace"
+	KindNetworkPolicy = "networkpolicy"
+	KindService       = "service"
+
+	ViolationInvalidLabel      ViolationType = "Invalid Label"
+	ViolationInactiveWorkloads ViolationType = "Inactive Workloads"
+	ViolationIgnoredRules      ViolationType = "Ignored Rules"
+	Ingress                    RuleType      = "Ingress"
+	Egress                     RuleType      = "Egress"
+
+	SeverityError   Severity = "error"
+	SeverityWarning Severity = "warning"
+
+	// LabelMetadataName is set by Kubernetes 1.21 and newer on every namespace to its name.
+	LabelMetadataName = "kubernetes.io/metadata.name"
+	// LabelManagedBy with the ManagedBy value marks network policies generated by netpolvalidator.
+	LabelManagedBy = "app.kubernetes.io/managed-by"
+	ManagedBy      = "netpolvalidator"
 )
 
 type ViolationType string
 type RuleType string
+type Severity string
+
+// Severity tells how serious violations of the given type are.
+func (t ViolationType) Severity() Severity {
+	switch t {
+	case ViolationInvalidLabel:
+		return SeverityError
+	default:
+		return SeverityWarning
+	}
+}
+
+var severityRanks = map[Severity]int{
+	SeverityWarning: 1,
+	SeverityError:   2,
+}
+
+// AtLeast reports whether s is as serious as threshold or more serious.
+func (s Severity) AtLeast(threshold Severity) bool {
+	return severityRanks[s] >= severityRanks[threshold]
+}
+
+// Owner identifies the workload that creates pods of a pod candidate.
+type Owner struct {
+	// Kind is the workload type in lower case, e.g. deployment.
+	Kind      string
+	Namespace string
+	Name      string
+}
+
+// String returns the owner in the kind/namespace/name format, e.g. deployment/orders/web.
+func (o Owner) String() string {
+	return fmt.Sprintf("%s/%s/%s", o.Kind, o.Namespace, o.Name)
+}
 
 type PodCandidate struct {
-	OwnerName string
-	Labels    map[string]string
+	Owner  Owner
+	Labels map[string]string
+	// InjectedLabels holds labels that controllers add to pods, but pod templates do not declare, e.g. pod-template-hash,
+	// together with all values pods may have. Nil values mean that the value is not known up front and can be anything.
+	InjectedLabels map[string][]string
+	// ControlledBy holds owner names of workloads that may control the pod, starting from the nearest one,
+	// e.g. replicaset/orders/web-5d4f8b7c9 and deployment/orders/web. It is empty for standalone pods and workloads.
+	ControlledBy []string
+	// DesiredReplicas is the number of pods the workload is supposed to run. Nil means that it is not known up front,
+	// e.g. for cronjobs and custom resources.
+	DesiredReplicas *int32
+	// ReadyReplicas is the number of ready pods, as reported by the workload.
+	ReadyReplicas int32
+	// LiveReplicas is the number of existing pods of the candidate.
+	LiveReplicas   int
+	ServiceAccount string
+	NodeSelector   map[string]string
+	// Suspended tells that the workload does not create pods until it is resumed, e.g. a suspended cronjob.
+	Suspended bool
+	// Completed tells that the workload finished and runs no pods anymore, e.g. a job that succeeded or failed.
+	Completed bool
+}
+
+// OwnerName returns the owner of the candidate in the kind/namespace/name format, e.g. deployment/orders/web.
+func (pc PodCandidate) OwnerName() string {
+	return pc.Owner.String()
+}
+
+// ScaledToZero reports whether the workload is supposed to run no pods.
+func (pc PodCandidate) ScaledToZero() bool {
+	return pc.DesiredReplicas != nil && *pc.DesiredReplicas == 0
+}
+
+// Running reports whether the workload is supposed to run pods, i.e. it is neither suspended, completed nor scaled to zero.
+func (pc PodCandidate) Running() bool {
+	return !pc.Suspended && !pc.Completed && !pc.ScaledToZero()
+}
+
+// Matches reports whether pods of the candidate may be selected by the selector. Requirements on injected labels
+// are satisfied when any of their values satisfies them.
+func (pc PodCandidate) Matches(selector labels.Selector) bool {
+	requirements, selectable := selector.Requirements()
+	if !selectable {
+		return false
+	}
+	set := labels.Set(pc.Labels)
+	for _, r := range requirements {
+		values, injected := pc.InjectedLabels[r.Key()]
+		if _, declared := pc.Labels[r.Key()]; declared || !injected {
+			if !r.Matches(set) {
+				return false
+			}
+			continue
+		}
+		if !injectedLabelMatches(r, values) {
+			return false
+		}
+	}
+	return true
+}
+
+func injectedLabelMatches(r labels.Requirement, values []string) bool {
+	if values == nil {
+		return r.Operator() != selection.DoesNotExist
+	}
+	for _, v := range values {
+		if r.Matches(labels.Set{r.Key(): v}) {
+			return true
+		}
+	}
+	return false
 }
 
 type ClusterState struct {
 	Namespaces      []v1.Namespace
 	NetworkPolicies map[string][]networkingv1.NetworkPolicy
 	PodCandidates   map[string][]PodCandidate
+	CoverageGaps    []CoverageGap
+	// Services holds services by namespace. They are fetched only by commands that need them, e.g. generate.
+	Services map[string][]v1.Service
+}
+
+// ClusterReport holds results of validating a single cluster.
+type ClusterReport struct {
+	Cluster    string
+	State      ClusterState
+	Violations []Violation
+	// Err tells why the cluster was not validated, e.g. because it is unreachable. State and Violations are empty then.
+	Err error
+}
+
+// CoverageGap describes a kind of resources that was not inspected in the given namespace.
+// Empty Namespace means that the kind was not inspected in any namespace.
+type CoverageGap struct {
+	Namespace string
+	Kind      string
+	Reason    string
+}
+
+func NewCoverageGap(namespace, kind string, err error) CoverageGap {
+	return CoverageGap{
+		Namespace: namespace,
+		Kind:      kind,
+		Reason:    string(apierrors.ReasonForError(err)),
+	}
+}
+
+func (g CoverageGap) String() string {
+	ns := g.Namespace
+	if ns == "" {
+		ns = "all namespaces"
+	}
+	return fmt.Sprintf("[%s]: %s not inspected: %s", ns, g.Kind, g.Reason)
 }
 
 type Violation struct {
@@ -32,15 +189,62 @@ type Violation struct {
 	Namespace         string
 	Message           string
 	Type              ViolationType
+	// Position tells which part of the network policy is violated, e.g. Ingress [1:2] for the second peer of the first
+	// ingress rule. It is empty when the violation refers to the pod selector of the policy.
+	Position string
+	// Reason is a short, CamelCase reason of the violation, e.g. for Kubernetes Events.
+	Reason string
+}
+
+func NewViolation(np networkingv1.NetworkPolicy, reason, message string, vType ViolationType) Violation {
+	return NewViolationAt(np, "", reason, message, vType)
 }
 
-func NewViolation(np networkingv1.NetworkPolicy, message string, vType ViolationType) Violation {
+// NewViolationAt creates a violation of the given part of the network policy, see Violation.Position.
+func NewViolationAt(np networkingv1.NetworkPolicy, position, reason, message string, vType ViolationType) Violation {
 	return Violation{
 		Namespace:         np.Namespace,
 		NetworkPolicyName: np.Name,
 		Message:           message,
 		Type:              vType,
+		Position:          position,
+		Reason:            reason,
+	}
+}
+
+// ViolationKey identifies a violation regardless of its message, which changes between validations when it reports
+// live counts or suggestions.
+type ViolationKey struct {
+	Namespace         string
+	NetworkPolicyName string
+	Type              ViolationType
+	Position          string
+}
+
+// Key returns the identity of the violation, e.g. to tell new violations from the ones already reported.
+func (v Violation) Key() ViolationKey {
+	return ViolationKey{
+		Namespace:         v.Namespace,
+		NetworkPolicyName: v.NetworkPolicyName,
+		Type:              v.Type,
+		Position:          v.Position,
+	}
+}
+
+// Subtract returns violations from a that are not in b, keeping the order of a. Violations are compared by their keys,
+// so that a violation whose message changed, e.g. because of a new suggestion, is not reported again.
+func Subtract(a, b []Violation) []Violation {
+	inB := make(map[ViolationKey]struct{}, len(b))
+	for _, v := range b {
+		inB[v.Key()] = struct{}{}
+	}
+	var out []Violation
+	for _, v := range a {
+		if _, found := inB[v.Key()]; !found {
+			out = append(out, v)
+		}
 	}
+	return out
 }
 
 func (v Violation) String() string {
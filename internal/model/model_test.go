@@ -0,0 +1,127 @@
+package model_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/labels"
+
+	"github.com/aszecowka/netpolvalidator/internal/model"
+)
+
+func TestPodCandidateMatches(t *testing.T) {
+	givenCandidate := model.PodCandidate{
+		Owner:  model.Owner{Kind: "statefulset", Namespace: "orders", Name: "db"},
+		Labels: map[string]string{"app": "db"},
+		InjectedLabels: map[string][]string{
+			"statefulset.kubernetes.io/pod-name": {"db-0", "db-1"},
+			"controller-revision-hash":           nil,
+		},
+	}
+
+	testCases := map[string]struct {
+		givenSelector string
+		expected      bool
+	}{
+		"declared label":                            {givenSelector: "app=db", expected: true},
+		"wrong declared label":                      {givenSelector: "app=web", expected: false},
+		"injected label with one of known values":   {givenSelector: "app=db,statefulset.kubernetes.io/pod-name=db-1", expected: true},
+		"injected label with unknown value":         {givenSelector: "statefulset.kubernetes.io/pod-name=db-2", expected: false},
+		"injected label with any value":             {givenSelector: "controller-revision-hash=db-5d4f8b7c9", expected: true},
+		"injected label exists":                     {givenSelector: "controller-revision-hash", expected: true},
+		"injected label does not exist":             {givenSelector: "!controller-revision-hash", expected: false},
+		"injected label not in known values":        {givenSelector: "statefulset.kubernetes.io/pod-name notin (db-0)", expected: true},
+		"injected label not in all of known values": {givenSelector: "statefulset.kubernetes.io/pod-name notin (db-0,db-1)", expected: false},
+		"label neither declared nor injected":       {givenSelector: "tier=backend", expected: false},
+	}
+
+	for tn, tc := range testCases {
+		t.Run(tn, func(t *testing.T) {
+			// GIVEN
+			selector, err := labels.Parse(tc.givenSelector)
+			require.NoError(t, err)
+			// WHEN
+			actual := givenCandidate.Matches(selector)
+			// THEN
+			assert.Equal(t, tc.expected, actual)
+		})
+	}
+}
+
+func TestPodCandidateRunning(t *testing.T) {
+	zero, one := int32(0), int32(1)
+	testCases := map[string]struct {
+		givenCandidate       model.PodCandidate
+		expectedScaledToZero bool
+		expectedRunning      bool
+	}{
+		"workload with replicas": {
+			givenCandidate:  model.PodCandidate{DesiredReplicas: &one},
+			expectedRunning: true,
+		},
+		"workload scaled to zero": {
+			givenCandidate:       model.PodCandidate{DesiredReplicas: &zero},
+			expectedScaledToZero: true,
+		},
+		"suspended workload": {
+			givenCandidate: model.PodCandidate{Suspended: true},
+		},
+		"completed workload": {
+			givenCandidate: model.PodCandidate{DesiredReplicas: &one, Completed: true},
+		},
+		"workload with unknown number of replicas": {
+			givenCandidate:  model.PodCandidate{},
+			expectedRunning: true,
+		},
+	}
+
+	for tn, tc := range testCases {
+		t.Run(tn, func(t *testing.T) {
+			// WHEN
+			actualScaledToZero := tc.givenCandidate.ScaledToZero()
+			actualRunning := tc.givenCandidate.Running()
+			// THEN
+			assert.Equal(t, tc.expectedScaledToZero, actualScaledToZero)
+			assert.Equal(t, tc.expectedRunning, actualRunning)
+		})
+	}
+}
+
+func TestOwnerString(t *testing.T) {
+	// GIVEN
+	owner := model.Owner{Kind: "deployment", Namespace: "orders", Name: "web"}
+	// WHEN
+	actual := owner.String()
+	// THEN
+	assert.Equal(t, "deployment/orders/web", actual)
+}
+
+func TestSubtract(t *testing.T) {
+	// GIVEN
+	missingLabel := model.Violation{Namespace: "orders", NetworkPolicyName: "ingress-to-a", Type: model.ViolationInvalidLabel, Position: "Ingress [1:1]", Message: "no pods matching pod selector"}
+	ignoredRules := model.Violation{Namespace: "orders", NetworkPolicyName: "ingress-to-a", Type: model.ViolationIgnoredRules, Position: "Egress", Message: "Egress rules are ignored"}
+	otherPosition := missingLabel
+	otherPosition.Position = "Ingress [2:1]"
+	changedMessage := missingLabel
+	changedMessage.Message = "no pods matching pod selector, did you mean app=orders-b?"
+
+	testCases := map[string]struct {
+		givenA   []model.Violation
+		givenB   []model.Violation
+		expected []model.Violation
+	}{
+		"nothing to subtract":          {givenA: []model.Violation{missingLabel, ignoredRules}, expected: []model.Violation{missingLabel, ignoredRules}},
+		"subtracted violation":         {givenA: []model.Violation{missingLabel, ignoredRules}, givenB: []model.Violation{missingLabel}, expected: []model.Violation{ignoredRules}},
+		"violation with other message": {givenA: []model.Violation{changedMessage}, givenB: []model.Violation{missingLabel}},
+		"violation at other position":  {givenA: []model.Violation{otherPosition, ignoredRules}, givenB: []model.Violation{missingLabel}, expected: []model.Violation{otherPosition, ignoredRules}},
+	}
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			// WHEN
+			actual := model.Subtract(tc.givenA, tc.givenB)
+			// THEN
+			assert.Equal(t, tc.expected, actual)
+		})
+	}
+}
@@ -0,0 +1,35 @@
+package netpol
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	netv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	netlisters "k8s.io/client-go/listers/networking/v1"
+)
+
+// cachedService provides network policies from an informer cache instead of calling the API server.
+type cachedService struct {
+	lister netlisters.NetworkPolicyLister
+}
+
+func NewCachedService(lister netlisters.NetworkPolicyLister) *cachedService {
+	return &cachedService{lister: lister}
+}
+
+func (s *cachedService) GetNetworkPoliciesForNamespace(_ context.Context, ns string) ([]netv1.NetworkPolicy, error) {
+	items, err := s.lister.NetworkPolicies(ns).List(labels.Everything())
+	if err != nil {
+		return nil, fmt.Errorf("while listing cached network policies from namespace: %s: %w", ns, err)
+	}
+	sort.Slice(items, func(i, j int) bool {
+		return items[i].Name < items[j].Name
+	})
+	var response []netv1.NetworkPolicy
+	for _, np := range items {
+		response = append(response, *np)
+	}
+	return response, nil
+}
@@ -9,6 +9,8 @@ import (
 	typednetv1 "k8s.io/client-go/kubernetes/typed/networking/v1"
 )
 
+const listPageSize int64 = 500
+
 type service struct {
 	client typednetv1.NetworkPoliciesGetter
 }
@@ -18,12 +20,33 @@ func NewService(client typednetv1.NetworkPoliciesGetter) *service {
 }
 
 func (s *service) GetNetworkPoliciesForNamespace(ctx context.Context, ns string) ([]netv1.NetworkPolicy, error) {
+	response, err := s.list(ctx, ns)
+	if err != nil {
+		return nil, fmt.Errorf("while listing network policies from namespace: %s: %w", ns, err)
+	}
+	return response, nil
+}
+
+// GetNetworkPoliciesForAllNamespaces lists network policies with a single cluster-scoped call and groups them by namespace.
+func (s *service) GetNetworkPoliciesForAllNamespaces(ctx context.Context) (map[string][]netv1.NetworkPolicy, error) {
+	all, err := s.list(ctx, metav1.NamespaceAll)
+	if err != nil {
+		return nil, fmt.Errorf("while listing network policies from all namespaces: %w", err)
+	}
+	out := make(map[string][]netv1.NetworkPolicy)
+	for _, np := range all {
+		out[np.Namespace] = append(out[np.Namespace], np)
+	}
+	return out, nil
+}
+
+func (s *service) list(ctx context.Context, ns string) ([]netv1.NetworkPolicy, error) {
 	var response []netv1.NetworkPolicy
 	continueOption := ""
 	for {
-		list, err := s.client.NetworkPolicies(ns).List(ctx, metav1.ListOptions{Continue: continueOption})
+		list, err := s.client.NetworkPolicies(ns).List(ctx, metav1.ListOptions{Limit: listPageSize, Continue: continueOption})
 		if err != nil {
-			return nil, fmt.Errorf("while listing network policies from namespace: %s: %w", ns, err)
+			return nil, err
 		}
 		response = append(response, list.Items...)
 		continueOption = list.Continue
@@ -28,6 +28,21 @@ func TestServiceGetNetworkPoliciesForNamespace(t *testing.T) {
 
 }
 
+func TestServiceGetNetworkPoliciesForAllNamespaces(t *testing.T) {
+	// GIVEN
+	netPolOrders := fixNetPolPaymentB()
+	netPolOrders.Namespace = "orders"
+	fakeClientset := fake.NewSimpleClientset(fixNetPolPaymentA(), netPolOrders)
+	sut := netpol.NewService(fakeClientset.NetworkingV1())
+	// WHEN
+	actual, err := sut.GetNetworkPoliciesForAllNamespaces(context.Background())
+	// THEN
+	require.NoError(t, err)
+	require.Len(t, actual, 2)
+	assert.Equal(t, []v1.NetworkPolicy{*fixNetPolPaymentA()}, actual["payment"])
+	assert.Equal(t, []v1.NetworkPolicy{*netPolOrders}, actual["orders"])
+}
+
 func fixNetPolPaymentA() *v1.NetworkPolicy {
 	return &v1.NetworkPolicy{
 		ObjectMeta: metav1.ObjectMeta{
@@ -0,0 +1,35 @@
+package ns
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	corelisters "k8s.io/client-go/listers/core/v1"
+)
+
+// cachedService provides namespaces from an informer cache instead of calling the API server.
+type cachedService struct {
+	lister corelisters.NamespaceLister
+}
+
+func NewCached(lister corelisters.NamespaceLister) *cachedService {
+	return &cachedService{lister: lister}
+}
+
+func (s *cachedService) GetAllNamespaces(_ context.Context) ([]v1.Namespace, error) {
+	items, err := s.lister.List(labels.Everything())
+	if err != nil {
+		return nil, fmt.Errorf("while listing cached namespaces: %w", err)
+	}
+	sort.Slice(items, func(i, j int) bool {
+		return items[i].Name < items[j].Name
+	})
+	var response []v1.Namespace
+	for _, ns := range items {
+		response = append(response, *ns)
+	}
+	return response, nil
+}
@@ -0,0 +1,73 @@
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/aszecowka/netpolvalidator/internal/fix"
+)
+
+// jsonPatchOperation is a single operation of a JSON patch (RFC 6902).
+type jsonPatchOperation struct {
+	Op    string      `json:"op"`
+	Path  string      `json:"path"`
+	Value interface{} `json:"value"`
+}
+
+// FixedPolicyYAML returns the fixed network policy as a manifest ready to be applied, preceded by comments describing
+// the changes.
+func FixedPolicyYAML(f fix.PolicyFix) ([]byte, error) {
+	var comments []string
+	for _, c := range f.Changes {
+		comments = append(comments, c.Description)
+	}
+	return policyManifest(f.Fixed, comments)
+}
+
+// FixPatchJSON returns changes of the network policy as a JSON patch, which can be applied with
+// kubectl patch networkpolicy --type json.
+func FixPatchJSON(f fix.PolicyFix) ([]byte, error) {
+	var operations []jsonPatchOperation
+	for _, c := range f.Changes {
+		operations = append(operations, jsonPatchOperation{Op: "replace", Path: c.Path, Value: c.Value})
+	}
+	out, err := json.Marshal(operations)
+	if err != nil {
+		return nil, fmt.Errorf("while marshalling patch of network policy %s/%s: %w", f.Fixed.Namespace, f.Fixed.Name, err)
+	}
+	return out, nil
+}
+
+// WriteFixedPolicies writes fixed network policies as a multi-document YAML.
+func WriteFixedPolicies(w io.Writer, fixes []fix.PolicyFix) error {
+	for idx, f := range fixes {
+		out, err := FixedPolicyYAML(f)
+		if err != nil {
+			return err
+		}
+		if idx > 0 {
+			if _, err := fmt.Fprintln(w, "---"); err != nil {
+				return err
+			}
+		}
+		if _, err := w.Write(out); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// WriteFixPatches writes a kubectl patch command for every fixed network policy.
+func WriteFixPatches(w io.Writer, fixes []fix.PolicyFix) error {
+	for _, f := range fixes {
+		out, err := FixPatchJSON(f)
+		if err != nil {
+			return err
+		}
+		if _, err := fmt.Fprintf(w, "kubectl patch networkpolicy %s -n %s --type json -p '%s'\n", f.Fixed.Name, f.Fixed.Namespace, out); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,60 @@
+package output_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	netv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/aszecowka/netpolvalidator/internal/fix"
+	"github.com/aszecowka/netpolvalidator/internal/output"
+)
+
+func TestWriteFixedPolicies(t *testing.T) {
+	// GIVEN
+	actual := &bytes.Buffer{}
+	// WHEN
+	err := output.WriteFixedPolicies(actual, []fix.PolicyFix{fixPolicyFix("web"), fixPolicyFix("api")})
+	// THEN
+	require.NoError(t, err)
+	assert.Equal(t, getGoldenFileContent(t, "testdata/fixed_policies.yaml"), actual.String())
+}
+
+func TestWriteFixPatches(t *testing.T) {
+	// GIVEN
+	actual := &bytes.Buffer{}
+	// WHEN
+	err := output.WriteFixPatches(actual, []fix.PolicyFix{fixPolicyFix("web")})
+	// THEN
+	require.NoError(t, err)
+	assert.Equal(t, `kubectl patch networkpolicy web -n orders --type json -p '[{"op":"replace","path":"/spec/podSelector","value":{"matchLabels":{"app":"component-a"}}},{"op":"replace","path":"/spec/policyTypes","value":["Ingress","Egress"]}]'`+"\n", actual.String())
+}
+
+func fixPolicyFix(name string) fix.PolicyFix {
+	fixed := netv1.NetworkPolicy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            name,
+			Namespace:       "orders",
+			ResourceVersion: "42",
+			Labels:          map[string]string{"team": "orders"},
+			Annotations: map[string]string{
+				"kubectl.kubernetes.io/last-applied-configuration": "{}",
+				"owner": "orders-team",
+			},
+		},
+		Spec: netv1.NetworkPolicySpec{
+			PodSelector: metav1.LabelSelector{MatchLabels: map[string]string{"app": "component-a"}},
+			PolicyTypes: []netv1.PolicyType{netv1.PolicyTypeIngress, netv1.PolicyTypeEgress},
+		},
+	}
+	return fix.PolicyFix{
+		Fixed: fixed,
+		Changes: []fix.Change{
+			{Path: "/spec/podSelector", Value: fixed.Spec.PodSelector, Description: "pod selector: replaced app=componet-a with app=component-a"},
+			{Path: "/spec/policyTypes", Value: fixed.Spec.PolicyTypes, Description: "policyTypes: added Egress, so that their rules are not ignored"},
+		},
+	}
+}
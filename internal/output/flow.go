@@ -0,0 +1,26 @@
+package output
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/aszecowka/netpolvalidator/internal/flow"
+)
+
+// WriteBlockedConnections writes observed connections that current network policies would block, together with
+// network policies that decided about them.
+func WriteBlockedConnections(w io.Writer, blocked []flow.BlockedConnection) error {
+	if len(blocked) == 0 {
+		_, err := fmt.Fprintln(w, "Observed connections blocked by current network policies: none")
+		return err
+	}
+	fmt.Fprintln(w, "Observed connections blocked by current network policies:")
+	for _, b := range blocked {
+		c := b.Connection
+		_, err := fmt.Fprintf(w, "  - %s\n      egress: %s\n      ingress: %s\n", c, describeDirection(b.Verdict.Egress), describeDirection(b.Verdict.Ingress))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
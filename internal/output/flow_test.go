@@ -0,0 +1,49 @@
+package output_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+
+	"github.com/aszecowka/netpolvalidator/internal/connectivity"
+	"github.com/aszecowka/netpolvalidator/internal/flow"
+	"github.com/aszecowka/netpolvalidator/internal/model"
+	"github.com/aszecowka/netpolvalidator/internal/output"
+)
+
+func TestWriteBlockedConnections(t *testing.T) {
+	t.Run("blocked connections", func(t *testing.T) {
+		// GIVEN
+		givenBlocked := []flow.BlockedConnection{{
+			Connection: flow.Connection{
+				Source:      connectivity.Endpoint{Candidate: model.PodCandidate{Owner: model.Owner{Kind: "deployment", Namespace: "orders", Name: "web"}}},
+				Destination: connectivity.Endpoint{Candidate: model.PodCandidate{Owner: model.Owner{Kind: "deployment", Namespace: "payments", Name: "api"}}},
+				Port:        connectivity.Port{Protocol: v1.ProtocolTCP, Number: 9090},
+			},
+			Verdict: connectivity.Verdict{Ingress: connectivity.Direction{Selecting: []string{"allow-web-8080"}}},
+		}}
+		actual := &bytes.Buffer{}
+		// WHEN
+		err := output.WriteBlockedConnections(actual, givenBlocked)
+		// THEN
+		require.NoError(t, err)
+		assert.Equal(t, `Observed connections blocked by current network policies:
+  - deployment/orders/web -> deployment/payments/api on TCP/9090
+      egress: allowed, no network policy selects the pod
+      ingress: denied, selected by [allow-web-8080] but no rule allows it
+`, actual.String())
+	})
+
+	t.Run("no blocked connections", func(t *testing.T) {
+		// GIVEN
+		actual := &bytes.Buffer{}
+		// WHEN
+		err := output.WriteBlockedConnections(actual, nil)
+		// THEN
+		require.NoError(t, err)
+		assert.Equal(t, "Observed connections blocked by current network policies: none\n", actual.String())
+	})
+}
@@ -0,0 +1,62 @@
+package output
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/ghodss/yaml"
+	netv1 "k8s.io/api/networking/v1"
+
+	"github.com/aszecowka/netpolvalidator/internal/generate"
+)
+
+// kustomization is the subset of kustomization.yaml used for generated network policies.
+type kustomization struct {
+	APIVersion string   `json:"apiVersion"`
+	Kind       string   `json:"kind"`
+	Namespace  string   `json:"namespace,omitempty"`
+	Resources  []string `json:"resources"`
+}
+
+// GeneratedPolicyYAML returns the generated network policy as a YAML manifest.
+func GeneratedPolicyYAML(np netv1.NetworkPolicy) ([]byte, error) {
+	return policyManifest(np, nil)
+}
+
+// KustomizationYAML returns a kustomization.yaml with the given resources. Empty namespace means that
+// the kustomization does not set namespaces of its resources, e.g. because it groups kustomizations of many namespaces.
+func KustomizationYAML(namespace string, resources []string) ([]byte, error) {
+	out, err := yaml.Marshal(kustomization{
+		APIVersion: "kustomize.config.k8s.io/v1beta1",
+		Kind:       "Kustomization",
+		Namespace:  namespace,
+		Resources:  resources,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("while marshalling kustomization: %w", err)
+	}
+	return out, nil
+}
+
+// WriteGeneratedPolicies writes generated network policies of all namespaces as a multi-document YAML.
+func WriteGeneratedPolicies(w io.Writer, namespaces []generate.NamespacePolicies) error {
+	first := true
+	for _, ns := range namespaces {
+		for _, np := range ns.Policies {
+			out, err := GeneratedPolicyYAML(np)
+			if err != nil {
+				return err
+			}
+			if !first {
+				if _, err := fmt.Fprintln(w, "---"); err != nil {
+					return err
+				}
+			}
+			first = false
+			if _, err := w.Write(out); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
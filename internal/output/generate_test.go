@@ -0,0 +1,70 @@
+package output_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	netv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/aszecowka/netpolvalidator/internal/generate"
+	"github.com/aszecowka/netpolvalidator/internal/output"
+)
+
+func TestWriteGeneratedPolicies(t *testing.T) {
+	// GIVEN
+	givenNamespaces := []generate.NamespacePolicies{
+		{Namespace: "orders", Policies: []netv1.NetworkPolicy{fixDefaultDeny("orders")}},
+		{Namespace: "payments", Policies: []netv1.NetworkPolicy{fixDefaultDeny("payments")}},
+	}
+	actual := &bytes.Buffer{}
+	// WHEN
+	err := output.WriteGeneratedPolicies(actual, givenNamespaces)
+	// THEN
+	require.NoError(t, err)
+	assert.Equal(t, getGoldenFileContent(t, "testdata/generated_policies.yaml"), actual.String())
+}
+
+func TestKustomizationYAML(t *testing.T) {
+	t.Run("namespace", func(t *testing.T) {
+		// WHEN
+		actual, err := output.KustomizationYAML("orders", []string{"default-deny.yaml", "allow-dns.yaml"})
+		// THEN
+		require.NoError(t, err)
+		assert.Equal(t, `apiVersion: kustomize.config.k8s.io/v1beta1
+kind: Kustomization
+namespace: orders
+resources:
+- default-deny.yaml
+- allow-dns.yaml
+`, string(actual))
+	})
+
+	t.Run("all namespaces", func(t *testing.T) {
+		// WHEN
+		actual, err := output.KustomizationYAML("", []string{"orders", "payments"})
+		// THEN
+		require.NoError(t, err)
+		assert.Equal(t, `apiVersion: kustomize.config.k8s.io/v1beta1
+kind: Kustomization
+resources:
+- orders
+- payments
+`, string(actual))
+	})
+}
+
+func fixDefaultDeny(namespace string) netv1.NetworkPolicy {
+	return netv1.NetworkPolicy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "default-deny",
+			Namespace: namespace,
+			Labels:    map[string]string{"app.kubernetes.io/managed-by": "netpolvalidator"},
+		},
+		Spec: netv1.NetworkPolicySpec{
+			PolicyTypes: []netv1.PolicyType{netv1.PolicyTypeIngress, netv1.PolicyTypeEgress},
+		},
+	}
+}
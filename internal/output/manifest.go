@@ -0,0 +1,55 @@
+package output
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/ghodss/yaml"
+	netv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// lastAppliedAnnotation holds the configuration applied with kubectl apply, it is regenerated on the next apply.
+const lastAppliedAnnotation = "kubectl.kubernetes.io/last-applied-configuration"
+
+// policyManifest returns the network policy as a YAML manifest ready to be applied, preceded by the comments.
+// Fields set by the API server, like resourceVersion or managedFields, are dropped.
+func policyManifest(np netv1.NetworkPolicy, comments []string) ([]byte, error) {
+	annotations := make(map[string]string)
+	for key, value := range np.Annotations {
+		if key != lastAppliedAnnotation {
+			annotations[key] = value
+		}
+	}
+	if len(annotations) == 0 {
+		annotations = nil
+	}
+	manifest := netv1.NetworkPolicy{
+		TypeMeta: metav1.TypeMeta{APIVersion: netv1.SchemeGroupVersion.String(), Kind: "NetworkPolicy"},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        np.Name,
+			Namespace:   np.Namespace,
+			Labels:      np.Labels,
+			Annotations: annotations,
+		},
+		Spec: np.Spec,
+	}
+	obj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(&manifest)
+	if err != nil {
+		return nil, fmt.Errorf("while converting network policy %s/%s: %w", np.Namespace, np.Name, err)
+	}
+	unstructured.RemoveNestedField(obj, "metadata", "creationTimestamp")
+	out, err := yaml.Marshal(obj)
+	if err != nil {
+		return nil, fmt.Errorf("while marshalling network policy %s/%s: %w", np.Namespace, np.Name, err)
+	}
+
+	buf := &bytes.Buffer{}
+	for _, comment := range comments {
+		fmt.Fprintf(buf, "# %s\n", comment)
+	}
+	buf.Write(out)
+	return buf.Bytes(), nil
+}
@@ -10,10 +10,8 @@ import (
 	"github.com/aszecowka/netpolvalidator/internal/model"
 )
 
-var md = `# Network Policy Report
-
-## Violations
-
+var mdTables = `
+{{- define "violations" -}}
 Number of violations: {{ len .Violations }}
 {{- "\n"}}
 {{- if .Violations }}
@@ -23,8 +21,69 @@ Number of violations: {{ len .Violations }}
 {{- end }}
 {{- range .Violations }}
 | {{.Namespace}} | {{.NetworkPolicyName}} | {{.Type}} | {{.Message}} |
+{{- end }}
+{{- end }}
+
+{{- define "coverageGaps" -}}
+The following resources were not inspected, so the report for these namespaces may be incomplete.
+
+| Namespace | Kind | Reason |
+|-----------|------|--------|
+
+{{- range .CoverageGaps }}
+| {{ or .Namespace "all namespaces" }} | {{.Kind}} | {{.Reason}} |
+{{- end }}
+{{- end }}`
+
+var md = `# Network Policy Report
+
+## Violations
+
+{{ template "violations" . }}
+{{- if .State.CoverageGaps }}
+
+## Coverage Gaps
+
+{{ template "coverageGaps" .State }}
+{{- end }}`
+
+var mdClusters = `# Network Policy Report
+{{- range .Clusters }}
+
+## Cluster: {{ .Cluster }}
+{{- if .Err }}
+
+Not validated: {{ .Err }}
+{{- else }}
+
+### Violations
+
+{{ template "violations" . }}
+{{- if .State.CoverageGaps }}
+
+### Coverage Gaps
+
+{{ template "coverageGaps" .State }}
+{{- end }}
+{{- end }}
 {{- end }}`
 
+var mdMatrix = `# Connectivity Matrix
+
+Traffic on {{ .Port }}, sources in rows, destinations in columns.
+{{- if .Endpoints }}
+
+| From \ To |{{ range .Endpoints }} {{ . }} |{{ end }}
+|-----------|{{ range .Endpoints }}---|{{ end }}
+{{- range $i, $from := .Endpoints }}
+| {{ $from }} |{{ range $j, $_ := (index $.Allowed $i) }} {{ $.Cell $i $j }} |{{ end }}
+{{- end }}
+{{- else }}
+
+No pods found.
+{{- end }}
+`
+
 type Markdown struct{}
 
 type Data struct {
@@ -32,17 +91,38 @@ type Data struct {
 	Violations []model.Violation
 }
 
+type ClustersData struct {
+	Clusters []model.ClusterReport
+}
+
 func NewMarkdown() *Markdown {
 	return &Markdown{}
 }
 
 func (m *Markdown) Generate(ctx context.Context, state model.ClusterState, violations []model.Violation) (io.Reader, error) {
-	tpl, err := template.New("net_pol_report").Parse(md)
+	return m.execute(md, Data{State: state, Violations: violations})
+}
+
+// GenerateForClusters generates a single report with violations grouped by cluster.
+func (m *Markdown) GenerateForClusters(ctx context.Context, reports []model.ClusterReport) (io.Reader, error) {
+	return m.execute(mdClusters, ClustersData{Clusters: reports})
+}
+
+// GenerateMatrix generates a connectivity matrix between pods.
+func (m *Markdown) GenerateMatrix(ctx context.Context, matrix Matrix) (io.Reader, error) {
+	return m.execute(mdMatrix, matrix)
+}
+
+func (m *Markdown) execute(text string, data interface{}) (io.Reader, error) {
+	tpl, err := template.New("net_pol_report").Parse(mdTables)
 	if err != nil {
 		return nil, fmt.Errorf("while parsing markdown report: %w", err)
 	}
+	if _, err := tpl.Parse(text); err != nil {
+		return nil, fmt.Errorf("while parsing markdown report: %w", err)
+	}
 	buf := bytes.Buffer{}
-	if err := tpl.Execute(&buf, Data{State: state, Violations: violations}); err != nil {
+	if err := tpl.Execute(&buf, data); err != nil {
 		return nil, fmt.Errorf("while generating markdown report: %w", err)
 	}
 
@@ -2,6 +2,7 @@ package output_test
 
 import (
 	"context"
+	"errors"
 	"io/ioutil"
 	"os"
 	"testing"
@@ -73,6 +74,69 @@ func TestGenerateMarkdownReport(t *testing.T) {
 		assert.Equal(t, expected, string(actualBytes))
 	})
 
+	t.Run("coverage gaps", func(t *testing.T) {
+		// GIVEN
+		givenState := model.ClusterState{
+			CoverageGaps: []model.CoverageGap{
+				{Kind: "cronjob", Reason: "NotFound"},
+				{Namespace: "payments", Kind: "pod", Reason: "Forbidden"},
+			},
+		}
+		// WHEN
+		actual, err := sut.Generate(context.Background(), givenState, []model.Violation{
+			{
+				Namespace:         "orders",
+				Type:              model.ViolationInvalidLabel,
+				NetworkPolicyName: "ingress-all",
+				Message:           "something went wrong",
+			},
+		})
+		// THEN
+		require.NoError(t, err)
+		require.NotNil(t, actual)
+		actualBytes, err := ioutil.ReadAll(actual)
+		require.NoError(t, err)
+		expected := getGoldenFileContent(t, "testdata/coverage_gaps.md")
+		assert.Equal(t, expected, string(actualBytes))
+	})
+
+}
+
+func TestGenerateMarkdownReportForClusters(t *testing.T) {
+	// GIVEN
+	sut := output.NewMarkdown()
+	givenReports := []model.ClusterReport{
+		{
+			Cluster: "prod",
+			Violations: []model.Violation{
+				{
+					Namespace:         "orders",
+					Type:              model.ViolationInvalidLabel,
+					NetworkPolicyName: "ingress-all",
+					Message:           "something went wrong",
+				},
+			},
+			State: model.ClusterState{
+				CoverageGaps: []model.CoverageGap{{Namespace: "payments", Kind: "pod", Reason: "Forbidden"}},
+			},
+		},
+		{
+			Cluster: "dev",
+			Err:     errors.New("connection refused"),
+		},
+		{
+			Cluster: "staging",
+		},
+	}
+	// WHEN
+	actual, err := sut.GenerateForClusters(context.Background(), givenReports)
+	// THEN
+	require.NoError(t, err)
+	require.NotNil(t, actual)
+	actualBytes, err := ioutil.ReadAll(actual)
+	require.NoError(t, err)
+	expected := getGoldenFileContent(t, "testdata/many_clusters.md")
+	assert.Equal(t, expected, string(actualBytes))
 }
 
 func getGoldenFileContent(t *testing.T, path string) string {
@@ -0,0 +1,147 @@
+package output
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	netv1 "k8s.io/api/networking/v1"
+
+	"github.com/aszecowka/netpolvalidator/internal/connectivity"
+	"github.com/aszecowka/netpolvalidator/internal/model"
+)
+
+const (
+	allowed          = "allow"
+	partiallyAllowed = "partial"
+	denied           = "deny"
+)
+
+// Matrix holds verdicts for traffic between every pair of endpoints.
+// Allowed[i][j] tells whether traffic from Endpoints[i] to Endpoints[j] is allowed.
+// Partial[i][j] tells whether it is allowed on some ports only, it is nil when the matrix is for a single port.
+type Matrix struct {
+	Endpoints []string
+	Allowed   [][]bool
+	Partial   [][]bool
+	Port      string
+}
+
+// Cell returns the verdict for traffic from Endpoints[i] to Endpoints[j]: allow, partial or deny.
+func (m Matrix) Cell(i, j int) string {
+	switch {
+	case m.Allowed[i][j]:
+		return allowed
+	case m.Partial != nil && m.Partial[i][j]:
+		return partiallyAllowed
+	default:
+		return denied
+	}
+}
+
+// WriteMatrix writes the connectivity matrix as a table with sources in rows and destinations in columns.
+func WriteMatrix(w io.Writer, m Matrix) error {
+	if len(m.Endpoints) == 0 {
+		_, err := fmt.Fprintln(w, "No pods found")
+		return err
+	}
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintf(tw, "FROM \\ TO (%s)\t%s\n", m.Port, strings.Join(m.Endpoints, "\t"))
+	for i, from := range m.Endpoints {
+		cells := make([]string, 0, len(m.Endpoints))
+		for j := range m.Endpoints {
+			cells = append(cells, m.Cell(i, j))
+		}
+		fmt.Fprintf(tw, "%s\t%s\n", from, strings.Join(cells, "\t"))
+	}
+	return tw.Flush()
+}
+
+// WriteVerdict writes whether traffic between two endpoints is allowed, together with network policies that decided about it.
+func WriteVerdict(w io.Writer, from, to string, port *connectivity.Port, v connectivity.Verdict) error {
+	answer := "no"
+	switch {
+	case v.Allowed():
+		answer = "yes"
+	case v.PartiallyAllowed():
+		answer = fmt.Sprintf("partially, on [%s] only", strings.Join(v.Ports(), ", "))
+	}
+	_, err := fmt.Fprintf(w, "%s\n  egress from %s on %s: %s\n  ingress to %s on %s: %s\n",
+		answer,
+		from, port, describeDirection(v.Egress),
+		to, port, describeDirection(v.Ingress))
+	return err
+}
+
+// WriteExplanation writes a human readable description of the network policy and its violations.
+func WriteExplanation(w io.Writer, e connectivity.Explanation, violations []model.Violation) error {
+	np := e.NetworkPolicy
+	fmt.Fprintf(w, "Network policy: %s/%s\n", np.Namespace, np.Name)
+	var types []string
+	for _, t := range e.PolicyTypes {
+		types = append(types, string(t))
+	}
+	fmt.Fprintf(w, "Policy types: %s\n", strings.Join(types, ", "))
+	fmt.Fprintf(w, "Selected pods: %s\n", listOrNone(e.Selected))
+	writeRules(w, "Ingress", "from", netv1.PolicyTypeIngress, e.PolicyTypes, e.Ingress)
+	writeRules(w, "Egress", "to", netv1.PolicyTypeEgress, e.PolicyTypes, e.Egress)
+
+	if len(violations) == 0 {
+		_, err := fmt.Fprintln(w, "Violations: none")
+		return err
+	}
+	fmt.Fprintln(w, "Violations:")
+	for _, v := range violations {
+		fmt.Fprintf(w, "  - %s: %s\n", v.Type, v.Message)
+	}
+	return nil
+}
+
+func writeRules(w io.Writer, title, peerPrefix string, policyType netv1.PolicyType, policyTypes []netv1.PolicyType, rules []connectivity.RuleExplanation) {
+	restricted := false
+	for _, t := range policyTypes {
+		if t == policyType {
+			restricted = true
+		}
+	}
+	switch {
+	case !restricted:
+		fmt.Fprintf(w, "%s: not restricted\n", title)
+		return
+	case len(rules) == 0:
+		fmt.Fprintf(w, "%s: all traffic denied\n", title)
+		return
+	}
+
+	fmt.Fprintf(w, "%s rules:\n", title)
+	for i, r := range rules {
+		fmt.Fprintf(w, "  %d. ports: %s\n", i+1, r.Ports)
+		if len(r.Peers) == 0 {
+			fmt.Fprintf(w, "     %s anywhere\n", peerPrefix)
+		}
+		for _, p := range r.Peers {
+			fmt.Fprintf(w, "     %s %s: %s\n", peerPrefix, p.Description, listOrNone(p.Matching))
+		}
+	}
+}
+
+func describeDirection(d connectivity.Direction) string {
+	switch {
+	case len(d.Selecting) == 0:
+		return "allowed, no network policy selects the pod"
+	case len(d.Allowing) > 0:
+		return fmt.Sprintf("allowed by [%s]", strings.Join(d.Allowing, ", "))
+	case len(d.PartiallyAllowing) > 0:
+		return fmt.Sprintf("allowed only on [%s] by [%s]", strings.Join(d.Ports, ", "), strings.Join(d.PartiallyAllowing, ", "))
+	default:
+		return fmt.Sprintf("denied, selected by [%s] but no rule allows it", strings.Join(d.Selecting, ", "))
+	}
+}
+
+func listOrNone(items []string) string {
+	if len(items) == 0 {
+		return "none"
+	}
+	return strings.Join(items, ", ")
+}
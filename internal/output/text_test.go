@@ -0,0 +1,115 @@
+package output_test
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	netv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/aszecowka/netpolvalidator/internal/connectivity"
+	"github.com/aszecowka/netpolvalidator/internal/model"
+	"github.com/aszecowka/netpolvalidator/internal/output"
+)
+
+func TestWriteMatrix(t *testing.T) {
+	// GIVEN
+	givenMatrix := fixMatrix()
+	actual := bytes.Buffer{}
+	// WHEN
+	err := output.WriteMatrix(&actual, givenMatrix)
+	// THEN
+	require.NoError(t, err)
+	assert.Equal(t, getGoldenFileContent(t, "testdata/matrix.txt"), actual.String())
+}
+
+func TestGenerateMarkdownMatrix(t *testing.T) {
+	// WHEN
+	actual, err := output.NewMarkdown().GenerateMatrix(context.Background(), fixMatrix())
+	// THEN
+	require.NoError(t, err)
+	actualBytes, err := ioutil.ReadAll(actual)
+	require.NoError(t, err)
+	assert.Equal(t, getGoldenFileContent(t, "testdata/matrix.md"), string(actualBytes))
+}
+
+func TestWriteVerdict(t *testing.T) {
+	// GIVEN
+	givenVerdict := connectivity.Verdict{
+		Ingress: connectivity.Direction{Selecting: []string{"default-deny", "allow-from-frontend"}},
+	}
+	actual := bytes.Buffer{}
+	// WHEN
+	err := output.WriteVerdict(&actual, "deployment/frontend/web", "deployment/orders/a", &connectivity.Port{Protocol: v1.ProtocolTCP, Number: 8080}, givenVerdict)
+	// THEN
+	require.NoError(t, err)
+	assert.Equal(t, `no
+  egress from deployment/frontend/web on TCP/8080: allowed, no network policy selects the pod
+  ingress to deployment/orders/a on TCP/8080: denied, selected by [default-deny, allow-from-frontend] but no rule allows it
+`, actual.String())
+}
+
+func TestWritePartialVerdict(t *testing.T) {
+	// GIVEN
+	givenVerdict := connectivity.Verdict{
+		Ingress: connectivity.Direction{
+			Selecting:         []string{"allow-from-frontend"},
+			PartiallyAllowing: []string{"allow-from-frontend"},
+			Ports:             []string{"TCP/8080"},
+		},
+	}
+	actual := bytes.Buffer{}
+	// WHEN
+	err := output.WriteVerdict(&actual, "deployment/frontend/web", "deployment/orders/a", nil, givenVerdict)
+	// THEN
+	require.NoError(t, err)
+	assert.Equal(t, `partially, on [TCP/8080] only
+  egress from deployment/frontend/web on any port: allowed, no network policy selects the pod
+  ingress to deployment/orders/a on any port: allowed only on [TCP/8080] by [allow-from-frontend]
+`, actual.String())
+}
+
+func TestWriteExplanation(t *testing.T) {
+	// GIVEN
+	givenExplanation := connectivity.Explanation{
+		NetworkPolicy: netv1.NetworkPolicy{ObjectMeta: metav1.ObjectMeta{Name: "allow-from-frontend", Namespace: "orders"}},
+		PolicyTypes:   []netv1.PolicyType{netv1.PolicyTypeIngress, netv1.PolicyTypeEgress},
+		Selected:      []string{"deployment/orders/a"},
+		Ingress: []connectivity.RuleExplanation{
+			{
+				Ports: "TCP/8080",
+				Peers: []connectivity.PeerExplanation{
+					{Description: "all pods in namespaces [name=frontend]", Matching: []string{"deployment/frontend/web"}},
+					{Description: "pods [app=b] in namespace orders"},
+				},
+			},
+			{Ports: "all ports"},
+		},
+	}
+	givenViolations := []model.Violation{{
+		Namespace:         "orders",
+		NetworkPolicyName: "allow-from-frontend",
+		Type:              model.ViolationInvalidLabel,
+		Message:           "no pods matching labels for Ingress rule [0:1]",
+	}}
+	actual := bytes.Buffer{}
+	// WHEN
+	err := output.WriteExplanation(&actual, givenExplanation, givenViolations)
+	// THEN
+	require.NoError(t, err)
+	assert.Equal(t, getGoldenFileContent(t, "testdata/explanation.txt"), actual.String())
+}
+
+func fixMatrix() output.Matrix {
+	return output.Matrix{
+		Endpoints: []string{"deployment/orders/a", "deployment/orders/b"},
+		Allowed:   [][]bool{{true, false}, {false, true}},
+		Partial:   [][]bool{{false, false}, {true, false}},
+		Port:      "any port",
+	}
+}
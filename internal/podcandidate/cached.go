@@ -0,0 +1,234 @@
+package podcandidate
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	appslisters "k8s.io/client-go/listers/apps/v1"
+	batchlisters "k8s.io/client-go/listers/batch/v1"
+	batchv1beta1listers "k8s.io/client-go/listers/batch/v1beta1"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/aszecowka/netpolvalidator/internal/model"
+)
+
+// CachedFetcher provides pod candidates from an informer cache instead of calling the API server.
+type CachedFetcher struct {
+	list func(ns string) ([]model.PodCandidate, error)
+}
+
+func (cf *CachedFetcher) GetPodCandidatesForNamespace(_ context.Context, ns string) ([]model.PodCandidate, error) {
+	out, err := cf.list(ns)
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(out, func(i, j int) bool {
+		return out[i].OwnerName() < out[j].OwnerName()
+	})
+	return out, nil
+}
+
+func NewCachedCronjobFetcher(lister batchv1beta1listers.CronJobLister) *CachedFetcher {
+	cf := &CronjobFetcher{}
+	return &CachedFetcher{list: func(ns string) ([]model.PodCandidate, error) {
+		items, err := lister.CronJobs(ns).List(labels.Everything())
+		if err != nil {
+			return nil, fmt.Errorf("while getting cached cronjobs for namespace %s: %w", ns, err)
+		}
+		var out []model.PodCandidate
+		for _, cj := range items {
+			out = append(out, cf.convert(*cj))
+		}
+		return out, nil
+	}}
+}
+
+// NewCachedCronjobV1Fetcher reads batch/v1 cronjobs from the cache of a dynamic informer, see CronjobV1Fetcher.
+func NewCachedCronjobV1Fetcher(lister cache.GenericLister) *CachedFetcher {
+	cf := &CronjobFetcher{}
+	return &CachedFetcher{list: func(ns string) ([]model.PodCandidate, error) {
+		items, err := lister.ByNamespace(ns).List(labels.Everything())
+		if err != nil {
+			return nil, fmt.Errorf("while getting cached cronjobs for namespace %s: %w", ns, err)
+		}
+		var out []model.PodCandidate
+		for _, item := range items {
+			obj, ok := item.(*unstructured.Unstructured)
+			if !ok {
+				return nil, fmt.Errorf("unexpected type of cached cronjob: %T", item)
+			}
+			cj, err := fromUnstructuredCronjob(obj)
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, cf.convert(cj))
+		}
+		return out, nil
+	}}
+}
+
+func NewCachedDaemonsetFetcher(lister appslisters.DaemonSetLister) *CachedFetcher {
+	df := &DaemonsetFetcher{}
+	return &CachedFetcher{list: func(ns string) ([]model.PodCandidate, error) {
+		items, err := lister.DaemonSets(ns).List(labels.Everything())
+		if err != nil {
+			return nil, fmt.Errorf("while gettting cached daemonsets from namespace: %s: %w", ns, err)
+		}
+		var out []model.PodCandidate
+		for _, d := range items {
+			out = append(out, df.convert(*d))
+		}
+		return out, nil
+	}}
+}
+
+func NewCachedDeploymentsFetcher(lister appslisters.DeploymentLister) *CachedFetcher {
+	df := &DeploymentsFetcher{}
+	return &CachedFetcher{list: func(ns string) ([]model.PodCandidate, error) {
+		items, err := lister.Deployments(ns).List(labels.Everything())
+		if err != nil {
+			return nil, fmt.Errorf("while gettting cached deployments from namespace: %s: %w", ns, err)
+		}
+		var out []model.PodCandidate
+		for _, d := range items {
+			out = append(out, df.convert(*d))
+		}
+		return out, nil
+	}}
+}
+
+func NewCachedJobFetcher(lister batchlisters.JobLister) *CachedFetcher {
+	jf := &JobFetcher{}
+	return &CachedFetcher{list: func(ns string) ([]model.PodCandidate, error) {
+		items, err := lister.Jobs(ns).List(labels.Everything())
+		if err != nil {
+			return nil, fmt.Errorf("while getting cached jobs for namespace %s: %w", ns, err)
+		}
+		var out []model.PodCandidate
+		for _, j := range items {
+			out = append(out, jf.convert(*j))
+		}
+		return out, nil
+	}}
+}
+
+func NewCachedPodsFetcher(lister corelisters.PodLister) *CachedFetcher {
+	pf := &PodsFetcher{}
+	return &CachedFetcher{list: func(ns string) ([]model.PodCandidate, error) {
+		items, err := lister.Pods(ns).List(labels.Everything())
+		if err != nil {
+			return nil, fmt.Errorf("while gettting cached pods from namespace: %s: %w", ns, err)
+		}
+		var out []model.PodCandidate
+		for _, p := range items {
+			out = append(out, pf.convert(*p))
+		}
+		return out, nil
+	}}
+}
+
+func NewCachedStatefulsetsFetcher(lister appslisters.StatefulSetLister) *CachedFetcher {
+	sf := &StatefulsetFetcher{}
+	return &CachedFetcher{list: func(ns string) ([]model.PodCandidate, error) {
+		items, err := lister.StatefulSets(ns).List(labels.Everything())
+		if err != nil {
+			return nil, fmt.Errorf("while gettting cached statefulsets from namespace: %s: %w", ns, err)
+		}
+		var out []model.PodCandidate
+		for _, s := range items {
+			out = append(out, sf.convert(*s))
+		}
+		return out, nil
+	}}
+}
+
+func NewCachedReplicasetFetcher(lister appslisters.ReplicaSetLister) *CachedFetcher {
+	rf := &ReplicasetFetcher{}
+	return &CachedFetcher{list: func(ns string) ([]model.PodCandidate, error) {
+		items, err := lister.ReplicaSets(ns).List(labels.Everything())
+		if err != nil {
+			return nil, fmt.Errorf("while gettting cached replicasets from namespace: %s: %w", ns, err)
+		}
+		var out []model.PodCandidate
+		for _, rs := range items {
+			if rf.isBare(*rs) {
+				out = append(out, rf.convert(*rs))
+			}
+		}
+		return out, nil
+	}}
+}
+
+func NewCachedReplicationControllerFetcher(lister corelisters.ReplicationControllerLister) *CachedFetcher {
+	rf := &ReplicationControllerFetcher{}
+	return &CachedFetcher{list: func(ns string) ([]model.PodCandidate, error) {
+		items, err := lister.ReplicationControllers(ns).List(labels.Everything())
+		if err != nil {
+			return nil, fmt.Errorf("while gettting cached replication controllers from namespace: %s: %w", ns, err)
+		}
+		var out []model.PodCandidate
+		for _, rc := range items {
+			out = append(out, rf.convert(*rc))
+		}
+		return out, nil
+	}}
+}
+
+// NewCachedRolloutFetcher reads Argo Rollouts from the cache of a dynamic informer, see RolloutFetcher.
+func NewCachedRolloutFetcher(lister cache.GenericLister) *CachedFetcher {
+	rf := &RolloutFetcher{}
+	return &CachedFetcher{list: func(ns string) ([]model.PodCandidate, error) {
+		items, err := lister.ByNamespace(ns).List(labels.Everything())
+		if err != nil {
+			return nil, fmt.Errorf("while getting cached rollouts for namespace %s: %w", ns, err)
+		}
+		var out []model.PodCandidate
+		for _, item := range items {
+			obj, ok := item.(*unstructured.Unstructured)
+			if !ok {
+				return nil, fmt.Errorf("unexpected type of cached rollout: %T", item)
+			}
+			if candidate, ok := rf.convert(*obj); ok {
+				out = append(out, candidate)
+			}
+		}
+		return out, nil
+	}}
+}
+
+// NewCachedCustomResourceFetcher reads custom resources from the cache of a dynamic informer, see CustomResourceFetcher.
+func NewCachedCustomResourceFetcher(lister cache.GenericLister, definition CustomResource) (*CachedFetcher, error) {
+	if _, err := parseLabelsPath(definition); err != nil {
+		return nil, err
+	}
+	cf := &CustomResourceFetcher{definition: definition, resource: definition.GroupVersionResource()}
+	return &CachedFetcher{list: func(ns string) ([]model.PodCandidate, error) {
+		items, err := lister.ByNamespace(ns).List(labels.Everything())
+		if err != nil {
+			return nil, fmt.Errorf("while getting cached %s for namespace %s: %w", cf.resource.String(), ns, err)
+		}
+		labelsPath, err := parseLabelsPath(definition)
+		if err != nil {
+			return nil, err
+		}
+		var out []model.PodCandidate
+		for _, item := range items {
+			obj, ok := item.(*unstructured.Unstructured)
+			if !ok {
+				return nil, fmt.Errorf("unexpected type of cached %s: %T", cf.resource.String(), item)
+			}
+			candidate, ok, err := cf.convert(labelsPath, *obj)
+			if err != nil {
+				return nil, err
+			}
+			if ok {
+				out = append(out, candidate)
+			}
+		}
+		return out, nil
+	}}, nil
+}
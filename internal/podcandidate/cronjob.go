@@ -20,12 +20,37 @@ func NewCronjobFetcher(client v1beta1.CronJobsGetter) *CronjobFetcher {
 }
 
 func (cf *CronjobFetcher) GetPodCandidatesForNamespace(ctx context.Context, ns string) ([]model.PodCandidate, error) {
+	allCronjobs, err := cf.list(ctx, ns)
+	if err != nil {
+		return nil, fmt.Errorf("while getting cronjobs for namespace %s: %w", ns, err)
+	}
+	var out []model.PodCandidate
+	for _, cj := range allCronjobs {
+		out = append(out, cf.convert(cj))
+	}
+	return out, nil
+}
+
+// GetPodCandidatesForAllNamespaces lists cronjobs with a single cluster-scoped call and groups them by namespace.
+func (cf *CronjobFetcher) GetPodCandidatesForAllNamespaces(ctx context.Context) (map[string][]model.PodCandidate, error) {
+	allCronjobs, err := cf.list(ctx, v1.NamespaceAll)
+	if err != nil {
+		return nil, fmt.Errorf("while getting cronjobs for all namespaces: %w", err)
+	}
+	out := make(map[string][]model.PodCandidate)
+	for _, cj := range allCronjobs {
+		out[cj.Namespace] = append(out[cj.Namespace], cf.convert(cj))
+	}
+	return out, nil
+}
+
+func (cf *CronjobFetcher) list(ctx context.Context, ns string) ([]v1beta12.CronJob, error) {
 	var allCronjobs []v1beta12.CronJob
 	continueOption := ""
 	for {
-		cronjobs, err := cf.client.CronJobs(ns).List(ctx, v1.ListOptions{Continue: continueOption})
+		cronjobs, err := cf.client.CronJobs(ns).List(ctx, v1.ListOptions{Limit: listPageSize, Continue: continueOption})
 		if err != nil {
-			return nil, fmt.Errorf("while getting cronjobs for namespace %s: %w", ns, err)
+			return nil, err
 		}
 		allCronjobs = append(allCronjobs, cronjobs.Items...)
 		continueOption = cronjobs.Continue
@@ -33,18 +58,19 @@ func (cf *CronjobFetcher) GetPodCandidatesForNamespace(ctx context.Context, ns s
 			break
 		}
 	}
-
-	var out []model.PodCandidate
-	for _, cj := range allCronjobs {
-		out = append(out, cf.convert(cj))
-	}
-
-	return out, nil
+	return allCronjobs, nil
 }
 
 func (cf *CronjobFetcher) convert(cronjob v1beta12.CronJob) model.PodCandidate {
+	podSpec := cronjob.Spec.JobTemplate.Spec.Template.Spec
 	return model.PodCandidate{
-		Labels:    cronjob.Spec.JobTemplate.Spec.Template.Labels,
-		OwnerName: getOwnerName(WorkloadCronjob, cronjob.Namespace, cronjob.Name),
+		Labels: cronjob.Spec.JobTemplate.Spec.Template.Labels,
+		Owner:  newOwner(WorkloadCronjob, cronjob.Namespace, cronjob.Name),
+
+		InjectedLabels: cronjobInjectedLabels(),
+		ReadyReplicas:  int32(len(cronjob.Status.Active)),
+		ServiceAccount: podSpec.ServiceAccountName,
+		NodeSelector:   podSpec.NodeSelector,
+		Suspended:      cronjob.Spec.Suspend != nil && *cronjob.Spec.Suspend,
 	}
 }
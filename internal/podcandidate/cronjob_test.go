@@ -28,16 +28,30 @@ func TestPodCandidatesFromCronjobs(t *testing.T) {
 	require.NoError(t, err)
 	require.Len(t, actual, 2)
 	assert.Contains(t, actual, model.PodCandidate{
-		OwnerName: "cronjob/orders/cron-job-a",
+		Owner: model.Owner{Kind: "cronjob", Namespace: "orders", Name: "cron-job-a"},
 		Labels: map[string]string{
 			"app": "app-a",
 		},
+		InjectedLabels: map[string][]string{
+			"job-name":                           nil,
+			"controller-uid":                     nil,
+			"batch.kubernetes.io/job-name":       nil,
+			"batch.kubernetes.io/controller-uid": nil,
+		},
 	})
 	assert.Contains(t, actual, model.PodCandidate{
-		OwnerName: "cronjob/orders/cron-job-b",
+		Owner: model.Owner{Kind: "cronjob", Namespace: "orders", Name: "cron-job-b"},
 		Labels: map[string]string{
 			"app": "app-b",
 		},
+		ServiceAccount: "reporter",
+		Suspended:      true,
+		InjectedLabels: map[string][]string{
+			"job-name":                           nil,
+			"controller-uid":                     nil,
+			"batch.kubernetes.io/job-name":       nil,
+			"batch.kubernetes.io/controller-uid": nil,
+		},
 	})
 }
 
@@ -64,12 +78,14 @@ func fixCronJobA() v1beta1.CronJob {
 }
 
 func fixCronJobB() v1beta1.CronJob {
+	suspend := true
 	return v1beta1.CronJob{
 		ObjectMeta: v1.ObjectMeta{
 			Name:      "cron-job-b",
 			Namespace: "orders",
 		},
 		Spec: v1beta1.CronJobSpec{
+			Suspend: &suspend,
 			JobTemplate: v1beta1.JobTemplateSpec{
 				Spec: v12.JobSpec{
 					Template: v13.PodTemplateSpec{
@@ -78,6 +94,9 @@ func fixCronJobB() v1beta1.CronJob {
 								"app": "app-b",
 							},
 						},
+						Spec: v13.PodSpec{
+							ServiceAccountName: "reporter",
+						},
 					},
 				},
 			},
@@ -0,0 +1,80 @@
+package podcandidate
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/api/batch/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/dynamic"
+
+	"github.com/aszecowka/netpolvalidator/internal/model"
+)
+
+// CronjobV1Fetcher provides pod candidates from batch/v1 cronjobs. The typed client does not support them yet,
+// so they are listed with the dynamic client and converted to batch/v1beta1 cronjobs, which have the same schema.
+type CronjobV1Fetcher struct {
+	client dynamic.Interface
+}
+
+func NewCronjobV1Fetcher(client dynamic.Interface) *CronjobV1Fetcher {
+	return &CronjobV1Fetcher{client: client}
+}
+
+func (cf *CronjobV1Fetcher) GetPodCandidatesForNamespace(ctx context.Context, ns string) ([]model.PodCandidate, error) {
+	allCronjobs, err := cf.list(ctx, ns)
+	if err != nil {
+		return nil, fmt.Errorf("while getting cronjobs for namespace %s: %w", ns, err)
+	}
+	var out []model.PodCandidate
+	for _, cj := range allCronjobs {
+		out = append(out, (&CronjobFetcher{}).convert(cj))
+	}
+	return out, nil
+}
+
+// GetPodCandidatesForAllNamespaces lists cronjobs with a single cluster-scoped call and groups them by namespace.
+func (cf *CronjobV1Fetcher) GetPodCandidatesForAllNamespaces(ctx context.Context) (map[string][]model.PodCandidate, error) {
+	allCronjobs, err := cf.list(ctx, metav1.NamespaceAll)
+	if err != nil {
+		return nil, fmt.Errorf("while getting cronjobs for all namespaces: %w", err)
+	}
+	out := make(map[string][]model.PodCandidate)
+	for _, cj := range allCronjobs {
+		out[cj.Namespace] = append(out[cj.Namespace], (&CronjobFetcher{}).convert(cj))
+	}
+	return out, nil
+}
+
+func (cf *CronjobV1Fetcher) list(ctx context.Context, ns string) ([]v1beta1.CronJob, error) {
+	var allCronjobs []v1beta1.CronJob
+	continueOption := ""
+	for {
+		list, err := cf.client.Resource(CronjobsV1Resource).Namespace(ns).List(ctx, metav1.ListOptions{Limit: listPageSize, Continue: continueOption})
+		if err != nil {
+			return nil, err
+		}
+		for _, item := range list.Items {
+			cj, err := fromUnstructuredCronjob(&item)
+			if err != nil {
+				return nil, err
+			}
+			allCronjobs = append(allCronjobs, cj)
+		}
+		continueOption = list.GetContinue()
+		if continueOption == "" {
+			break
+		}
+	}
+	return allCronjobs, nil
+}
+
+func fromUnstructuredCronjob(obj *unstructured.Unstructured) (v1beta1.CronJob, error) {
+	cj := v1beta1.CronJob{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, &cj); err != nil {
+		return v1beta1.CronJob{}, fmt.Errorf("while converting cronjob %s/%s: %w", obj.GetNamespace(), obj.GetName(), err)
+	}
+	return cj, nil
+}
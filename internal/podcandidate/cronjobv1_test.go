@@ -0,0 +1,81 @@
+package podcandidate_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+
+	"github.com/aszecowka/netpolvalidator/internal/model"
+	"github.com/aszecowka/netpolvalidator/internal/podcandidate"
+)
+
+func TestPodCandidatesFromCronjobsV1(t *testing.T) {
+	// GIVEN
+	fakeClient := dynamicfake.NewSimpleDynamicClient(runtime.NewScheme(), fixCronjobV1("orders", "cron-job-a"), fixCronjobV1("payments", "cron-job-b"))
+	sut := podcandidate.NewCronjobV1Fetcher(fakeClient)
+	// WHEN
+	actual, err := sut.GetPodCandidatesForNamespace(context.Background(), "orders")
+	// THEN
+	require.NoError(t, err)
+	assert.Equal(t, []model.PodCandidate{{
+		Owner: model.Owner{Kind: "cronjob", Namespace: "orders", Name: "cron-job-a"},
+		Labels: map[string]string{
+			"app": "app-a",
+		},
+		InjectedLabels: map[string][]string{
+			"job-name":                           nil,
+			"controller-uid":                     nil,
+			"batch.kubernetes.io/job-name":       nil,
+			"batch.kubernetes.io/controller-uid": nil,
+		},
+		ServiceAccount: "reporter",
+		Suspended:      true,
+	}}, actual)
+}
+
+func TestPodCandidatesFromCronjobsV1InAllNamespaces(t *testing.T) {
+	// GIVEN
+	fakeClient := dynamicfake.NewSimpleDynamicClient(runtime.NewScheme(), fixCronjobV1("orders", "cron-job-a"), fixCronjobV1("payments", "cron-job-b"))
+	sut := podcandidate.NewCronjobV1Fetcher(fakeClient)
+	// WHEN
+	actual, err := sut.GetPodCandidatesForAllNamespaces(context.Background())
+	// THEN
+	require.NoError(t, err)
+	require.Len(t, actual, 2)
+	require.Len(t, actual["orders"], 1)
+	assert.Equal(t, "cronjob/orders/cron-job-a", actual["orders"][0].OwnerName())
+	require.Len(t, actual["payments"], 1)
+	assert.Equal(t, "cronjob/payments/cron-job-b", actual["payments"][0].OwnerName())
+}
+
+func fixCronjobV1(namespace, name string) *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "batch/v1",
+		"kind":       "CronJob",
+		"metadata": map[string]interface{}{
+			"namespace": namespace,
+			"name":      name,
+		},
+		"spec": map[string]interface{}{
+			"schedule": "*/5 * * * *",
+			"suspend":  true,
+			"jobTemplate": map[string]interface{}{
+				"spec": map[string]interface{}{
+					"template": map[string]interface{}{
+						"metadata": map[string]interface{}{
+							"labels": map[string]interface{}{"app": "app-a"},
+						},
+						"spec": map[string]interface{}{
+							"serviceAccountName": "reporter",
+						},
+					},
+				},
+			},
+		},
+	}}
+}
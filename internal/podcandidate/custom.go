@@ -0,0 +1,190 @@
+package podcandidate
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"strings"
+
+	"github.com/ghodss/yaml"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/util/jsonpath"
+
+	"github.com/aszecowka/netpolvalidator/internal/model"
+)
+
+// CustomResource describes a custom resource whose operator creates pods, e.g. a Knative Service or a Tekton Task.
+type CustomResource struct {
+	// Name is the workload type of pod candidates created from the resource, e.g. knative-service.
+	Name     string `json:"name"`
+	Group    string `json:"group"`
+	Version  string `json:"version"`
+	Resource string `json:"resource"`
+	// LabelsPath is a JSONPath expression pointing to labels of pods created from the resource, e.g. {.spec.template.metadata.labels}.
+	LabelsPath string `json:"labelsPath"`
+	// StaticLabels are labels the operator adds to every pod it creates.
+	StaticLabels map[string]string `json:"staticLabels,omitempty"`
+}
+
+// GroupVersionResource returns the API resource of the custom resource.
+func (cr CustomResource) GroupVersionResource() schema.GroupVersionResource {
+	return schema.GroupVersionResource{Group: cr.Group, Version: cr.Version, Resource: cr.Resource}
+}
+
+type customResourcesFile struct {
+	CustomResources []CustomResource `json:"customResources"`
+}
+
+// LoadCustomResources reads definitions of custom resources from a YAML file with a top-level customResources list.
+func LoadCustomResources(path string) ([]CustomResource, error) {
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("while reading custom resources from %s: %w", path, err)
+	}
+	var file customResourcesFile
+	if err := yaml.Unmarshal(content, &file); err != nil {
+		return nil, fmt.Errorf("while parsing custom resources from %s: %w", path, err)
+	}
+	names := make(map[string]struct{})
+	for _, cr := range file.CustomResources {
+		if cr.Name == "" || cr.Version == "" || cr.Resource == "" || cr.LabelsPath == "" {
+			return nil, fmt.Errorf("custom resource %q in %s: name, version, resource and labelsPath are required", cr.Name, path)
+		}
+		if _, found := names[cr.Name]; found {
+			return nil, fmt.Errorf("custom resource %q is defined more than once in %s", cr.Name, path)
+		}
+		names[cr.Name] = struct{}{}
+		if _, err := parseLabelsPath(cr); err != nil {
+			return nil, err
+		}
+	}
+	return file.CustomResources, nil
+}
+
+// CustomResourceFetcher provides pod candidates from custom resources, reading pod labels with a JSONPath expression.
+// Resources without labels at the path and without static labels are skipped.
+type CustomResourceFetcher struct {
+	client     dynamic.Interface
+	definition CustomResource
+	resource   schema.GroupVersionResource
+}
+
+func NewCustomResourceFetcher(client dynamic.Interface, definition CustomResource) (*CustomResourceFetcher, error) {
+	if _, err := parseLabelsPath(definition); err != nil {
+		return nil, err
+	}
+	return &CustomResourceFetcher{
+		client:     client,
+		definition: definition,
+		resource:   definition.GroupVersionResource(),
+	}, nil
+}
+
+func (cf *CustomResourceFetcher) GetPodCandidatesForNamespace(ctx context.Context, ns string) ([]model.PodCandidate, error) {
+	allResources, err := cf.list(ctx, ns)
+	if err != nil {
+		return nil, fmt.Errorf("while gettting %s from namespace: %s: %w", cf.resource.String(), ns, err)
+	}
+	labelsPath, err := parseLabelsPath(cf.definition)
+	if err != nil {
+		return nil, err
+	}
+	var out []model.PodCandidate
+	for _, r := range allResources {
+		candidate, ok, err := cf.convert(labelsPath, r)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			out = append(out, candidate)
+		}
+	}
+	return out, nil
+}
+
+// GetPodCandidatesForAllNamespaces lists custom resources with a single cluster-scoped call and groups them by namespace.
+func (cf *CustomResourceFetcher) GetPodCandidatesForAllNamespaces(ctx context.Context) (map[string][]model.PodCandidate, error) {
+	allResources, err := cf.list(ctx, metav1.NamespaceAll)
+	if err != nil {
+		return nil, fmt.Errorf("while gettting %s from all namespaces: %w", cf.resource.String(), err)
+	}
+	labelsPath, err := parseLabelsPath(cf.definition)
+	if err != nil {
+		return nil, err
+	}
+	out := make(map[string][]model.PodCandidate)
+	for _, r := range allResources {
+		candidate, ok, err := cf.convert(labelsPath, r)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			out[r.GetNamespace()] = append(out[r.GetNamespace()], candidate)
+		}
+	}
+	return out, nil
+}
+
+func (cf *CustomResourceFetcher) list(ctx context.Context, ns string) ([]unstructured.Unstructured, error) {
+	var allResources []unstructured.Unstructured
+	continueOption := ""
+	for {
+		list, err := cf.client.Resource(cf.resource).Namespace(ns).List(ctx, metav1.ListOptions{Limit: listPageSize, Continue: continueOption})
+		if err != nil {
+			return nil, err
+		}
+		allResources = append(allResources, list.Items...)
+		continueOption = list.GetContinue()
+		if continueOption == "" {
+			break
+		}
+	}
+	return allResources, nil
+}
+
+func (cf *CustomResourceFetcher) convert(labelsPath *jsonpath.JSONPath, r unstructured.Unstructured) (model.PodCandidate, bool, error) {
+	results, err := labelsPath.FindResults(r.Object)
+	if err != nil {
+		return model.PodCandidate{}, false, fmt.Errorf("while reading labels of %s %s/%s: %w", cf.definition.Name, r.GetNamespace(), r.GetName(), err)
+	}
+	labels := make(map[string]string)
+	for _, result := range results {
+		for _, value := range result {
+			found, ok := value.Interface().(map[string]interface{})
+			if !ok {
+				return model.PodCandidate{}, false, fmt.Errorf("labels path %s of %s %s/%s does not point to a map", cf.definition.LabelsPath, cf.definition.Name, r.GetNamespace(), r.GetName())
+			}
+			for k, v := range found {
+				labels[k] = fmt.Sprint(v)
+			}
+		}
+	}
+	for k, v := range cf.definition.StaticLabels {
+		labels[k] = v
+	}
+	if len(labels) == 0 {
+		return model.PodCandidate{}, false, nil
+	}
+	return model.PodCandidate{
+		Labels: labels,
+		Owner:  newOwner(WorkloadType(cf.definition.Name), r.GetNamespace(), r.GetName()),
+	}, true, nil
+}
+
+// parseLabelsPath is called by every fetch, because a JSONPath keeps the state of {range} between calls
+// and cannot be shared by concurrent fetches.
+func parseLabelsPath(definition CustomResource) (*jsonpath.JSONPath, error) {
+	expression := definition.LabelsPath
+	// accept paths without braces, like kubectl does for custom columns
+	if !strings.HasPrefix(expression, "{") {
+		expression = fmt.Sprintf("{%s}", expression)
+	}
+	out := jsonpath.New(definition.Name).AllowMissingKeys(true)
+	if err := out.Parse(expression); err != nil {
+		return nil, fmt.Errorf("while parsing labels path of custom resource %q: %w", definition.Name, err)
+	}
+	return out, nil
+}
@@ -0,0 +1,160 @@
+package podcandidate_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/aszecowka/netpolvalidator/internal/model"
+	"github.com/aszecowka/netpolvalidator/internal/podcandidate"
+)
+
+func TestLoadCustomResources(t *testing.T) {
+	// WHEN
+	actual, err := podcandidate.LoadCustomResources("testdata/custom-resources.yaml")
+	// THEN
+	require.NoError(t, err)
+	assert.Equal(t, []podcandidate.CustomResource{fixKnativeServiceDefinition(), {
+		Name:       "spark-application",
+		Group:      "sparkoperator.k8s.io",
+		Version:    "v1beta2",
+		Resource:   "sparkapplications",
+		LabelsPath: ".spec.driver.labels",
+	}}, actual)
+}
+
+func TestPodCandidateFromCustomResources(t *testing.T) {
+	// GIVEN
+	serviceA := fixKnativeService("orders", "service-a", map[string]interface{}{"app": "app-a"})
+	serviceWithoutLabels := fixKnativeService("orders", "service-b", nil)
+	fakeClient := dynamicfake.NewSimpleDynamicClient(runtime.NewScheme(), serviceA, serviceWithoutLabels)
+	sut, err := podcandidate.NewCustomResourceFetcher(fakeClient, fixKnativeServiceDefinition())
+	require.NoError(t, err)
+	// WHEN
+	actual, err := sut.GetPodCandidatesForNamespace(context.Background(), "orders")
+	// THEN
+	require.NoError(t, err)
+	require.Len(t, actual, 2)
+	assert.Contains(t, actual, model.PodCandidate{Owner: model.Owner{Kind: "knative-service", Namespace: "orders", Name: "service-a"}, Labels: map[string]string{
+		"app":                         "app-a",
+		"serving.knative.dev/release": "stable",
+	}})
+	assert.Contains(t, actual, model.PodCandidate{Owner: model.Owner{Kind: "knative-service", Namespace: "orders", Name: "service-b"}, Labels: map[string]string{
+		"serving.knative.dev/release": "stable",
+	}})
+}
+
+func TestPodCandidateFromCustomResourcesInAllNamespaces(t *testing.T) {
+	// GIVEN
+	serviceA := fixKnativeService("orders", "service-a", map[string]interface{}{"app": "app-a"})
+	serviceB := fixKnativeService("payments", "service-b", nil)
+	fakeClient := dynamicfake.NewSimpleDynamicClient(runtime.NewScheme(), serviceA, serviceB)
+	definition := fixKnativeServiceDefinition()
+	definition.StaticLabels = nil
+	sut, err := podcandidate.NewCustomResourceFetcher(fakeClient, definition)
+	require.NoError(t, err)
+	// WHEN
+	actual, err := sut.GetPodCandidatesForAllNamespaces(context.Background())
+	// THEN
+	require.NoError(t, err)
+	assert.Equal(t, map[string][]model.PodCandidate{
+		"orders": {{Owner: model.Owner{Kind: "knative-service", Namespace: "orders", Name: "service-a"}, Labels: map[string]string{"app": "app-a"}}},
+	}, actual)
+}
+
+func TestPodCandidateFromCustomResourcesConcurrently(t *testing.T) {
+	// GIVEN
+	serviceA := fixKnativeService("orders", "service-a", map[string]interface{}{"app": "app-a"})
+	serviceB := fixKnativeService("payments", "service-b", map[string]interface{}{"app": "app-b"})
+	fakeClient := dynamicfake.NewSimpleDynamicClient(runtime.NewScheme(), serviceA, serviceB)
+	definition := fixKnativeServiceDefinition()
+	definition.LabelsPath = "{range .spec.*}{.metadata.labels}{end}"
+	definition.StaticLabels = nil
+	sut, err := podcandidate.NewCustomResourceFetcher(fakeClient, definition)
+	require.NoError(t, err)
+	expected := map[string][]model.PodCandidate{
+		"orders":   {{Owner: model.Owner{Kind: "knative-service", Namespace: "orders", Name: "service-a"}, Labels: map[string]string{"app": "app-a"}}},
+		"payments": {{Owner: model.Owner{Kind: "knative-service", Namespace: "payments", Name: "service-b"}, Labels: map[string]string{"app": "app-b"}}},
+	}
+	namespaces := []string{"orders", "payments", "orders", "payments"}
+	actual := make([][]model.PodCandidate, len(namespaces))
+	errs := make([]error, len(namespaces))
+	// WHEN
+	var wg sync.WaitGroup
+	for i, ns := range namespaces {
+		wg.Add(1)
+		go func(i int, ns string) {
+			defer wg.Done()
+			actual[i], errs[i] = sut.GetPodCandidatesForNamespace(context.Background(), ns)
+		}(i, ns)
+	}
+	wg.Wait()
+	// THEN
+	for i, ns := range namespaces {
+		require.NoError(t, errs[i])
+		assert.Equal(t, expected[ns], actual[i])
+	}
+}
+
+func TestPodCandidateFromCachedCustomResources(t *testing.T) {
+	// GIVEN
+	definition := fixKnativeServiceDefinition()
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	require.NoError(t, indexer.Add(fixKnativeService("orders", "service-a", map[string]interface{}{"app": "app-a"})))
+	require.NoError(t, indexer.Add(fixKnativeService("payments", "service-b", map[string]interface{}{"app": "app-b"})))
+	sut, err := podcandidate.NewCachedCustomResourceFetcher(cache.NewGenericLister(indexer, definition.GroupVersionResource().GroupResource()), definition)
+	require.NoError(t, err)
+	// WHEN
+	actual, err := sut.GetPodCandidatesForNamespace(context.Background(), "orders")
+	// THEN
+	require.NoError(t, err)
+	assert.Equal(t, []model.PodCandidate{{Owner: model.Owner{Kind: "knative-service", Namespace: "orders", Name: "service-a"}, Labels: map[string]string{
+		"app":                         "app-a",
+		"serving.knative.dev/release": "stable",
+	}}}, actual)
+}
+
+func TestNewCustomResourceFetcherReturnsErrorOnInvalidLabelsPath(t *testing.T) {
+	// GIVEN
+	definition := fixKnativeServiceDefinition()
+	definition.LabelsPath = "{.spec.template[}"
+	// WHEN
+	_, err := podcandidate.NewCustomResourceFetcher(dynamicfake.NewSimpleDynamicClient(runtime.NewScheme()), definition)
+	// THEN
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "knative-service")
+}
+
+func fixKnativeServiceDefinition() podcandidate.CustomResource {
+	return podcandidate.CustomResource{
+		Name:         "knative-service",
+		Group:        "serving.knative.dev",
+		Version:      "v1",
+		Resource:     "services",
+		LabelsPath:   "{.spec.template.metadata.labels}",
+		StaticLabels: map[string]string{"serving.knative.dev/release": "stable"},
+	}
+}
+
+func fixKnativeService(namespace, name string, templateLabels map[string]interface{}) *unstructured.Unstructured {
+	template := map[string]interface{}{}
+	if templateLabels != nil {
+		template["metadata"] = map[string]interface{}{"labels": templateLabels}
+	}
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "serving.knative.dev/v1",
+		"kind":       "Service",
+		"metadata": map[string]interface{}{
+			"namespace": namespace,
+			"name":      name,
+		},
+		"spec": map[string]interface{}{"template": template},
+	}}
+}
@@ -20,30 +20,56 @@ func NewDaemonsetFetcher(client v1.DaemonSetsGetter) *DaemonsetFetcher {
 }
 
 func (df *DaemonsetFetcher) GetPodCandidatesForNamespace(ctx context.Context, ns string) ([]model.PodCandidate, error) {
+	allDs, err := df.list(ctx, ns)
+	if err != nil {
+		return nil, fmt.Errorf("while gettting daemonsets from namespace: %s: %w", ns, err)
+	}
+	var out []model.PodCandidate
+	for _, d := range allDs {
+		out = append(out, df.convert(d))
+	}
+	return out, nil
+}
+
+// GetPodCandidatesForAllNamespaces lists daemonsets with a single cluster-scoped call and groups them by namespace.
+func (df *DaemonsetFetcher) GetPodCandidatesForAllNamespaces(ctx context.Context) (map[string][]model.PodCandidate, error) {
+	allDs, err := df.list(ctx, metav1.NamespaceAll)
+	if err != nil {
+		return nil, fmt.Errorf("while gettting daemonsets from all namespaces: %w", err)
+	}
+	out := make(map[string][]model.PodCandidate)
+	for _, d := range allDs {
+		out[d.Namespace] = append(out[d.Namespace], df.convert(d))
+	}
+	return out, nil
+}
+
+func (df *DaemonsetFetcher) list(ctx context.Context, ns string) ([]appsv1.DaemonSet, error) {
 	var allDs []appsv1.DaemonSet
 	continueOption := ""
 	for {
-		dsList, err := df.client.DaemonSets(ns).List(ctx, metav1.ListOptions{Continue: continueOption})
+		dsList, err := df.client.DaemonSets(ns).List(ctx, metav1.ListOptions{Limit: listPageSize, Continue: continueOption})
 		if err != nil {
-			return nil, fmt.Errorf("while gettting daemonsets from namespace: %s: %w", ns, err)
+			return nil, err
 		}
 		allDs = append(allDs, dsList.Items...)
 		continueOption = dsList.Continue
 		if continueOption == "" {
 			break
 		}
-
-	}
-	var out []model.PodCandidate
-	for _, d := range allDs {
-		out = append(out, df.convert(d))
 	}
-	return out, nil
+	return allDs, nil
 }
 
 func (df *DaemonsetFetcher) convert(daemonset appsv1.DaemonSet) model.PodCandidate {
+	desired := daemonset.Status.DesiredNumberScheduled
 	return model.PodCandidate{
-		Labels:    daemonset.Spec.Template.Labels,
-		OwnerName: getOwnerName(WorkloadDaemonset, daemonset.Namespace, daemonset.Name),
+		Labels: daemonset.Spec.Template.Labels,
+		Owner:  newOwner(WorkloadDaemonset, daemonset.Namespace, daemonset.Name),
+
+		DesiredReplicas: &desired,
+		ReadyReplicas:   daemonset.Status.NumberReady,
+		ServiceAccount:  daemonset.Spec.Template.Spec.ServiceAccountName,
+		NodeSelector:    daemonset.Spec.Template.Spec.NodeSelector,
 	}
 }
@@ -25,13 +25,15 @@ func TestPodCandidatesFromDaemonsets(t *testing.T) {
 	require.NoError(t, err)
 	require.Len(t, actual, 2)
 	assert.Contains(t, actual, model.PodCandidate{
-		OwnerName: "daemonset/orders/ds-a",
+		Owner:           model.Owner{Kind: "daemonset", Namespace: "orders", Name: "ds-a"},
+		DesiredReplicas: int32Ptr(0),
 		Labels: map[string]string{
 			"app": "app-a",
 		},
 	})
 	assert.Contains(t, actual, model.PodCandidate{
-		OwnerName: "daemonset/orders/ds-b",
+		Owner:           model.Owner{Kind: "daemonset", Namespace: "orders", Name: "ds-b"},
+		DesiredReplicas: int32Ptr(0),
 		Labels: map[string]string{
 			"app": "app-b",
 		},
@@ -20,30 +20,56 @@ func NewDeploymentsFetcher(client v1.DeploymentsGetter) *DeploymentsFetcher {
 }
 
 func (df *DeploymentsFetcher) GetPodCandidatesForNamespace(ctx context.Context, ns string) ([]model.PodCandidate, error) {
+	allDeployments, err := df.list(ctx, ns)
+	if err != nil {
+		return nil, fmt.Errorf("while gettting deployments from namespace: %s: %w", ns, err)
+	}
+	var out []model.PodCandidate
+	for _, d := range allDeployments {
+		out = append(out, df.convert(d))
+	}
+	return out, nil
+}
+
+// GetPodCandidatesForAllNamespaces lists deployments with a single cluster-scoped call and groups them by namespace.
+func (df *DeploymentsFetcher) GetPodCandidatesForAllNamespaces(ctx context.Context) (map[string][]model.PodCandidate, error) {
+	allDeployments, err := df.list(ctx, metav1.NamespaceAll)
+	if err != nil {
+		return nil, fmt.Errorf("while gettting deployments from all namespaces: %w", err)
+	}
+	out := make(map[string][]model.PodCandidate)
+	for _, d := range allDeployments {
+		out[d.Namespace] = append(out[d.Namespace], df.convert(d))
+	}
+	return out, nil
+}
+
+func (df *DeploymentsFetcher) list(ctx context.Context, ns string) ([]appsv1.Deployment, error) {
 	var allDeployments []appsv1.Deployment
 	continueOption := ""
 	for {
-		deployments, err := df.client.Deployments(ns).List(ctx, metav1.ListOptions{Continue: continueOption})
+		deployments, err := df.client.Deployments(ns).List(ctx, metav1.ListOptions{Limit: listPageSize, Continue: continueOption})
 		if err != nil {
-			return nil, fmt.Errorf("while gettting deployments from namespace: %s: %w", ns, err)
+			return nil, err
 		}
 		allDeployments = append(allDeployments, deployments.Items...)
 		continueOption = deployments.Continue
 		if continueOption == "" {
 			break
 		}
-
-	}
-	var out []model.PodCandidate
-	for _, d := range allDeployments {
-		out = append(out, df.convert(d))
 	}
-	return out, nil
+	return allDeployments, nil
 }
 
 func (df *DeploymentsFetcher) convert(deploy appsv1.Deployment) model.PodCandidate {
 	return model.PodCandidate{
-		Labels:    deploy.Spec.Template.Labels,
-		OwnerName: getOwnerName(WorkloadDeployment, deploy.Namespace, deploy.Name),
+		Labels: deploy.Spec.Template.Labels,
+		Owner:  newOwner(WorkloadDeployment, deploy.Namespace, deploy.Name),
+
+		InjectedLabels:  deploymentInjectedLabels(),
+		DesiredReplicas: replicasOrDefault(deploy.Spec.Replicas),
+		ReadyReplicas:   deploy.Status.ReadyReplicas,
+		ServiceAccount:  deploy.Spec.Template.Spec.ServiceAccountName,
+		NodeSelector:    deploy.Spec.Template.Spec.NodeSelector,
 	}
 }
@@ -26,12 +26,54 @@ func TestPodCandidateFromDeployments(t *testing.T) {
 	// THEN
 	require.NoError(t, err)
 	require.Len(t, actual, 2)
-	assert.Contains(t, actual, model.PodCandidate{OwnerName: "deployment/orders/deploy-a", Labels: map[string]string{
+	assert.Contains(t, actual, model.PodCandidate{Owner: model.Owner{Kind: "deployment", Namespace: "orders", Name: "deploy-a"}, DesiredReplicas: int32Ptr(1), Labels: map[string]string{
 		"app": "app-a",
-	}})
-	assert.Contains(t, actual, model.PodCandidate{OwnerName: "deployment/orders/deploy-b", Labels: map[string]string{
+	}, InjectedLabels: map[string][]string{"pod-template-hash": nil}})
+	assert.Contains(t, actual, model.PodCandidate{Owner: model.Owner{Kind: "deployment", Namespace: "orders", Name: "deploy-b"}, DesiredReplicas: int32Ptr(1), Labels: map[string]string{
 		"app": "app-b",
-	}})
+	}, InjectedLabels: map[string][]string{"pod-template-hash": nil}})
+}
+
+func TestPodCandidateFromDeploymentsInAllNamespaces(t *testing.T) {
+	// GIVEN
+	deployA := fixDeployA()
+	deployB := fixDeployB()
+	deployB.Namespace = "payments"
+	fakeClientset := fake.NewSimpleClientset(&deployA, &deployB)
+	sut := podcandidate.NewDeploymentsFetcher(fakeClientset.AppsV1())
+	// WHEN
+	actual, err := sut.GetPodCandidatesForAllNamespaces(context.Background())
+	// THEN
+	require.NoError(t, err)
+	require.Len(t, actual, 2)
+	assert.Equal(t, []model.PodCandidate{{Owner: model.Owner{Kind: "deployment", Namespace: "orders", Name: "deploy-a"}, DesiredReplicas: int32Ptr(1), Labels: map[string]string{
+		"app": "app-a",
+	}, InjectedLabels: map[string][]string{"pod-template-hash": nil}}}, actual["orders"])
+	assert.Equal(t, []model.PodCandidate{{Owner: model.Owner{Kind: "deployment", Namespace: "payments", Name: "deploy-b"}, DesiredReplicas: int32Ptr(1), Labels: map[string]string{
+		"app": "app-b",
+	}, InjectedLabels: map[string][]string{"pod-template-hash": nil}}}, actual["payments"])
+}
+
+func TestPodCandidateFromDeploymentScaledToZero(t *testing.T) {
+	// GIVEN
+	deploy := fixDeployA()
+	deploy.Spec.Replicas = int32Ptr(0)
+	deploy.Spec.Template.Spec.ServiceAccountName = "orders"
+	deploy.Spec.Template.Spec.NodeSelector = map[string]string{"pool": "backend"}
+	deploy.Status.ReadyReplicas = 1
+	fakeClientset := fake.NewSimpleClientset(&deploy)
+	sut := podcandidate.NewDeploymentsFetcher(fakeClientset.AppsV1())
+	// WHEN
+	actual, err := sut.GetPodCandidatesForNamespace(context.Background(), "orders")
+	// THEN
+	require.NoError(t, err)
+	require.Len(t, actual, 1)
+	assert.Equal(t, int32Ptr(0), actual[0].DesiredReplicas)
+	assert.Equal(t, int32(1), actual[0].ReadyReplicas)
+	assert.Equal(t, "orders", actual[0].ServiceAccount)
+	assert.Equal(t, map[string]string{"pool": "backend"}, actual[0].NodeSelector)
+	assert.True(t, actual[0].ScaledToZero())
+	assert.False(t, actual[0].Running())
 }
 
 func fixDeployA() appsv1.Deployment {
@@ -69,3 +111,7 @@ func fixDeployB() appsv1.Deployment {
 		},
 	}
 }
+
+func int32Ptr(v int32) *int32 {
+	return &v
+}
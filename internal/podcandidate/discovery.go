@@ -0,0 +1,101 @@
+package podcandidate
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	"k8s.io/api/batch/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/aszecowka/netpolvalidator/internal/model"
+)
+
+var (
+	// CronjobsV1Resource are cronjobs from the batch/v1 API, served since Kubernetes 1.21 instead of batch/v1beta1 ones.
+	CronjobsV1Resource             = batchv1.SchemeGroupVersion.WithResource("cronjobs")
+	CronjobsV1beta1Resource        = v1beta1.SchemeGroupVersion.WithResource("cronjobs")
+	DaemonsetsResource             = appsv1.SchemeGroupVersion.WithResource("daemonsets")
+	DeploymentsResource            = appsv1.SchemeGroupVersion.WithResource("deployments")
+	JobsResource                   = batchv1.SchemeGroupVersion.WithResource("jobs")
+	PodsResource                   = corev1.SchemeGroupVersion.WithResource("pods")
+	ReplicasetsResource            = appsv1.SchemeGroupVersion.WithResource("replicasets")
+	ReplicationControllersResource = corev1.SchemeGroupVersion.WithResource("replicationcontrollers")
+	// RolloutsResource are Argo Rollouts, served only when their CRD is installed.
+	RolloutsResource     = schema.GroupVersionResource{Group: "argoproj.io", Version: "v1alpha1", Resource: "rollouts"}
+	StatefulsetsResource = appsv1.SchemeGroupVersion.WithResource("statefulsets")
+)
+
+// Fetcher provides pod candidates of a single workload type.
+type Fetcher interface {
+	GetPodCandidatesForNamespace(ctx context.Context, ns string) ([]model.PodCandidate, error)
+}
+
+// ServedResources holds resources served by the API server.
+type ServedResources map[schema.GroupVersionResource]struct{}
+
+// DiscoverServedResources asks the API server which resources it serves. Groups whose discovery failed,
+// e.g. because their aggregated API server is unavailable, are treated as not served.
+func DiscoverServedResources(client discovery.DiscoveryInterface) (ServedResources, error) {
+	_, lists, err := client.ServerGroupsAndResources()
+	if err != nil && !discovery.IsGroupDiscoveryFailedError(err) {
+		return nil, fmt.Errorf("while discovering resources served by the API server: %w", err)
+	}
+	out := make(ServedResources)
+	for _, list := range lists {
+		gv, err := schema.ParseGroupVersion(list.GroupVersion)
+		if err != nil {
+			continue
+		}
+		for _, r := range list.APIResources {
+			// subresources, like deployments/scale, are not workloads
+			if strings.Contains(r.Name, "/") {
+				continue
+			}
+			out[gv.WithResource(r.Name)] = struct{}{}
+		}
+	}
+	return out, nil
+}
+
+func (s ServedResources) Serves(resource schema.GroupVersionResource) bool {
+	_, found := s[resource]
+	return found
+}
+
+// NewFetchers creates fetchers of built-in workload types, using the newest version of every type served by the API server.
+// Workload types the API server does not serve are skipped.
+func NewFetchers(clientset kubernetes.Interface, dynamicClient dynamic.Interface, served ServedResources) map[WorkloadType]Fetcher {
+	// versions of the same workload type are ordered from the newest one
+	candidates := []struct {
+		workloadType WorkloadType
+		resource     schema.GroupVersionResource
+		newFetcher   func() Fetcher
+	}{
+		{WorkloadCronjob, CronjobsV1Resource, func() Fetcher { return NewCronjobV1Fetcher(dynamicClient) }},
+		{WorkloadCronjob, CronjobsV1beta1Resource, func() Fetcher { return NewCronjobFetcher(clientset.BatchV1beta1()) }},
+		{WorkloadDaemonset, DaemonsetsResource, func() Fetcher { return NewDaemonsetFetcher(clientset.AppsV1()) }},
+		{WorkloadDeployment, DeploymentsResource, func() Fetcher { return NewDeploymentsFetcher(clientset.AppsV1()) }},
+		{WorkloadJob, JobsResource, func() Fetcher { return NewJobFetcher(clientset.BatchV1()) }},
+		{WorkloadPod, PodsResource, func() Fetcher { return NewPodsFetcher(clientset.CoreV1()) }},
+		{WorkloadReplicaset, ReplicasetsResource, func() Fetcher { return NewReplicasetFetcher(clientset.AppsV1()) }},
+		{WorkloadReplicationController, ReplicationControllersResource, func() Fetcher { return NewReplicationControllerFetcher(clientset.CoreV1()) }},
+		{WorkloadRollout, RolloutsResource, func() Fetcher { return NewRolloutFetcher(dynamicClient) }},
+		{WorkloadStatefulset, StatefulsetsResource, func() Fetcher { return NewStatefulsetsFetcher(clientset.AppsV1()) }},
+	}
+
+	out := make(map[WorkloadType]Fetcher)
+	for _, c := range candidates {
+		if _, found := out[c.workloadType]; found || !served.Serves(c.resource) {
+			continue
+		}
+		out[c.workloadType] = c.newFetcher()
+	}
+	return out
+}
@@ -0,0 +1,72 @@
+package podcandidate_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/aszecowka/netpolvalidator/internal/podcandidate"
+)
+
+func TestNewFetchers(t *testing.T) {
+	testCases := map[string]struct {
+		givenResources []*metav1.APIResourceList
+		expected       map[podcandidate.WorkloadType]interface{}
+	}{
+		"cluster serving batch/v1 cronjobs": {
+			givenResources: []*metav1.APIResourceList{
+				{GroupVersion: "apps/v1", APIResources: []metav1.APIResource{{Name: "deployments"}, {Name: "deployments/scale"}}},
+				{GroupVersion: "batch/v1", APIResources: []metav1.APIResource{{Name: "cronjobs"}, {Name: "jobs"}}},
+				{GroupVersion: "batch/v1beta1", APIResources: []metav1.APIResource{{Name: "cronjobs"}}},
+			},
+			expected: map[podcandidate.WorkloadType]interface{}{
+				podcandidate.WorkloadCronjob:    &podcandidate.CronjobV1Fetcher{},
+				podcandidate.WorkloadDeployment: &podcandidate.DeploymentsFetcher{},
+				podcandidate.WorkloadJob:        &podcandidate.JobFetcher{},
+			},
+		},
+		"cluster serving batch/v1beta1 cronjobs only": {
+			givenResources: []*metav1.APIResourceList{
+				{GroupVersion: "batch/v1", APIResources: []metav1.APIResource{{Name: "jobs"}}},
+				{GroupVersion: "batch/v1beta1", APIResources: []metav1.APIResource{{Name: "cronjobs"}}},
+			},
+			expected: map[podcandidate.WorkloadType]interface{}{
+				podcandidate.WorkloadCronjob: &podcandidate.CronjobFetcher{},
+				podcandidate.WorkloadJob:     &podcandidate.JobFetcher{},
+			},
+		},
+		"cluster with Argo Rollouts": {
+			givenResources: []*metav1.APIResourceList{
+				{GroupVersion: "v1", APIResources: []metav1.APIResource{{Name: "pods"}, {Name: "replicationcontrollers"}}},
+				{GroupVersion: "argoproj.io/v1alpha1", APIResources: []metav1.APIResource{{Name: "rollouts"}}},
+			},
+			expected: map[podcandidate.WorkloadType]interface{}{
+				podcandidate.WorkloadPod:                   &podcandidate.PodsFetcher{},
+				podcandidate.WorkloadReplicationController: &podcandidate.ReplicationControllerFetcher{},
+				podcandidate.WorkloadRollout:               &podcandidate.RolloutFetcher{},
+			},
+		},
+	}
+
+	for tn, tc := range testCases {
+		t.Run(tn, func(t *testing.T) {
+			// GIVEN
+			fakeClientset := fake.NewSimpleClientset()
+			fakeClientset.Resources = tc.givenResources
+			served, err := podcandidate.DiscoverServedResources(fakeClientset.Discovery())
+			require.NoError(t, err)
+			// WHEN
+			actual := podcandidate.NewFetchers(fakeClientset, dynamicfake.NewSimpleDynamicClient(runtime.NewScheme()), served)
+			// THEN
+			require.Len(t, actual, len(tc.expected))
+			for workloadType, expectedFetcher := range tc.expected {
+				assert.IsType(t, expectedFetcher, actual[workloadType], "fetcher of %s", workloadType)
+			}
+		})
+	}
+}
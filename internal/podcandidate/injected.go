@@ -0,0 +1,77 @@
+package podcandidate
+
+import (
+	"strconv"
+
+	appsv1 "k8s.io/api/apps/v1"
+)
+
+const (
+	labelJobName             = "job-name"
+	labelControllerUID       = "controller-uid"
+	labelBatchJobName        = "batch.kubernetes.io/job-name"
+	labelBatchControllerUID  = "batch.kubernetes.io/controller-uid"
+	labelStatefulSetPodIndex = "apps.kubernetes.io/pod-index"
+)
+
+// deploymentInjectedLabels returns labels the deployment controller adds to pods through replicasets.
+// The hash is computed from the pod template by the controller, so any value is accepted.
+func deploymentInjectedLabels() map[string][]string {
+	return map[string][]string{
+		appsv1.DefaultDeploymentUniqueLabelKey: nil,
+	}
+}
+
+// rolloutInjectedLabels returns labels the Argo Rollouts controller adds to pods through replicasets.
+// The hash is computed from the pod template by the controller, so any value is accepted.
+func rolloutInjectedLabels() map[string][]string {
+	return map[string][]string{
+		labelRolloutPodTemplateHash: nil,
+	}
+}
+
+// jobInjectedLabels returns labels the job controller adds to pods of the job with the given name and UID.
+// The UID is not known before the job is created, then any value is accepted.
+func jobInjectedLabels(name, uid string) map[string][]string {
+	var uids []string
+	if uid != "" {
+		uids = []string{uid}
+	}
+	return map[string][]string{
+		labelJobName:            {name},
+		labelBatchJobName:       {name},
+		labelControllerUID:      uids,
+		labelBatchControllerUID: uids,
+	}
+}
+
+// cronjobInjectedLabels returns labels the job controller adds to pods of jobs created by a cronjob.
+// Names and UIDs of jobs are known only once they are scheduled, so any value is accepted.
+func cronjobInjectedLabels() map[string][]string {
+	return map[string][]string{
+		labelJobName:            nil,
+		labelBatchJobName:       nil,
+		labelControllerUID:      nil,
+		labelBatchControllerUID: nil,
+	}
+}
+
+// statefulsetInjectedLabels returns labels the statefulset controller adds to every replica of the statefulset.
+func statefulsetInjectedLabels(ss appsv1.StatefulSet) map[string][]string {
+	replicas := int32(1)
+	if ss.Spec.Replicas != nil {
+		replicas = *ss.Spec.Replicas
+	}
+	podNames := make([]string, 0, replicas)
+	podIndexes := make([]string, 0, replicas)
+	for i := int32(0); i < replicas; i++ {
+		index := strconv.Itoa(int(i))
+		podNames = append(podNames, ss.Name+"-"+index)
+		podIndexes = append(podIndexes, index)
+	}
+	return map[string][]string{
+		appsv1.StatefulSetPodNameLabel:        podNames,
+		labelStatefulSetPodIndex:              podIndexes,
+		appsv1.ControllerRevisionHashLabelKey: nil,
+	}
+}
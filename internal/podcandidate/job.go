@@ -5,6 +5,7 @@ import (
 	"fmt"
 
 	v13 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	v12 "k8s.io/client-go/kubernetes/typed/batch/v1"
 
@@ -20,12 +21,37 @@ func NewJobFetcher(client v12.JobsGetter) *JobFetcher {
 }
 
 func (jf *JobFetcher) GetPodCandidatesForNamespace(ctx context.Context, ns string) ([]model.PodCandidate, error) {
+	allJobs, err := jf.list(ctx, ns)
+	if err != nil {
+		return nil, fmt.Errorf("while getting jobs for namespace %s: %w", ns, err)
+	}
+	var out []model.PodCandidate
+	for _, j := range allJobs {
+		out = append(out, jf.convert(j))
+	}
+	return out, nil
+}
+
+// GetPodCandidatesForAllNamespaces lists jobs with a single cluster-scoped call and groups them by namespace.
+func (jf *JobFetcher) GetPodCandidatesForAllNamespaces(ctx context.Context) (map[string][]model.PodCandidate, error) {
+	allJobs, err := jf.list(ctx, v1.NamespaceAll)
+	if err != nil {
+		return nil, fmt.Errorf("while getting jobs for all namespaces: %w", err)
+	}
+	out := make(map[string][]model.PodCandidate)
+	for _, j := range allJobs {
+		out[j.Namespace] = append(out[j.Namespace], jf.convert(j))
+	}
+	return out, nil
+}
+
+func (jf *JobFetcher) list(ctx context.Context, ns string) ([]v13.Job, error) {
 	var allJobs []v13.Job
 	continueOption := ""
 	for {
-		list, err := jf.client.Jobs(ns).List(ctx, v1.ListOptions{Continue: continueOption})
+		list, err := jf.client.Jobs(ns).List(ctx, v1.ListOptions{Limit: listPageSize, Continue: continueOption})
 		if err != nil {
-			return nil, fmt.Errorf("while getting jobs for namespace %s: %w", ns, err)
+			return nil, err
 		}
 		allJobs = append(allJobs, list.Items...)
 		continueOption = list.Continue
@@ -33,19 +59,30 @@ func (jf *JobFetcher) GetPodCandidatesForNamespace(ctx context.Context, ns strin
 			break
 		}
 	}
-
-	var out []model.PodCandidate
-	for _, j := range allJobs {
-		out = append(out, jf.convert(j))
-	}
-
-	return out, nil
+	return allJobs, nil
 }
 
 func (jf *JobFetcher) convert(job v13.Job) model.PodCandidate {
 	// TODO take into account owner ref
 	return model.PodCandidate{
-		Labels:    job.Spec.Template.Labels,
-		OwnerName: getOwnerName(WorkloadJob, job.Namespace, job.Name),
+		Labels: job.Spec.Template.Labels,
+		Owner:  newOwner(WorkloadJob, job.Namespace, job.Name),
+
+		InjectedLabels:  jobInjectedLabels(job.Name, string(job.UID)),
+		DesiredReplicas: replicasOrDefault(job.Spec.Parallelism),
+		ReadyReplicas:   job.Status.Active,
+		ServiceAccount:  job.Spec.Template.Spec.ServiceAccountName,
+		NodeSelector:    job.Spec.Template.Spec.NodeSelector,
+		Completed:       jobFinished(job),
+	}
+}
+
+// jobFinished reports whether the job completed or failed, so it does not start new pods anymore.
+func jobFinished(job v13.Job) bool {
+	for _, c := range job.Status.Conditions {
+		if (c.Type == v13.JobComplete || c.Type == v13.JobFailed) && c.Status == corev1.ConditionTrue {
+			return true
+		}
 	}
+	return false
 }
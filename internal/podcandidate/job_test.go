@@ -25,19 +25,72 @@ func TestPodCandidatesFromJobs(t *testing.T) {
 	require.NoError(t, err)
 	require.Len(t, actual, 2)
 	assert.Contains(t, actual, model.PodCandidate{
-		OwnerName: "job/orders/job-a",
+		Owner:           model.Owner{Kind: "job", Namespace: "orders", Name: "job-a"},
+		DesiredReplicas: int32Ptr(1),
 		Labels: map[string]string{
 			"app": "app-a",
 		},
+		InjectedLabels: map[string][]string{
+			"job-name":                           {"job-a"},
+			"controller-uid":                     nil,
+			"batch.kubernetes.io/job-name":       {"job-a"},
+			"batch.kubernetes.io/controller-uid": nil,
+		},
 	})
 	assert.Contains(t, actual, model.PodCandidate{
-		OwnerName: "job/orders/job-b",
+		Owner:           model.Owner{Kind: "job", Namespace: "orders", Name: "job-b"},
+		DesiredReplicas: int32Ptr(1),
 		Labels: map[string]string{
 			"app": "app-b",
 		},
+		InjectedLabels: map[string][]string{
+			"job-name":                           {"job-b"},
+			"controller-uid":                     nil,
+			"batch.kubernetes.io/job-name":       {"job-b"},
+			"batch.kubernetes.io/controller-uid": nil,
+		},
 	})
 }
 
+func TestPodCandidateFromFinishedJob(t *testing.T) {
+	testCases := map[string]struct {
+		givenConditions   []v12.JobCondition
+		expectedCompleted bool
+	}{
+		"running job": {
+			givenConditions: nil,
+		},
+		"succeeded job": {
+			givenConditions:   []v12.JobCondition{{Type: v12.JobComplete, Status: v13.ConditionTrue}},
+			expectedCompleted: true,
+		},
+		"failed job": {
+			givenConditions:   []v12.JobCondition{{Type: v12.JobFailed, Status: v13.ConditionTrue}},
+			expectedCompleted: true,
+		},
+		"job with stale condition": {
+			givenConditions: []v12.JobCondition{{Type: v12.JobComplete, Status: v13.ConditionFalse}},
+		},
+	}
+
+	for tn, tc := range testCases {
+		t.Run(tn, func(t *testing.T) {
+			// GIVEN
+			job := fixJobA()
+			job.Status.Conditions = tc.givenConditions
+			fakeClientset := fake.NewSimpleClientset(job)
+			sut := podcandidate.NewJobFetcher(fakeClientset.BatchV1())
+			// WHEN
+			actual, err := sut.GetPodCandidatesForNamespace(context.Background(), "orders")
+			// THEN
+			require.NoError(t, err)
+			require.Len(t, actual, 1)
+			assert.Equal(t, tc.expectedCompleted, actual[0].Completed)
+			assert.Equal(t, !tc.expectedCompleted, actual[0].Running())
+		})
+	}
+}
+
 func fixJobA() *v12.Job {
 	return &v12.Job{
 		ObjectMeta: v1.ObjectMeta{
@@ -1,6 +1,15 @@
 package podcandidate
 
-import "fmt"
+import (
+	"strconv"
+	"strings"
+
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/aszecowka/netpolvalidator/internal/model"
+)
 
 const (
 	WorkloadDeployment  WorkloadType = "deployment"
@@ -9,10 +18,62 @@ const (
 	WorkloadStatefulset WorkloadType = "statefulset"
 	WorkloadDaemonset   WorkloadType = "daemonset"
 	WorkloadPod         WorkloadType = "pod"
+
+	WorkloadReplicaset            WorkloadType = "replicaset"
+	WorkloadReplicationController WorkloadType = "replicationcontroller"
+	WorkloadRollout               WorkloadType = "rollout"
+
+	listPageSize int64 = 500
+
+	labelRolloutPodTemplateHash = "rollouts-pod-template-hash"
 )
 
 type WorkloadType string
 
+func newOwner(t WorkloadType, ns, name string) model.Owner {
+	return model.Owner{Kind: string(t), Namespace: ns, Name: name}
+}
+
 func getOwnerName(t WorkloadType, ns, name string) string {
-	return fmt.Sprintf("%s/%s/%s", t, ns, name)
+	return newOwner(t, ns, name).String()
+}
+
+// replicasOrDefault returns the number of replicas from the workload spec, which defaults to 1.
+func replicasOrDefault(replicas *int32) *int32 {
+	out := int32(1)
+	if replicas != nil {
+		out = *replicas
+	}
+	return &out
+}
+
+// getControllerNames returns owner names of workloads that may control the pod, starting from its controller.
+// Owners of the controller are not fetched, they are derived from names the controllers give to the resources they create:
+// replicasets of deployments and rollouts are suffixed with the pod template hash, jobs of cronjobs with the scheduled time.
+func getControllerNames(pod v1.Pod) []string {
+	controller := metav1.GetControllerOf(&pod)
+	if controller == nil {
+		return nil
+	}
+	switch controller.Kind {
+	case "ReplicaSet":
+		out := []string{getOwnerName(WorkloadReplicaset, pod.Namespace, controller.Name)}
+		if hash, found := pod.Labels[appsv1.DefaultDeploymentUniqueLabelKey]; found && strings.HasSuffix(controller.Name, "-"+hash) {
+			out = append(out, getOwnerName(WorkloadDeployment, pod.Namespace, strings.TrimSuffix(controller.Name, "-"+hash)))
+		}
+		if hash, found := pod.Labels[labelRolloutPodTemplateHash]; found && strings.HasSuffix(controller.Name, "-"+hash) {
+			out = append(out, getOwnerName(WorkloadRollout, pod.Namespace, strings.TrimSuffix(controller.Name, "-"+hash)))
+		}
+		return out
+	case "Job":
+		out := []string{getOwnerName(WorkloadJob, pod.Namespace, controller.Name)}
+		if idx := strings.LastIndex(controller.Name, "-"); idx > 0 {
+			if _, err := strconv.ParseInt(controller.Name[idx+1:], 10, 64); err == nil {
+				out = append(out, getOwnerName(WorkloadCronjob, pod.Namespace, controller.Name[:idx]))
+			}
+		}
+		return out
+	default:
+		return []string{getOwnerName(WorkloadType(strings.ToLower(controller.Kind)), pod.Namespace, controller.Name)}
+	}
 }
@@ -20,30 +20,64 @@ func NewPodsFetcher(client v1.PodsGetter) *PodsFetcher {
 }
 
 func (pf *PodsFetcher) GetPodCandidatesForNamespace(ctx context.Context, ns string) ([]model.PodCandidate, error) {
+	allPods, err := pf.list(ctx, ns)
+	if err != nil {
+		return nil, fmt.Errorf("while gettting pods from namespace: %s: %w", ns, err)
+	}
+	var out []model.PodCandidate
+	for _, p := range allPods {
+		out = append(out, pf.convert(p))
+	}
+	return out, nil
+}
+
+// GetPodCandidatesForAllNamespaces lists pods with a single cluster-scoped call and groups them by namespace.
+func (pf *PodsFetcher) GetPodCandidatesForAllNamespaces(ctx context.Context) (map[string][]model.PodCandidate, error) {
+	allPods, err := pf.list(ctx, metav1.NamespaceAll)
+	if err != nil {
+		return nil, fmt.Errorf("while gettting pods from all namespaces: %w", err)
+	}
+	out := make(map[string][]model.PodCandidate)
+	for _, p := range allPods {
+		out[p.Namespace] = append(out[p.Namespace], pf.convert(p))
+	}
+	return out, nil
+}
+
+func (pf *PodsFetcher) list(ctx context.Context, ns string) ([]v12.Pod, error) {
 	var allPods []v12.Pod
 	continueOption := ""
 	for {
-		list, err := pf.client.Pods(ns).List(ctx, metav1.ListOptions{Continue: continueOption})
+		list, err := pf.client.Pods(ns).List(ctx, metav1.ListOptions{Limit: listPageSize, Continue: continueOption})
 		if err != nil {
-			return nil, fmt.Errorf("while gettting pods from namespace: %s: %w", ns, err)
+			return nil, err
 		}
 		allPods = append(allPods, list.Items...)
 		continueOption = list.Continue
 		if continueOption == "" {
 			break
 		}
-
 	}
-	var out []model.PodCandidate
-	for _, p := range allPods {
-		out = append(out, pf.convert(p))
-	}
-	return out, nil
+	return allPods, nil
 }
 
 func (pf *PodsFetcher) convert(pod v12.Pod) model.PodCandidate {
+	desired, ready := int32(1), int32(0)
+	for _, c := range pod.Status.Conditions {
+		if c.Type == v12.PodReady && c.Status == v12.ConditionTrue {
+			ready = 1
+		}
+	}
 	return model.PodCandidate{
-		Labels:    pod.Labels,
-		OwnerName: getOwnerName(WorkloadPod, pod.Namespace, pod.Name),
+		Labels: pod.Labels,
+		Owner:  newOwner(WorkloadPod, pod.Namespace, pod.Name),
+
+		ControlledBy:    getControllerNames(pod),
+		DesiredReplicas: &desired,
+		ReadyReplicas:   ready,
+		LiveReplicas:    1,
+		ServiceAccount:  pod.Spec.ServiceAccountName,
+		NodeSelector:    pod.Spec.NodeSelector,
+		Completed:       pod.Status.Phase == v12.PodSucceeded || pod.Status.Phase == v12.PodFailed,
 	}
 }
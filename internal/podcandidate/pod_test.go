@@ -23,12 +23,96 @@ func TestPodCandidateFromPods(t *testing.T) {
 	// THEN
 	require.NoError(t, err)
 	require.Len(t, actual, 2)
-	assert.Contains(t, actual, model.PodCandidate{OwnerName: "pod/orders/pod-a", Labels: map[string]string{
+	assert.Contains(t, actual, model.PodCandidate{Owner: model.Owner{Kind: "pod", Namespace: "orders", Name: "pod-a"}, DesiredReplicas: int32Ptr(1), Labels: map[string]string{
 		"app": "app-a",
-	}})
-	assert.Contains(t, actual, model.PodCandidate{OwnerName: "pod/orders/pod-b", Labels: map[string]string{
+	}, LiveReplicas: 1})
+	assert.Contains(t, actual, model.PodCandidate{Owner: model.Owner{Kind: "pod", Namespace: "orders", Name: "pod-b"}, DesiredReplicas: int32Ptr(1), Labels: map[string]string{
 		"app": "app-b",
-	}})
+	}, LiveReplicas: 1})
+}
+
+func TestPodCandidateFromFinishedPod(t *testing.T) {
+	testCases := map[string]struct {
+		givenPhase        v1.PodPhase
+		expectedCompleted bool
+	}{
+		"pending pod":   {givenPhase: v1.PodPending},
+		"running pod":   {givenPhase: v1.PodRunning},
+		"succeeded pod": {givenPhase: v1.PodSucceeded, expectedCompleted: true},
+		"failed pod":    {givenPhase: v1.PodFailed, expectedCompleted: true},
+		"unknown phase": {givenPhase: v1.PodUnknown},
+	}
+
+	for tn, tc := range testCases {
+		t.Run(tn, func(t *testing.T) {
+			// GIVEN
+			pod := fixPodA()
+			pod.Status.Phase = tc.givenPhase
+			sut := podcandidate.NewPodsFetcher(fake.NewSimpleClientset(pod).CoreV1())
+			// WHEN
+			actual, err := sut.GetPodCandidatesForNamespace(context.Background(), "orders")
+			// THEN
+			require.NoError(t, err)
+			require.Len(t, actual, 1)
+			assert.Equal(t, tc.expectedCompleted, actual[0].Completed)
+			assert.Equal(t, !tc.expectedCompleted, actual[0].Running())
+		})
+	}
+}
+
+func TestPodCandidateFromControlledPods(t *testing.T) {
+	testCases := map[string]struct {
+		givenOwner  metav1.OwnerReference
+		givenLabels map[string]string
+		expected    []string
+	}{
+		"pod of deployment": {
+			givenOwner:  metav1.OwnerReference{Kind: "ReplicaSet", Name: "web-5d4f8b7c9"},
+			givenLabels: map[string]string{"pod-template-hash": "5d4f8b7c9"},
+			expected:    []string{"replicaset/orders/web-5d4f8b7c9", "deployment/orders/web"},
+		},
+		"pod of bare replicaset": {
+			givenOwner: metav1.OwnerReference{Kind: "ReplicaSet", Name: "web"},
+			expected:   []string{"replicaset/orders/web"},
+		},
+		"pod of rollout": {
+			givenOwner:  metav1.OwnerReference{Kind: "ReplicaSet", Name: "web-6c8d9f"},
+			givenLabels: map[string]string{"rollouts-pod-template-hash": "6c8d9f"},
+			expected:    []string{"replicaset/orders/web-6c8d9f", "rollout/orders/web"},
+		},
+		"pod of cronjob": {
+			givenOwner: metav1.OwnerReference{Kind: "Job", Name: "backup-27845160"},
+			expected:   []string{"job/orders/backup-27845160", "cronjob/orders/backup"},
+		},
+		"pod of job": {
+			givenOwner: metav1.OwnerReference{Kind: "Job", Name: "migrate-db"},
+			expected:   []string{"job/orders/migrate-db"},
+		},
+		"pod of statefulset": {
+			givenOwner: metav1.OwnerReference{Kind: "StatefulSet", Name: "db"},
+			expected:   []string{"statefulset/orders/db"},
+		},
+	}
+
+	for tn, tc := range testCases {
+		t.Run(tn, func(t *testing.T) {
+			// GIVEN
+			isController := true
+			pod := fixPodA()
+			tc.givenOwner.Controller = &isController
+			pod.OwnerReferences = []metav1.OwnerReference{tc.givenOwner}
+			for k, v := range tc.givenLabels {
+				pod.Labels[k] = v
+			}
+			sut := podcandidate.NewPodsFetcher(fake.NewSimpleClientset(pod).CoreV1())
+			// WHEN
+			actual, err := sut.GetPodCandidatesForNamespace(context.Background(), "orders")
+			// THEN
+			require.NoError(t, err)
+			require.Len(t, actual, 1)
+			assert.Equal(t, tc.expected, actual[0].ControlledBy)
+		})
+	}
 }
 
 func fixPodA() *v1.Pod {
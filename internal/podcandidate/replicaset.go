@@ -0,0 +1,84 @@
+package podcandidate
+
+import (
+	"context"
+	"fmt"
+
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	v1 "k8s.io/client-go/kubernetes/typed/apps/v1"
+
+	"github.com/aszecowka/netpolvalidator/internal/model"
+)
+
+// ReplicasetFetcher provides pod candidates from bare replicasets only. Replicasets controlled by deployments
+// are covered by the deployments.
+type ReplicasetFetcher struct {
+	client v1.ReplicaSetsGetter
+}
+
+func NewReplicasetFetcher(client v1.ReplicaSetsGetter) *ReplicasetFetcher {
+	return &ReplicasetFetcher{client: client}
+}
+
+func (rf *ReplicasetFetcher) GetPodCandidatesForNamespace(ctx context.Context, ns string) ([]model.PodCandidate, error) {
+	allReplicasets, err := rf.list(ctx, ns)
+	if err != nil {
+		return nil, fmt.Errorf("while gettting replicasets from namespace: %s: %w", ns, err)
+	}
+	var out []model.PodCandidate
+	for _, rs := range allReplicasets {
+		if rf.isBare(rs) {
+			out = append(out, rf.convert(rs))
+		}
+	}
+	return out, nil
+}
+
+// GetPodCandidatesForAllNamespaces lists replicasets with a single cluster-scoped call and groups them by namespace.
+func (rf *ReplicasetFetcher) GetPodCandidatesForAllNamespaces(ctx context.Context) (map[string][]model.PodCandidate, error) {
+	allReplicasets, err := rf.list(ctx, metav1.NamespaceAll)
+	if err != nil {
+		return nil, fmt.Errorf("while gettting replicasets from all namespaces: %w", err)
+	}
+	out := make(map[string][]model.PodCandidate)
+	for _, rs := range allReplicasets {
+		if rf.isBare(rs) {
+			out[rs.Namespace] = append(out[rs.Namespace], rf.convert(rs))
+		}
+	}
+	return out, nil
+}
+
+func (rf *ReplicasetFetcher) list(ctx context.Context, ns string) ([]appsv1.ReplicaSet, error) {
+	var allReplicasets []appsv1.ReplicaSet
+	continueOption := ""
+	for {
+		list, err := rf.client.ReplicaSets(ns).List(ctx, metav1.ListOptions{Limit: listPageSize, Continue: continueOption})
+		if err != nil {
+			return nil, err
+		}
+		allReplicasets = append(allReplicasets, list.Items...)
+		continueOption = list.Continue
+		if continueOption == "" {
+			break
+		}
+	}
+	return allReplicasets, nil
+}
+
+func (rf *ReplicasetFetcher) isBare(rs appsv1.ReplicaSet) bool {
+	return metav1.GetControllerOf(&rs) == nil
+}
+
+func (rf *ReplicasetFetcher) convert(rs appsv1.ReplicaSet) model.PodCandidate {
+	return model.PodCandidate{
+		Labels: rs.Spec.Template.Labels,
+		Owner:  newOwner(WorkloadReplicaset, rs.Namespace, rs.Name),
+
+		DesiredReplicas: replicasOrDefault(rs.Spec.Replicas),
+		ReadyReplicas:   rs.Status.ReadyReplicas,
+		ServiceAccount:  rs.Spec.Template.Spec.ServiceAccountName,
+		NodeSelector:    rs.Spec.Template.Spec.NodeSelector,
+	}
+}
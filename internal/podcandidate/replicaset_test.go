@@ -0,0 +1,87 @@
+package podcandidate_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/aszecowka/netpolvalidator/internal/model"
+	"github.com/aszecowka/netpolvalidator/internal/podcandidate"
+)
+
+func TestPodCandidateFromReplicasets(t *testing.T) {
+	// GIVEN
+	bareReplicaset := fixReplicasetA()
+	ownedReplicaset := fixReplicasetOwnedByDeployment()
+	fakeClientset := fake.NewSimpleClientset(&bareReplicaset, &ownedReplicaset)
+	sut := podcandidate.NewReplicasetFetcher(fakeClientset.AppsV1())
+	// WHEN
+	actual, err := sut.GetPodCandidatesForNamespace(context.Background(), "orders")
+	// THEN
+	require.NoError(t, err)
+	assert.Equal(t, []model.PodCandidate{{Owner: model.Owner{Kind: "replicaset", Namespace: "orders", Name: "rs-a"}, DesiredReplicas: int32Ptr(1), Labels: map[string]string{
+		"app": "app-a",
+	}}}, actual)
+}
+
+func TestPodCandidateFromReplicasetsInAllNamespaces(t *testing.T) {
+	// GIVEN
+	bareReplicaset := fixReplicasetA()
+	bareReplicaset.Namespace = "payments"
+	ownedReplicaset := fixReplicasetOwnedByDeployment()
+	fakeClientset := fake.NewSimpleClientset(&bareReplicaset, &ownedReplicaset)
+	sut := podcandidate.NewReplicasetFetcher(fakeClientset.AppsV1())
+	// WHEN
+	actual, err := sut.GetPodCandidatesForAllNamespaces(context.Background())
+	// THEN
+	require.NoError(t, err)
+	assert.Equal(t, map[string][]model.PodCandidate{
+		"payments": {{Owner: model.Owner{Kind: "replicaset", Namespace: "payments", Name: "rs-a"}, DesiredReplicas: int32Ptr(1), Labels: map[string]string{"app": "app-a"}}},
+	}, actual)
+}
+
+func fixReplicasetA() appsv1.ReplicaSet {
+	return appsv1.ReplicaSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "rs-a",
+			Namespace: "orders",
+		},
+		Spec: appsv1.ReplicaSetSpec{
+			Template: v1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: map[string]string{
+						"app": "app-a",
+					},
+				},
+			},
+		},
+	}
+}
+
+func fixReplicasetOwnedByDeployment() appsv1.ReplicaSet {
+	isController := true
+	return appsv1.ReplicaSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "deploy-b-5d4f8b7c9",
+			Namespace: "orders",
+			OwnerReferences: []metav1.OwnerReference{
+				{APIVersion: "apps/v1", Kind: "Deployment", Name: "deploy-b", Controller: &isController},
+			},
+		},
+		Spec: appsv1.ReplicaSetSpec{
+			Template: v1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: map[string]string{
+						"app": "app-b",
+					},
+				},
+			},
+		},
+	}
+}
@@ -0,0 +1,77 @@
+package podcandidate
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	v1 "k8s.io/client-go/kubernetes/typed/core/v1"
+
+	"github.com/aszecowka/netpolvalidator/internal/model"
+)
+
+type ReplicationControllerFetcher struct {
+	client v1.ReplicationControllersGetter
+}
+
+func NewReplicationControllerFetcher(client v1.ReplicationControllersGetter) *ReplicationControllerFetcher {
+	return &ReplicationControllerFetcher{client: client}
+}
+
+func (rf *ReplicationControllerFetcher) GetPodCandidatesForNamespace(ctx context.Context, ns string) ([]model.PodCandidate, error) {
+	allControllers, err := rf.list(ctx, ns)
+	if err != nil {
+		return nil, fmt.Errorf("while gettting replication controllers from namespace: %s: %w", ns, err)
+	}
+	var out []model.PodCandidate
+	for _, rc := range allControllers {
+		out = append(out, rf.convert(rc))
+	}
+	return out, nil
+}
+
+// GetPodCandidatesForAllNamespaces lists replication controllers with a single cluster-scoped call and groups them by namespace.
+func (rf *ReplicationControllerFetcher) GetPodCandidatesForAllNamespaces(ctx context.Context) (map[string][]model.PodCandidate, error) {
+	allControllers, err := rf.list(ctx, metav1.NamespaceAll)
+	if err != nil {
+		return nil, fmt.Errorf("while gettting replication controllers from all namespaces: %w", err)
+	}
+	out := make(map[string][]model.PodCandidate)
+	for _, rc := range allControllers {
+		out[rc.Namespace] = append(out[rc.Namespace], rf.convert(rc))
+	}
+	return out, nil
+}
+
+func (rf *ReplicationControllerFetcher) list(ctx context.Context, ns string) ([]corev1.ReplicationController, error) {
+	var allControllers []corev1.ReplicationController
+	continueOption := ""
+	for {
+		list, err := rf.client.ReplicationControllers(ns).List(ctx, metav1.ListOptions{Limit: listPageSize, Continue: continueOption})
+		if err != nil {
+			return nil, err
+		}
+		allControllers = append(allControllers, list.Items...)
+		continueOption = list.Continue
+		if continueOption == "" {
+			break
+		}
+	}
+	return allControllers, nil
+}
+
+func (rf *ReplicationControllerFetcher) convert(rc corev1.ReplicationController) model.PodCandidate {
+	out := model.PodCandidate{
+		Owner: newOwner(WorkloadReplicationController, rc.Namespace, rc.Name),
+
+		DesiredReplicas: replicasOrDefault(rc.Spec.Replicas),
+		ReadyReplicas:   rc.Status.ReadyReplicas,
+	}
+	if rc.Spec.Template != nil {
+		out.Labels = rc.Spec.Template.Labels
+		out.ServiceAccount = rc.Spec.Template.Spec.ServiceAccountName
+		out.NodeSelector = rc.Spec.Template.Spec.NodeSelector
+	}
+	return out
+}
@@ -0,0 +1,73 @@
+package podcandidate_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/aszecowka/netpolvalidator/internal/model"
+	"github.com/aszecowka/netpolvalidator/internal/podcandidate"
+)
+
+func TestPodCandidateFromReplicationControllers(t *testing.T) {
+	// GIVEN
+	rcA := fixReplicationControllerA()
+	rcWithoutTemplate := fixReplicationControllerA()
+	rcWithoutTemplate.Name = "rc-b"
+	rcWithoutTemplate.Spec.Template = nil
+	fakeClientset := fake.NewSimpleClientset(&rcA, &rcWithoutTemplate)
+	sut := podcandidate.NewReplicationControllerFetcher(fakeClientset.CoreV1())
+	// WHEN
+	actual, err := sut.GetPodCandidatesForNamespace(context.Background(), "orders")
+	// THEN
+	require.NoError(t, err)
+	require.Len(t, actual, 2)
+	assert.Contains(t, actual, model.PodCandidate{Owner: model.Owner{Kind: "replicationcontroller", Namespace: "orders", Name: "rc-a"}, DesiredReplicas: int32Ptr(1), Labels: map[string]string{
+		"app": "app-a",
+	}})
+	assert.Contains(t, actual, model.PodCandidate{Owner: model.Owner{Kind: "replicationcontroller", Namespace: "orders", Name: "rc-b"}, DesiredReplicas: int32Ptr(1)})
+}
+
+func TestPodCandidateFromReplicationControllersInAllNamespaces(t *testing.T) {
+	// GIVEN
+	rcA := fixReplicationControllerA()
+	rcB := fixReplicationControllerA()
+	rcB.Name = "rc-b"
+	rcB.Namespace = "payments"
+	fakeClientset := fake.NewSimpleClientset(&rcA, &rcB)
+	sut := podcandidate.NewReplicationControllerFetcher(fakeClientset.CoreV1())
+	// WHEN
+	actual, err := sut.GetPodCandidatesForAllNamespaces(context.Background())
+	// THEN
+	require.NoError(t, err)
+	require.Len(t, actual, 2)
+	assert.Equal(t, []model.PodCandidate{{Owner: model.Owner{Kind: "replicationcontroller", Namespace: "orders", Name: "rc-a"}, DesiredReplicas: int32Ptr(1), Labels: map[string]string{
+		"app": "app-a",
+	}}}, actual["orders"])
+	assert.Equal(t, []model.PodCandidate{{Owner: model.Owner{Kind: "replicationcontroller", Namespace: "payments", Name: "rc-b"}, DesiredReplicas: int32Ptr(1), Labels: map[string]string{
+		"app": "app-a",
+	}}}, actual["payments"])
+}
+
+func fixReplicationControllerA() v1.ReplicationController {
+	return v1.ReplicationController{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "rc-a",
+			Namespace: "orders",
+		},
+		Spec: v1.ReplicationControllerSpec{
+			Template: &v1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: map[string]string{
+						"app": "app-a",
+					},
+				},
+			},
+		},
+	}
+}
@@ -0,0 +1,98 @@
+package podcandidate
+
+import (
+	"context"
+	"fmt"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/dynamic"
+
+	"github.com/aszecowka/netpolvalidator/internal/model"
+)
+
+// RolloutFetcher provides pod candidates from Argo Rollouts. Rollouts that reference a deployment
+// with spec.workloadRef have no pod template and are covered by the deployment.
+// When Argo Rollouts are not installed in the cluster, there are no pod candidates.
+type RolloutFetcher struct {
+	client dynamic.Interface
+}
+
+func NewRolloutFetcher(client dynamic.Interface) *RolloutFetcher {
+	return &RolloutFetcher{client: client}
+}
+
+func (rf *RolloutFetcher) GetPodCandidatesForNamespace(ctx context.Context, ns string) ([]model.PodCandidate, error) {
+	allRollouts, err := rf.list(ctx, ns)
+	if err != nil {
+		return nil, fmt.Errorf("while gettting rollouts from namespace: %s: %w", ns, err)
+	}
+	var out []model.PodCandidate
+	for _, r := range allRollouts {
+		if candidate, ok := rf.convert(r); ok {
+			out = append(out, candidate)
+		}
+	}
+	return out, nil
+}
+
+// GetPodCandidatesForAllNamespaces lists rollouts with a single cluster-scoped call and groups them by namespace.
+func (rf *RolloutFetcher) GetPodCandidatesForAllNamespaces(ctx context.Context) (map[string][]model.PodCandidate, error) {
+	allRollouts, err := rf.list(ctx, metav1.NamespaceAll)
+	if err != nil {
+		return nil, fmt.Errorf("while gettting rollouts from all namespaces: %w", err)
+	}
+	out := make(map[string][]model.PodCandidate)
+	for _, r := range allRollouts {
+		if candidate, ok := rf.convert(r); ok {
+			out[r.GetNamespace()] = append(out[r.GetNamespace()], candidate)
+		}
+	}
+	return out, nil
+}
+
+func (rf *RolloutFetcher) list(ctx context.Context, ns string) ([]unstructured.Unstructured, error) {
+	var allRollouts []unstructured.Unstructured
+	continueOption := ""
+	for {
+		list, err := rf.client.Resource(RolloutsResource).Namespace(ns).List(ctx, metav1.ListOptions{Limit: listPageSize, Continue: continueOption})
+		if apierrors.IsNotFound(err) {
+			// the Rollout CRD is not installed
+			return nil, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		allRollouts = append(allRollouts, list.Items...)
+		continueOption = list.GetContinue()
+		if continueOption == "" {
+			break
+		}
+	}
+	return allRollouts, nil
+}
+
+func (rf *RolloutFetcher) convert(rollout unstructured.Unstructured) (model.PodCandidate, bool) {
+	labels, found, err := unstructured.NestedStringMap(rollout.Object, "spec", "template", "metadata", "labels")
+	if err != nil || !found {
+		return model.PodCandidate{}, false
+	}
+	out := model.PodCandidate{
+		Labels: labels,
+		Owner:  newOwner(WorkloadRollout, rollout.GetNamespace(), rollout.GetName()),
+
+		DesiredReplicas: replicasOrDefault(nil),
+		InjectedLabels:  rolloutInjectedLabels(),
+	}
+	if replicas, found, err := unstructured.NestedInt64(rollout.Object, "spec", "replicas"); err == nil && found {
+		desired := int32(replicas)
+		out.DesiredReplicas = &desired
+	}
+	if ready, found, err := unstructured.NestedInt64(rollout.Object, "status", "readyReplicas"); err == nil && found {
+		out.ReadyReplicas = int32(ready)
+	}
+	out.ServiceAccount, _, _ = unstructured.NestedString(rollout.Object, "spec", "template", "spec", "serviceAccountName")
+	out.NodeSelector, _, _ = unstructured.NestedStringMap(rollout.Object, "spec", "template", "spec", "nodeSelector")
+	return out, true
+}
@@ -0,0 +1,85 @@
+package podcandidate_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/aszecowka/netpolvalidator/internal/model"
+	"github.com/aszecowka/netpolvalidator/internal/podcandidate"
+)
+
+func TestPodCandidateFromRollouts(t *testing.T) {
+	// GIVEN
+	rolloutA := fixRollout("orders", "rollout-a", map[string]interface{}{"app": "app-a"})
+	rolloutWithWorkloadRef := fixRollout("orders", "rollout-b", nil)
+	fakeClient := dynamicfake.NewSimpleDynamicClient(runtime.NewScheme(), rolloutA, rolloutWithWorkloadRef)
+	sut := podcandidate.NewRolloutFetcher(fakeClient)
+	// WHEN
+	actual, err := sut.GetPodCandidatesForNamespace(context.Background(), "orders")
+	// THEN
+	require.NoError(t, err)
+	assert.Equal(t, []model.PodCandidate{{Owner: model.Owner{Kind: "rollout", Namespace: "orders", Name: "rollout-a"}, DesiredReplicas: int32Ptr(1), Labels: map[string]string{
+		"app": "app-a",
+	}, InjectedLabels: map[string][]string{"rollouts-pod-template-hash": nil}}}, actual)
+}
+
+func TestPodCandidateFromRolloutsInAllNamespaces(t *testing.T) {
+	// GIVEN
+	rolloutA := fixRollout("orders", "rollout-a", map[string]interface{}{"app": "app-a"})
+	rolloutB := fixRollout("payments", "rollout-b", map[string]interface{}{"app": "app-b"})
+	fakeClient := dynamicfake.NewSimpleDynamicClient(runtime.NewScheme(), rolloutA, rolloutB)
+	sut := podcandidate.NewRolloutFetcher(fakeClient)
+	// WHEN
+	actual, err := sut.GetPodCandidatesForAllNamespaces(context.Background())
+	// THEN
+	require.NoError(t, err)
+	assert.Equal(t, map[string][]model.PodCandidate{
+		"orders":   {{Owner: model.Owner{Kind: "rollout", Namespace: "orders", Name: "rollout-a"}, DesiredReplicas: int32Ptr(1), Labels: map[string]string{"app": "app-a"}, InjectedLabels: map[string][]string{"rollouts-pod-template-hash": nil}}},
+		"payments": {{Owner: model.Owner{Kind: "rollout", Namespace: "payments", Name: "rollout-b"}, DesiredReplicas: int32Ptr(1), Labels: map[string]string{"app": "app-b"}, InjectedLabels: map[string][]string{"rollouts-pod-template-hash": nil}}},
+	}, actual)
+}
+
+func TestPodCandidateFromCachedRollouts(t *testing.T) {
+	// GIVEN
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	require.NoError(t, indexer.Add(fixRollout("orders", "rollout-a", map[string]interface{}{"app": "app-a"})))
+	require.NoError(t, indexer.Add(fixRollout("orders", "rollout-b", nil)))
+	require.NoError(t, indexer.Add(fixRollout("payments", "rollout-c", map[string]interface{}{"app": "app-c"})))
+	sut := podcandidate.NewCachedRolloutFetcher(cache.NewGenericLister(indexer, podcandidate.RolloutsResource.GroupResource()))
+	// WHEN
+	actual, err := sut.GetPodCandidatesForNamespace(context.Background(), "orders")
+	// THEN
+	require.NoError(t, err)
+	assert.Equal(t, []model.PodCandidate{{Owner: model.Owner{Kind: "rollout", Namespace: "orders", Name: "rollout-a"}, DesiredReplicas: int32Ptr(1), Labels: map[string]string{
+		"app": "app-a",
+	}, InjectedLabels: map[string][]string{"rollouts-pod-template-hash": nil}}}, actual)
+}
+
+func fixRollout(namespace, name string, templateLabels map[string]interface{}) *unstructured.Unstructured {
+	spec := map[string]interface{}{
+		"workloadRef": map[string]interface{}{"apiVersion": "apps/v1", "kind": "Deployment", "name": name},
+	}
+	if templateLabels != nil {
+		spec = map[string]interface{}{
+			"template": map[string]interface{}{
+				"metadata": map[string]interface{}{"labels": templateLabels},
+			},
+		}
+	}
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "argoproj.io/v1alpha1",
+		"kind":       "Rollout",
+		"metadata": map[string]interface{}{
+			"namespace": namespace,
+			"name":      name,
+		},
+		"spec": spec,
+	}}
+}
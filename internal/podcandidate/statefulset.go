@@ -20,30 +20,56 @@ func NewStatefulsetsFetcher(client v1.StatefulSetsGetter) *StatefulsetFetcher {
 }
 
 func (sf *StatefulsetFetcher) GetPodCandidatesForNamespace(ctx context.Context, ns string) ([]model.PodCandidate, error) {
+	allStatefulsets, err := sf.list(ctx, ns)
+	if err != nil {
+		return nil, fmt.Errorf("while gettting statefulsets from namespace: %s: %w", ns, err)
+	}
+	var out []model.PodCandidate
+	for _, d := range allStatefulsets {
+		out = append(out, sf.convert(d))
+	}
+	return out, nil
+}
+
+// GetPodCandidatesForAllNamespaces lists statefulsets with a single cluster-scoped call and groups them by namespace.
+func (sf *StatefulsetFetcher) GetPodCandidatesForAllNamespaces(ctx context.Context) (map[string][]model.PodCandidate, error) {
+	allStatefulsets, err := sf.list(ctx, metav1.NamespaceAll)
+	if err != nil {
+		return nil, fmt.Errorf("while gettting statefulsets from all namespaces: %w", err)
+	}
+	out := make(map[string][]model.PodCandidate)
+	for _, d := range allStatefulsets {
+		out[d.Namespace] = append(out[d.Namespace], sf.convert(d))
+	}
+	return out, nil
+}
+
+func (sf *StatefulsetFetcher) list(ctx context.Context, ns string) ([]appsv1.StatefulSet, error) {
 	var allStatefulsets []appsv1.StatefulSet
 	continueOption := ""
 	for {
-		list, err := sf.client.StatefulSets(ns).List(ctx, metav1.ListOptions{Continue: continueOption})
+		list, err := sf.client.StatefulSets(ns).List(ctx, metav1.ListOptions{Limit: listPageSize, Continue: continueOption})
 		if err != nil {
-			return nil, fmt.Errorf("while gettting statefulsets from namespace: %s: %w", ns, err)
+			return nil, err
 		}
 		allStatefulsets = append(allStatefulsets, list.Items...)
 		continueOption = list.Continue
 		if continueOption == "" {
 			break
 		}
-
-	}
-	var out []model.PodCandidate
-	for _, d := range allStatefulsets {
-		out = append(out, sf.convert(d))
 	}
-	return out, nil
+	return allStatefulsets, nil
 }
 
 func (sf *StatefulsetFetcher) convert(ss appsv1.StatefulSet) model.PodCandidate {
 	return model.PodCandidate{
-		Labels:    ss.Spec.Template.Labels,
-		OwnerName: getOwnerName(WorkloadStatefulset, ss.Namespace, ss.Name),
+		Labels: ss.Spec.Template.Labels,
+		Owner:  newOwner(WorkloadStatefulset, ss.Namespace, ss.Name),
+
+		InjectedLabels:  statefulsetInjectedLabels(ss),
+		DesiredReplicas: replicasOrDefault(ss.Spec.Replicas),
+		ReadyReplicas:   ss.Status.ReadyReplicas,
+		ServiceAccount:  ss.Spec.Template.Spec.ServiceAccountName,
+		NodeSelector:    ss.Spec.Template.Spec.NodeSelector,
 	}
 }
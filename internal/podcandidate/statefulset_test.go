@@ -24,11 +24,19 @@ func TestPodCandidateFromStatefulsets(t *testing.T) {
 	// THEN
 	require.NoError(t, err)
 	require.Len(t, actual, 2)
-	assert.Contains(t, actual, model.PodCandidate{OwnerName: "statefulset/orders/statefulset-a", Labels: map[string]string{
+	assert.Contains(t, actual, model.PodCandidate{Owner: model.Owner{Kind: "statefulset", Namespace: "orders", Name: "statefulset-a"}, DesiredReplicas: int32Ptr(1), Labels: map[string]string{
 		"app": "app-a",
+	}, InjectedLabels: map[string][]string{
+		"statefulset.kubernetes.io/pod-name": {"statefulset-a-0"},
+		"apps.kubernetes.io/pod-index":       {"0"},
+		"controller-revision-hash":           nil,
 	}})
-	assert.Contains(t, actual, model.PodCandidate{OwnerName: "statefulset/orders/statefulset-b", Labels: map[string]string{
+	assert.Contains(t, actual, model.PodCandidate{Owner: model.Owner{Kind: "statefulset", Namespace: "orders", Name: "statefulset-b"}, DesiredReplicas: int32Ptr(1), Labels: map[string]string{
 		"app": "app-b",
+	}, InjectedLabels: map[string][]string{
+		"statefulset.kubernetes.io/pod-name": {"statefulset-b-0"},
+		"apps.kubernetes.io/pod-index":       {"0"},
+		"controller-revision-hash":           nil,
 	}})
 }
 
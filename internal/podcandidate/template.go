@@ -0,0 +1,48 @@
+package podcandidate
+
+import (
+	"encoding/json"
+	"fmt"
+
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/aszecowka/netpolvalidator/internal/model"
+)
+
+// TemplateOwner is a workload that creates pods from a pod template, together with the selector of its pods.
+type TemplateOwner struct {
+	Candidate model.PodCandidate
+	Selector  *metav1.LabelSelector
+}
+
+// DecodeTemplateOwner decodes a deployment, statefulset or daemonset from its JSON representation, e.g. from an admission request.
+func DecodeTemplateOwner(workloadType WorkloadType, raw []byte) (TemplateOwner, error) {
+	switch workloadType {
+	case WorkloadDeployment:
+		d := appsv1.Deployment{}
+		if err := json.Unmarshal(raw, &d); err != nil {
+			return TemplateOwner{}, fmt.Errorf("while decoding deployment: %w", err)
+		}
+		return TemplateOwner{Candidate: (&DeploymentsFetcher{}).convert(d), Selector: d.Spec.Selector}, nil
+	case WorkloadStatefulset:
+		ss := appsv1.StatefulSet{}
+		if err := json.Unmarshal(raw, &ss); err != nil {
+			return TemplateOwner{}, fmt.Errorf("while decoding statefulset: %w", err)
+		}
+		return TemplateOwner{Candidate: (&StatefulsetFetcher{}).convert(ss), Selector: ss.Spec.Selector}, nil
+	case WorkloadDaemonset:
+		ds := appsv1.DaemonSet{}
+		if err := json.Unmarshal(raw, &ds); err != nil {
+			return TemplateOwner{}, fmt.Errorf("while decoding daemonset: %w", err)
+		}
+		return TemplateOwner{Candidate: (&DaemonsetFetcher{}).convert(ds), Selector: ds.Spec.Selector}, nil
+	default:
+		return TemplateOwner{}, fmt.Errorf("unsupported workload type: %s", workloadType)
+	}
+}
+
+// IsPod reports whether the candidate was created from a pod rather than from a pod template of its owner.
+func IsPod(pc model.PodCandidate) bool {
+	return pc.Owner.Kind == string(WorkloadPod)
+}
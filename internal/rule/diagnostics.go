@@ -0,0 +1,68 @@
+package rule
+
+import (
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// explainNoMatch appends the selector to the message of a violation, together with how many objects satisfy each of its
+// requirements and the closest existing label, e.g. "no pods matching pod selector app=orders,tier=frontnd:
+// app=orders matches 2 of 3 pods, tier=frontnd matches 0 of 3 pods, tier=frontnd eliminated the last 2;
+// did you mean tier=frontend (deployment/orders/web)?". The message is returned as is, if there is nothing to add.
+func explainNoMatch(message string, selector labels.Selector, in []labelled) string {
+	diagnostics := describeRequirements(selector, in)
+	s, found := suggest(selector, in)
+	if diagnostics == "" && !found {
+		return message
+	}
+	out := fmt.Sprintf("%s %s", message, selector)
+	if diagnostics != "" {
+		out = fmt.Sprintf("%s: %s", out, diagnostics)
+	}
+	if found {
+		out = fmt.Sprintf("%s; %s", out, s)
+	}
+	return out
+}
+
+// describeRequirements tells how many objects satisfy every requirement of the selector on its own, and which
+// requirement, applied in order, eliminated the last objects. It is empty for selectors with a single requirement,
+// for which the selector itself says it all.
+func describeRequirements(selector labels.Selector, in []labelled) string {
+	requirements, selectable := selector.Requirements()
+	if !selectable || len(requirements) < 2 || len(in) == 0 {
+		return ""
+	}
+	kind := in[0].kind
+
+	var parts []string
+	for _, r := range requirements {
+		single := labels.NewSelector().Add(r)
+		satisfying := 0
+		for _, obj := range in {
+			if obj.matches(single) {
+				satisfying++
+			}
+		}
+		parts = append(parts, fmt.Sprintf("%s matches %d of %d %s", r.String(), satisfying, len(in), kind))
+	}
+
+	remaining := in
+	for _, r := range requirements {
+		single := labels.NewSelector().Add(r)
+		var left []labelled
+		for _, obj := range remaining {
+			if obj.matches(single) {
+				left = append(left, obj)
+			}
+		}
+		if len(left) == 0 {
+			parts = append(parts, fmt.Sprintf("%s eliminated the last %d", r.String(), len(remaining)))
+			break
+		}
+		remaining = left
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,135 @@
+package rule
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+	netv1 "k8s.io/api/networking/v1"
+
+	"github.com/aszecowka/netpolvalidator/internal/model"
+)
+
+// inactiveWorkloads reports selectors that match workloads, but none of them runs pods, e.g. because a deployment
+// is scaled to zero or a cronjob is suspended. Selectors that match nothing are reported by labelCorrectness.
+type inactiveWorkloads struct {
+	selectors *labelCorrectness
+}
+
+func NewInactiveWorkloads() *inactiveWorkloads {
+	return &inactiveWorkloads{selectors: NewLabelCorrectness()}
+}
+
+func (iw *inactiveWorkloads) Validate(state model.ClusterState) ([]model.Violation, error) {
+	var allViolations []model.Violation
+	for _, policiesForGivenNamespace := range state.NetworkPolicies {
+		for _, np := range policiesForGivenNamespace {
+			violations, err := iw.validateNetworkPolicy(np, state.Namespaces, state.PodCandidates)
+			if err != nil {
+				return nil, err
+			}
+			allViolations = append(allViolations, violations...)
+		}
+	}
+	return allViolations, nil
+}
+
+func (iw *inactiveWorkloads) validateNetworkPolicy(np netv1.NetworkPolicy, namespaces []v1.Namespace, podCandidates map[string][]model.PodCandidate) ([]model.Violation, error) {
+	var allViolations []model.Violation
+	matching, err := iw.selectors.getPodCandidatesMatchingSelector(np.Spec.PodSelector, podCandidates[np.Namespace])
+	if err != nil {
+		return nil, fmt.Errorf("while getting pod candidates that matches spec.PodSelector for %s: %w", prettyNetworkPolicy(np), err)
+	}
+	if onlyInactive(matching) {
+		allViolations = append(allViolations, model.NewViolation(np, reasonOnlyInactivePods, fmt.Sprintf(msgOnlyInactiveWorkloadsMatchingPodSelectorPattern, describeInactive(matching)), model.ViolationInactiveWorkloads))
+	}
+
+	for idxIngress, ingressRule := range np.Spec.Ingress {
+		for idxFrom, from := range ingressRule.From {
+			position := fmt.Sprintf("%d:%d", idxIngress+1, idxFrom+1)
+			violations, err := iw.validatePeer(np, from, position, model.Ingress, namespaces, podCandidates)
+			if err != nil {
+				return nil, err
+			}
+			allViolations = append(allViolations, violations...)
+		}
+	}
+	for idxEgress, egressRule := range np.Spec.Egress {
+		for idxTo, to := range egressRule.To {
+			position := fmt.Sprintf("%d:%d", idxEgress+1, idxTo+1)
+			violations, err := iw.validatePeer(np, to, position, model.Egress, namespaces, podCandidates)
+			if err != nil {
+				return nil, err
+			}
+			allViolations = append(allViolations, violations...)
+		}
+	}
+	return allViolations, nil
+}
+
+func (iw *inactiveWorkloads) validatePeer(np netv1.NetworkPolicy, peer netv1.NetworkPolicyPeer, position string, ruleType model.RuleType, namespaces []v1.Namespace, podCandidates map[string][]model.PodCandidate) ([]model.Violation, error) {
+	matching, err := iw.getPeerPodCandidates(np, peer, namespaces, podCandidates)
+	if err != nil {
+		return nil, fmt.Errorf("while getting pod candidates specified in the %s rule [%s] for %s: %w", ruleType, position, prettyNetworkPolicy(np), err)
+	}
+	if !onlyInactive(matching) {
+		return nil, nil
+	}
+	return []model.Violation{
+		model.NewViolationAt(np, peerPosition(ruleType, position), reasonOnlyInactivePeerPods, fmt.Sprintf(msgOnlyInactiveWorkloadsMatchingLabelsPattern, ruleType, position, describeInactive(matching)), model.ViolationInactiveWorkloads),
+	}, nil
+}
+
+// getPeerPodCandidates returns pod candidates selected by the peer. Peers with an IP block select none.
+func (iw *inactiveWorkloads) getPeerPodCandidates(np netv1.NetworkPolicy, peer netv1.NetworkPolicyPeer, namespaces []v1.Namespace, podCandidates map[string][]model.PodCandidate) ([]model.PodCandidate, error) {
+	if peer.PodSelector == nil && peer.NamespaceSelector == nil {
+		return nil, nil
+	}
+	inScope := podCandidates[np.Namespace]
+	if peer.NamespaceSelector != nil {
+		filteredNs, err := iw.selectors.getNamespacesMatchingSelector(namespaces, *peer.NamespaceSelector)
+		if err != nil {
+			return nil, err
+		}
+		inScope = iw.selectors.getPodsFromNamespaces(filteredNs, podCandidates)
+	}
+	if peer.PodSelector == nil {
+		return inScope, nil
+	}
+	return iw.selectors.getPodCandidatesMatchingSelector(*peer.PodSelector, inScope)
+}
+
+// onlyInactive reports whether there are candidates, but none of them is supposed to run pods.
+func onlyInactive(podCandidates []model.PodCandidate) bool {
+	if len(podCandidates) == 0 {
+		return false
+	}
+	for _, pc := range podCandidates {
+		if pc.Running() {
+			return false
+		}
+	}
+	return true
+}
+
+// describeInactive lists owners of the candidates together with the reason why they do not run pods,
+// e.g. deployment/orders/web (scaled to zero).
+func describeInactive(podCandidates []model.PodCandidate) string {
+	var out []string
+	for _, pc := range podCandidates {
+		var why []string
+		if pc.ScaledToZero() {
+			why = append(why, "scaled to zero")
+		}
+		if pc.Suspended {
+			why = append(why, "suspended")
+		}
+		if pc.Completed {
+			why = append(why, "completed")
+		}
+		out = append(out, fmt.Sprintf("%s (%s)", pc.OwnerName(), strings.Join(why, ", ")))
+	}
+	sort.Strings(out)
+	return strings.Join(out, ", ")
+}
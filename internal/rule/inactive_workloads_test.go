@@ -0,0 +1,139 @@
+package rule_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	netv1 "k8s.io/api/networking/v1"
+
+	"github.com/aszecowka/netpolvalidator/internal/model"
+	"github.com/aszecowka/netpolvalidator/internal/rule"
+)
+
+func TestInactiveWorkloads(t *testing.T) {
+	sut := rule.NewInactiveWorkloads()
+
+	t.Run("pod selector matches running workload", func(t *testing.T) {
+		// GIVEN
+		givenState := model.ClusterState{
+			Namespaces: []v1.Namespace{fixNsOrders()},
+			NetworkPolicies: map[string][]netv1.NetworkPolicy{
+				nsOrders: {fixIngressNetworkPolicyForOrdersA()},
+			},
+			PodCandidates: map[string][]model.PodCandidate{
+				nsOrders: {fixPodCandidateOrdersA()},
+			},
+		}
+		// WHEN
+		actual, err := sut.Validate(givenState)
+		// THEN
+		require.NoError(t, err)
+		assert.Empty(t, actual)
+	})
+
+	t.Run("pod selector matches no workloads", func(t *testing.T) {
+		// GIVEN
+		givenState := model.ClusterState{
+			Namespaces: []v1.Namespace{fixNsOrders()},
+			NetworkPolicies: map[string][]netv1.NetworkPolicy{
+				nsOrders: {fixIngressNetworkPolicyForOrdersA()},
+			},
+			PodCandidates: map[string][]model.PodCandidate{
+				nsOrders: {fixPodCandidateOrdersB()},
+			},
+		}
+		// WHEN
+		actual, err := sut.Validate(givenState)
+		// THEN
+		require.NoError(t, err)
+		assert.Empty(t, actual)
+	})
+
+	t.Run("pod selector matches only inactive workloads", func(t *testing.T) {
+		// GIVEN
+		zero := int32(0)
+		scaledToZero := fixPodCandidateOrdersA()
+		scaledToZero.Owner = model.Owner{Kind: "deployment", Namespace: nsOrders, Name: "orders-a"}
+		scaledToZero.DesiredReplicas = &zero
+		suspended := fixPodCandidateOrdersA()
+		suspended.Owner = model.Owner{Kind: "cronjob", Namespace: nsOrders, Name: "orders-a-cleanup"}
+		suspended.Suspended = true
+		completed := fixPodCandidateOrdersA()
+		completed.Owner = model.Owner{Kind: "job", Namespace: nsOrders, Name: "orders-a-migration"}
+		completed.Completed = true
+		givenState := model.ClusterState{
+			Namespaces: []v1.Namespace{fixNsOrders()},
+			NetworkPolicies: map[string][]netv1.NetworkPolicy{
+				nsOrders: {fixIngressNetworkPolicyForOrdersA()},
+			},
+			PodCandidates: map[string][]model.PodCandidate{
+				nsOrders: {scaledToZero, suspended, completed},
+			},
+		}
+		// WHEN
+		actual, err := sut.Validate(givenState)
+		// THEN
+		require.NoError(t, err)
+		assert.Equal(t, []model.Violation{
+			model.NewViolation(fixIngressNetworkPolicyForOrdersA(), "OnlyInactivePods", "only inactive workloads matching pod selector: "+
+				"cronjob/orders/orders-a-cleanup (suspended), deployment/orders/orders-a (scaled to zero), job/orders/orders-a-migration (completed)",
+				model.ViolationInactiveWorkloads),
+		}, actual)
+	})
+
+	t.Run("peers match only inactive workloads", func(t *testing.T) {
+		// GIVEN
+		np := getNetPol(t, `
+metadata:
+  name: orders-a
+  namespace: orders
+spec:
+  podSelector:
+    matchLabels:
+      app: orders-a
+  ingress:
+  - from:
+    - podSelector:
+        matchLabels:
+          app: orders-b
+    - namespaceSelector:
+        matchLabels:
+          domain: payments
+      podSelector:
+        matchLabels:
+          app: payments-a
+    - ipBlock:
+        cidr: 10.0.0.0/8
+  egress:
+  - to:
+    - namespaceSelector:
+        matchLabels:
+          domain: payments
+`)
+		suspended := fixPodCandidateOrdersB()
+		suspended.Owner = model.Owner{Kind: "cronjob", Namespace: nsOrders, Name: "orders-b"}
+		suspended.Suspended = true
+		completed := fixPodCandidatePaymentsB()
+		completed.Owner = model.Owner{Kind: "job", Namespace: nsPayments, Name: "payments-b"}
+		completed.Completed = true
+		givenState := model.ClusterState{
+			Namespaces: []v1.Namespace{fixNsOrders(), fixNsPayments()},
+			NetworkPolicies: map[string][]netv1.NetworkPolicy{
+				nsOrders: {np},
+			},
+			PodCandidates: map[string][]model.PodCandidate{
+				nsOrders:   {fixPodCandidateOrdersA(), suspended},
+				nsPayments: {fixPodCandidatePaymentsA(), completed},
+			},
+		}
+		// WHEN
+		actual, err := sut.Validate(givenState)
+		// THEN
+		require.NoError(t, err)
+		assert.Equal(t, []model.Violation{
+			model.NewViolationAt(np, "Ingress [1:1]", "OnlyInactivePeerPods", "only inactive workloads matching labels for Ingress rule [1:1]: cronjob/orders/orders-b (suspended)", model.ViolationInactiveWorkloads),
+		}, actual)
+	})
+}
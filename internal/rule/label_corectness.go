@@ -61,8 +61,7 @@ func (lc *labelCorrectness) validatePodSelector(np netv1.NetworkPolicy, podCandi
 
 	found := false
 	for _, pc := range podCandidates {
-		labelsAsASet := labels.Set(pc.Labels)
-		if selector.Matches(labelsAsASet) {
+		if pc.Matches(selector) {
 			found = true
 			break
 		}
@@ -72,7 +71,7 @@ func (lc *labelCorrectness) validatePodSelector(np netv1.NetworkPolicy, podCandi
 		return nil, nil
 	}
 	return []model.Violation{
-		model.NewViolation(np, msgNoPodsMatchingPodSelector, model.ViolationInvalidLabel),
+		model.NewViolation(np, reasonNoMatchingPods, explainNoMatch(msgNoPodsMatchingPodSelector, selector, labelledPodCandidates(podCandidates)), model.ViolationInvalidLabel),
 	}, nil
 }
 
@@ -114,7 +113,8 @@ func (lc *labelCorrectness) validatePeer(np netv1.NetworkPolicy, from netv1.Netw
 			return nil, fmt.Errorf("while getting namespaces specified in the %s rule [%s] for %s :%w", ruleType, position, prettyNetworkPolicy(np), err)
 		}
 		if len(filteredNs) == 0 {
-			allViolations = append(allViolations, model.NewViolation(np, getViolationMessageWithTypeAndPosition(msgNoNsMatchingLabelsForIngressRulePattern, ruleType, position), model.ViolationInvalidLabel))
+			message := lc.explainNoMatch(getViolationMessageWithTypeAndPosition(msgNoNsMatchingLabelsForIngressRulePattern, ruleType, position), *from.NamespaceSelector, labelledNamespaces(namespaces))
+			allViolations = append(allViolations, model.NewViolationAt(np, peerPosition(ruleType, position), reasonNoMatchingNamespaces, message, model.ViolationInvalidLabel))
 			return allViolations, nil
 		}
 		podsFromNs := lc.getPodsFromNamespaces(filteredNs, podCandidates)
@@ -124,7 +124,8 @@ func (lc *labelCorrectness) validatePeer(np netv1.NetworkPolicy, from netv1.Netw
 
 		}
 		if len(matching) == 0 {
-			allViolations = append(allViolations, model.NewViolation(np, getViolationMessageWithTypeAndPosition(msgNoPodsMatchingLabelsForIngressRulePattern, ruleType, position), model.ViolationInvalidLabel))
+			message := lc.explainNoMatch(getViolationMessageWithTypeAndPosition(msgNoPodsMatchingLabelsForIngressRulePattern, ruleType, position), *from.PodSelector, labelledPodCandidates(podsFromNs))
+			allViolations = append(allViolations, model.NewViolationAt(np, peerPosition(ruleType, position), reasonNoMatchingPeerPods, message, model.ViolationInvalidLabel))
 			return allViolations, nil
 		}
 	} else if from.PodSelector != nil {
@@ -133,7 +134,8 @@ func (lc *labelCorrectness) validatePeer(np netv1.NetworkPolicy, from netv1.Netw
 			return nil, fmt.Errorf("while getting pod candidates that matches pod selector in the %s rule [%s] for %s: %w", ruleType, position, prettyNetworkPolicy(np), err)
 		}
 		if len(podsInTheSameNs) == 0 {
-			allViolations = append(allViolations, model.NewViolation(np, getViolationMessageWithTypeAndPosition(msgNoPodsMatchingLabelsForIngressRulePattern, ruleType, position), model.ViolationInvalidLabel))
+			message := lc.explainNoMatch(getViolationMessageWithTypeAndPosition(msgNoPodsMatchingLabelsForIngressRulePattern, ruleType, position), *from.PodSelector, labelledPodCandidates(podCandidates[np.Namespace]))
+			allViolations = append(allViolations, model.NewViolationAt(np, peerPosition(ruleType, position), reasonNoMatchingPeerPods, message, model.ViolationInvalidLabel))
 			return allViolations, nil
 		}
 	} else if from.NamespaceSelector != nil {
@@ -142,7 +144,8 @@ func (lc *labelCorrectness) validatePeer(np netv1.NetworkPolicy, from netv1.Netw
 			return nil, fmt.Errorf("while getting namespaces specified in the %s rule [%s] for %s:%w", ruleType, position, prettyNetworkPolicy(np), err)
 		}
 		if len(filteredNs) == 0 {
-			allViolations = append(allViolations, model.NewViolation(np, getViolationMessageWithTypeAndPosition(msgNoNsMatchingLabelsForIngressRulePattern, ruleType, position), model.ViolationInvalidLabel))
+			message := lc.explainNoMatch(getViolationMessageWithTypeAndPosition(msgNoNsMatchingLabelsForIngressRulePattern, ruleType, position), *from.NamespaceSelector, labelledNamespaces(namespaces))
+			allViolations = append(allViolations, model.NewViolationAt(np, peerPosition(ruleType, position), reasonNoMatchingNamespaces, message, model.ViolationInvalidLabel))
 			return allViolations, nil
 		}
 
@@ -151,13 +154,22 @@ func (lc *labelCorrectness) validatePeer(np netv1.NetworkPolicy, from netv1.Netw
 			podsInFilteredNS += len(podCandidates[ns.Name])
 		}
 		if podsInFilteredNS == 0 {
-			allViolations = append(allViolations, model.NewViolation(np, getViolationMessageWithTypeAndPosition(msgNoPodsInNamespaceMatchingLabelsForIngressRulePattern, ruleType, position), model.ViolationInvalidLabel))
+			allViolations = append(allViolations, model.NewViolationAt(np, peerPosition(ruleType, position), reasonNoPodsInMatchingNamespaces, getViolationMessageWithTypeAndPosition(msgNoPodsInNamespaceMatchingLabelsForIngressRulePattern, ruleType, position), model.ViolationInvalidLabel))
 			return allViolations, nil
 		}
 	}
 	return nil, nil
 }
 
+// explainNoMatch returns the message extended with diagnostics of the label selector, or the message as is when the selector is invalid.
+func (lc *labelCorrectness) explainNoMatch(message string, labelSelector metav1.LabelSelector, in []labelled) string {
+	selector, err := metav1.LabelSelectorAsSelector(&labelSelector)
+	if err != nil {
+		return message
+	}
+	return explainNoMatch(message, selector, in)
+}
+
 func (lc *labelCorrectness) getNamespacesMatchingSelector(in []v1.Namespace, labelSelector metav1.LabelSelector) ([]v1.Namespace, error) {
 	selector, err := metav1.LabelSelectorAsSelector(&labelSelector)
 	if err != nil {
@@ -182,10 +194,9 @@ func (lc *labelCorrectness) getPodCandidatesMatchingSelector(labelSelector metav
 	}
 
 	var out []model.PodCandidate
-	for _, ns := range podCandidates {
-		labelsAsASet := labels.Set(ns.Labels)
-		if selector.Matches(labelsAsASet) {
-			out = append(out, ns)
+	for _, pc := range podCandidates {
+		if pc.Matches(selector) {
+			out = append(out, pc)
 		}
 	}
 
@@ -63,7 +63,7 @@ func TestValidate(t *testing.T) {
 		// THEN
 		require.NoError(t, err)
 		require.Len(t, actual, 1)
-		assert.Equal(t, model.NewViolation(fixIngressNetworkPolicyForOrdersA(), "no pods matching pod selector", model.ViolationInvalidLabel), actual[0])
+		assert.Equal(t, model.NewViolation(fixIngressNetworkPolicyForOrdersA(), "NoMatchingPods", "no pods matching pod selector app=orders-a; did you mean app=orders-b (deployment/orders/orders-b)?", model.ViolationInvalidLabel), actual[0])
 	})
 
 	t.Run("ingress rule for specific pods and namespaces is correct", func(t *testing.T) {
@@ -139,7 +139,7 @@ spec:
 		actualViolations, err := sut.Validate(givenState)
 		require.NoError(t, err)
 		require.Len(t, actualViolations, 1)
-		assert.Equal(t, model.NewViolation(givenNetPol, "no namespaces matching labels for Ingress rule [1:1]", model.ViolationInvalidLabel), actualViolations[0])
+		assert.Equal(t, model.NewViolationAt(givenNetPol, "Ingress [1:1]", "NoMatchingNamespaces", "no namespaces matching labels for Ingress rule [1:1]", model.ViolationInvalidLabel), actualViolations[0])
 	})
 
 	t.Run("ingress rule for specific pods and namespaces does not match any pods", func(t *testing.T) {
@@ -177,7 +177,7 @@ spec:
 		// THEN
 		require.NoError(t, err)
 		require.Len(t, actualViolations, 1)
-		assert.Equal(t, model.NewViolation(givenNetPol, "no pods matching labels for Ingress rule [1:1]", model.ViolationInvalidLabel), actualViolations[0])
+		assert.Equal(t, model.NewViolationAt(givenNetPol, "Ingress [1:1]", "NoMatchingPeerPods", "no pods matching labels for Ingress rule [1:1] app=orders-a; did you mean app=orders-b (deployment/orders/orders-b)?", model.ViolationInvalidLabel), actualViolations[0])
 	})
 
 	t.Run("ingress rule for pods in the network policy namespace is correct", func(t *testing.T) {
@@ -245,7 +245,7 @@ spec:
 		// THEN
 		require.NoError(t, err)
 		require.Len(t, actualViolations, 1)
-		assert.Equal(t, model.NewViolation(givenNetPol, "no pods matching labels for Ingress rule [1:1]", model.ViolationInvalidLabel), actualViolations[0])
+		assert.Equal(t, model.NewViolationAt(givenNetPol, "Ingress [1:1]", "NoMatchingPeerPods", "no pods matching labels for Ingress rule [1:1] app=payments-c; did you mean app=payments-a (deployment/payments/payments-a)?", model.ViolationInvalidLabel), actualViolations[0])
 	})
 
 	t.Run("ingress rule for all pods in the selected namespaces is correct", func(t *testing.T) {
@@ -315,7 +315,7 @@ spec:
 		// THEN
 		require.NoError(t, err)
 		require.Len(t, actualViolations, 1)
-		assert.Equal(t, model.NewViolation(givenNetPol, "no namespaces matching labels for Ingress rule [1:1]", model.ViolationInvalidLabel), actualViolations[0])
+		assert.Equal(t, model.NewViolationAt(givenNetPol, "Ingress [1:1]", "NoMatchingNamespaces", "no namespaces matching labels for Ingress rule [1:1]", model.ViolationInvalidLabel), actualViolations[0])
 	})
 
 	t.Run("ingress rule for all pods in the selected namespaces does not match any pod", func(t *testing.T) {
@@ -349,7 +349,7 @@ spec:
 		// THEN
 		require.NoError(t, err)
 		require.Len(t, actualViolations, 1)
-		assert.Equal(t, model.NewViolation(givenNetPol, "no pods in namespaces matching labels for Ingress rule: [1:1]", model.ViolationInvalidLabel), actualViolations[0])
+		assert.Equal(t, model.NewViolationAt(givenNetPol, "Ingress [1:1]", "NoPodsInMatchingNamespaces", "no pods in namespaces matching labels for Ingress rule: [1:1]", model.ViolationInvalidLabel), actualViolations[0])
 	})
 
 	// egress start
@@ -426,7 +426,7 @@ spec:
 		actualViolations, err := sut.Validate(givenState)
 		require.NoError(t, err)
 		require.Len(t, actualViolations, 1)
-		assert.Equal(t, model.NewViolation(givenNetPol, "no namespaces matching labels for Egress rule [1:1]", model.ViolationInvalidLabel), actualViolations[0])
+		assert.Equal(t, model.NewViolationAt(givenNetPol, "Egress [1:1]", "NoMatchingNamespaces", "no namespaces matching labels for Egress rule [1:1]", model.ViolationInvalidLabel), actualViolations[0])
 	})
 
 	t.Run("egress rule for specific pods and namespaces does not match any pods", func(t *testing.T) {
@@ -464,7 +464,7 @@ spec:
 		// THEN
 		require.NoError(t, err)
 		require.Len(t, actualViolations, 1)
-		assert.Equal(t, model.NewViolation(givenNetPol, "no pods matching labels for Egress rule [1:1]", model.ViolationInvalidLabel), actualViolations[0])
+		assert.Equal(t, model.NewViolationAt(givenNetPol, "Egress [1:1]", "NoMatchingPeerPods", "no pods matching labels for Egress rule [1:1] app=orders-a; did you mean app=orders-b (deployment/orders/orders-b)?", model.ViolationInvalidLabel), actualViolations[0])
 	})
 
 	t.Run("egress rule for pods in the network policy namespace is correct", func(t *testing.T) {
@@ -532,7 +532,7 @@ spec:
 		// THEN
 		require.NoError(t, err)
 		require.Len(t, actualViolations, 1)
-		assert.Equal(t, model.NewViolation(givenNetPol, "no pods matching labels for Egress rule [1:1]", model.ViolationInvalidLabel), actualViolations[0])
+		assert.Equal(t, model.NewViolationAt(givenNetPol, "Egress [1:1]", "NoMatchingPeerPods", "no pods matching labels for Egress rule [1:1] app=payments-c; did you mean app=payments-a (deployment/payments/payments-a)?", model.ViolationInvalidLabel), actualViolations[0])
 	})
 
 	t.Run("egress rule for all pods in the selected namespaces is correct", func(t *testing.T) {
@@ -602,7 +602,7 @@ spec:
 		// THEN
 		require.NoError(t, err)
 		require.Len(t, actualViolations, 1)
-		assert.Equal(t, model.NewViolation(givenNetPol, "no namespaces matching labels for Egress rule [1:1]", model.ViolationInvalidLabel), actualViolations[0])
+		assert.Equal(t, model.NewViolationAt(givenNetPol, "Egress [1:1]", "NoMatchingNamespaces", "no namespaces matching labels for Egress rule [1:1]", model.ViolationInvalidLabel), actualViolations[0])
 	})
 
 	t.Run("egress rule for all pods in the selected namespaces does not match any pod", func(t *testing.T) {
@@ -636,7 +636,7 @@ spec:
 		// THEN
 		require.NoError(t, err)
 		require.Len(t, actualViolations, 1)
-		assert.Equal(t, model.NewViolation(givenNetPol, "no pods in namespaces matching labels for Egress rule: [1:1]", model.ViolationInvalidLabel), actualViolations[0])
+		assert.Equal(t, model.NewViolationAt(givenNetPol, "Egress [1:1]", "NoPodsInMatchingNamespaces", "no pods in namespaces matching labels for Egress rule: [1:1]", model.ViolationInvalidLabel), actualViolations[0])
 	})
 	// egress stop
 
@@ -712,19 +712,217 @@ spec:
 		// THEN
 		require.NoError(t, err)
 		require.Len(t, actual, 8)
-		require.Contains(t, actual, model.NewViolation(netPolOrders, "no pods matching pod selector", model.ViolationInvalidLabel))
-		require.Contains(t, actual, model.NewViolation(netPolOrders, "no pods matching labels for Ingress rule [1:1]", model.ViolationInvalidLabel))
-		require.Contains(t, actual, model.NewViolation(netPolOrders, "no namespaces matching labels for Egress rule [1:1]", model.ViolationInvalidLabel))
-		require.Contains(t, actual, model.NewViolation(netPolOrders, "no pods matching labels for Egress rule [1:2]", model.ViolationInvalidLabel))
-		require.Contains(t, actual, model.NewViolation(netPolPayments, "no pods matching pod selector", model.ViolationInvalidLabel))
-		require.Contains(t, actual, model.NewViolation(netPolPayments, "no pods matching labels for Ingress rule [1:1]", model.ViolationInvalidLabel))
-		require.Contains(t, actual, model.NewViolation(netPolPayments, "no namespaces matching labels for Egress rule [1:1]", model.ViolationInvalidLabel))
-		require.Contains(t, actual, model.NewViolation(netPolPayments, "no pods matching labels for Egress rule [1:2]", model.ViolationInvalidLabel))
+		require.Contains(t, actual, model.NewViolation(netPolOrders, "NoMatchingPods", "no pods matching pod selector", model.ViolationInvalidLabel))
+		require.Contains(t, actual, model.NewViolationAt(netPolOrders, "Ingress [1:1]", "NoMatchingPeerPods", "no pods matching labels for Ingress rule [1:1]", model.ViolationInvalidLabel))
+		require.Contains(t, actual, model.NewViolationAt(netPolOrders, "Egress [1:1]", "NoMatchingNamespaces", "no namespaces matching labels for Egress rule [1:1]", model.ViolationInvalidLabel))
+		require.Contains(t, actual, model.NewViolationAt(netPolOrders, "Egress [1:2]", "NoMatchingPeerPods", "no pods matching labels for Egress rule [1:2]", model.ViolationInvalidLabel))
+		require.Contains(t, actual, model.NewViolation(netPolPayments, "NoMatchingPods", "no pods matching pod selector", model.ViolationInvalidLabel))
+		require.Contains(t, actual, model.NewViolationAt(netPolPayments, "Ingress [1:1]", "NoMatchingPeerPods", "no pods matching labels for Ingress rule [1:1]", model.ViolationInvalidLabel))
+		require.Contains(t, actual, model.NewViolationAt(netPolPayments, "Egress [1:1]", "NoMatchingNamespaces", "no namespaces matching labels for Egress rule [1:1]", model.ViolationInvalidLabel))
+		require.Contains(t, actual, model.NewViolationAt(netPolPayments, "Egress [1:2]", "NoMatchingPeerPods", "no pods matching labels for Egress rule [1:2]", model.ViolationInvalidLabel))
 
 	})
 
 }
 
+func TestValidateSuggestions(t *testing.T) {
+	sut := rule.NewLabelCorrectness()
+	componentA := model.PodCandidate{
+		Owner:  model.Owner{Kind: "deployment", Namespace: nsOrders, Name: "component-a"},
+		Labels: map[string]string{labelApp: "component-a", "tier": "backend"},
+	}
+	testCases := map[string]struct {
+		givenNetPol      string
+		expectedMessages []string
+	}{
+		"typo in pod selector": {
+			givenNetPol: `
+metadata:
+  name: typo
+  namespace: orders
+spec:
+  podSelector:
+    matchLabels:
+      app: componet-a
+`,
+			expectedMessages: []string{"no pods matching pod selector app=componet-a; did you mean app=component-a (deployment/orders/component-a)?"},
+		},
+		"typo in label key of peer": {
+			givenNetPol: `
+metadata:
+  name: typo
+  namespace: orders
+spec:
+  podSelector: {}
+  ingress:
+  - from:
+    - podSelector:
+        matchLabels:
+          tier: backend
+          apps: component-a
+`,
+			expectedMessages: []string{"no pods matching labels for Ingress rule [1:1] apps=component-a,tier=backend: " +
+				"apps=component-a matches 0 of 1 pods, tier=backend matches 1 of 1 pods, apps=component-a eliminated the last 1; did you mean app=component-a (deployment/orders/component-a)?"},
+		},
+		"typo in namespace selector": {
+			givenNetPol: `
+metadata:
+  name: typo
+  namespace: orders
+spec:
+  podSelector: {}
+  egress:
+  - to:
+    - namespaceSelector:
+        matchLabels:
+          domain: payment
+`,
+			expectedMessages: []string{"no namespaces matching labels for Egress rule [1:1] domain=payment; did you mean domain=payments (namespace/payments)?"},
+		},
+		"no suggestion when other labels do not match either": {
+			givenNetPol: `
+metadata:
+  name: typo
+  namespace: orders
+spec:
+  podSelector:
+    matchLabels:
+      app: componet-a
+      tier: frontend
+`,
+			expectedMessages: []string{"no pods matching pod selector app=componet-a,tier=frontend: " +
+				"app=componet-a matches 0 of 1 pods, tier=frontend matches 0 of 1 pods, app=componet-a eliminated the last 1"},
+		},
+		"no suggestion for unrelated labels": {
+			givenNetPol: `
+metadata:
+  name: typo
+  namespace: orders
+spec:
+  podSelector:
+    matchLabels:
+      app: payments
+`,
+			expectedMessages: []string{"no pods matching pod selector"},
+		},
+	}
+
+	for tn, tc := range testCases {
+		t.Run(tn, func(t *testing.T) {
+			// GIVEN
+			givenNetPol := getNetPol(t, tc.givenNetPol)
+			givenState := model.ClusterState{
+				Namespaces: []v1.Namespace{fixNsOrders(), fixNsPayments()},
+				NetworkPolicies: map[string][]netv1.NetworkPolicy{
+					nsOrders: {givenNetPol},
+				},
+				PodCandidates: map[string][]model.PodCandidate{
+					nsOrders: {componentA},
+				},
+			}
+			// WHEN
+			actual, err := sut.Validate(givenState)
+			// THEN
+			require.NoError(t, err)
+			var actualMessages []string
+			for _, v := range actual {
+				actualMessages = append(actualMessages, v.Message)
+			}
+			assert.Equal(t, tc.expectedMessages, actualMessages)
+		})
+	}
+}
+
+func TestValidatePartialMatches(t *testing.T) {
+	sut := rule.NewLabelCorrectness()
+	givenPodCandidates := []model.PodCandidate{
+		{Owner: model.Owner{Kind: "deployment", Namespace: nsOrders, Name: "web"}, Labels: map[string]string{labelApp: "orders", "tier": "frontend"}},
+		{Owner: model.Owner{Kind: "deployment", Namespace: nsOrders, Name: "api"}, Labels: map[string]string{labelApp: "orders", "tier": "backend"}},
+		{Owner: model.Owner{Kind: "deployment", Namespace: nsOrders, Name: "admin"}, Labels: map[string]string{labelApp: "admin", "tier": "frontend"}},
+	}
+	testCases := map[string]struct {
+		givenNetPol      string
+		expectedMessages []string
+	}{
+		"requirement eliminating the last pods": {
+			givenNetPol: `
+metadata:
+  name: partial
+  namespace: orders
+spec:
+  podSelector:
+    matchLabels:
+      app: orders
+      tier: database
+`,
+			expectedMessages: []string{"no pods matching pod selector app=orders,tier=database: " +
+				"app=orders matches 2 of 3 pods, tier=database matches 0 of 3 pods, tier=database eliminated the last 2"},
+		},
+		"requirements satisfied separately, but not together": {
+			givenNetPol: `
+metadata:
+  name: partial
+  namespace: orders
+spec:
+  podSelector: {}
+  ingress:
+  - from:
+    - podSelector:
+        matchLabels:
+          app: admin
+        matchExpressions:
+        - key: tier
+          operator: In
+          values: [backend]
+`,
+			expectedMessages: []string{"no pods matching labels for Ingress rule [1:1] app=admin,tier in (backend): " +
+				"app=admin matches 1 of 3 pods, tier in (backend) matches 1 of 3 pods, tier in (backend) eliminated the last 1"},
+		},
+		"namespace selector": {
+			givenNetPol: `
+metadata:
+  name: partial
+  namespace: orders
+spec:
+  podSelector: {}
+  egress:
+  - to:
+    - namespaceSelector:
+        matchLabels:
+          domain: orders
+          team: checkout
+`,
+			expectedMessages: []string{"no namespaces matching labels for Egress rule [1:1] domain=orders,team=checkout: " +
+				"domain=orders matches 1 of 2 namespaces, team=checkout matches 0 of 2 namespaces, team=checkout eliminated the last 1"},
+		},
+	}
+
+	for tn, tc := range testCases {
+		t.Run(tn, func(t *testing.T) {
+			// GIVEN
+			givenNetPol := getNetPol(t, tc.givenNetPol)
+			givenState := model.ClusterState{
+				Namespaces: []v1.Namespace{fixNsOrders(), fixNsPayments()},
+				NetworkPolicies: map[string][]netv1.NetworkPolicy{
+					nsOrders: {givenNetPol},
+				},
+				PodCandidates: map[string][]model.PodCandidate{
+					nsOrders: givenPodCandidates,
+				},
+			}
+			// WHEN
+			actual, err := sut.Validate(givenState)
+			// THEN
+			require.NoError(t, err)
+			var actualMessages []string
+			for _, v := range actual {
+				actualMessages = append(actualMessages, v.Message)
+			}
+			assert.Equal(t, tc.expectedMessages, actualMessages)
+		})
+	}
+}
+
 func fixNsOrders() v1.Namespace {
 	return v1.Namespace{
 		ObjectMeta: metav1.ObjectMeta{
@@ -781,6 +979,7 @@ func fixIngressNetworkPolicyForPaymentsA() netv1.NetworkPolicy {
 
 func fixPodCandidateOrdersA() model.PodCandidate {
 	return model.PodCandidate{
+		Owner: model.Owner{Kind: "deployment", Namespace: nsOrders, Name: "orders-a"},
 		Labels: map[string]string{
 			labelApp: "orders-a",
 		},
@@ -789,6 +988,7 @@ func fixPodCandidateOrdersA() model.PodCandidate {
 
 func fixPodCandidateOrdersB() model.PodCandidate {
 	return model.PodCandidate{
+		Owner: model.Owner{Kind: "deployment", Namespace: nsOrders, Name: "orders-b"},
 		Labels: map[string]string{
 			labelApp: "orders-b",
 		},
@@ -797,6 +997,7 @@ func fixPodCandidateOrdersB() model.PodCandidate {
 
 func fixPodCandidatePaymentsA() model.PodCandidate {
 	return model.PodCandidate{
+		Owner: model.Owner{Kind: "deployment", Namespace: nsPayments, Name: "payments-a"},
 		Labels: map[string]string{
 			labelApp: "payments-a",
 		},
@@ -805,6 +1006,7 @@ func fixPodCandidatePaymentsA() model.PodCandidate {
 
 func fixPodCandidatePaymentsB() model.PodCandidate {
 	return model.PodCandidate{
+		Owner: model.Owner{Kind: "deployment", Namespace: nsPayments, Name: "payments-b"},
 		Labels: map[string]string{
 			labelApp: "payments-b",
 		},
@@ -0,0 +1,69 @@
+package rule
+
+import (
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	"github.com/aszecowka/netpolvalidator/internal/model"
+)
+
+// LabelFix replaces a label of a selector that matches nothing with an existing label, e.g. app=componet-a with app=component-a.
+type LabelFix struct {
+	Key, Value       string
+	NewKey, NewValue string
+}
+
+// SuggestPodSelectorFix returns the fix of a typo in the selector, if the selector matches none of the pod candidates
+// and exactly one existing label is the closest to one of the selector's labels.
+func SuggestPodSelectorFix(labelSelector metav1.LabelSelector, podCandidates []model.PodCandidate) (LabelFix, bool) {
+	return suggestFix(labelSelector, labelledPodCandidates(podCandidates))
+}
+
+// SuggestNamespaceSelectorFix returns the fix of a typo in the selector, if the selector matches none of the namespaces
+// and exactly one existing label is the closest to one of the selector's labels.
+func SuggestNamespaceSelectorFix(labelSelector metav1.LabelSelector, namespaces []v1.Namespace) (LabelFix, bool) {
+	return suggestFix(labelSelector, labelledNamespaces(namespaces))
+}
+
+func suggestFix(labelSelector metav1.LabelSelector, in []labelled) (LabelFix, bool) {
+	selector, err := metav1.LabelSelectorAsSelector(&labelSelector)
+	if err != nil || selector.Empty() {
+		return LabelFix{}, false
+	}
+	for _, obj := range in {
+		if obj.matches(selector) {
+			return LabelFix{}, false
+		}
+	}
+	s, found := suggest(selector, in)
+	if !found || s.ambiguous {
+		return LabelFix{}, false
+	}
+	return LabelFix{Key: s.replacedKey, Value: s.replacedValue, NewKey: s.key, NewValue: s.value}, true
+}
+
+// Apply returns a copy of the selector with the label replaced, both in matchLabels and in matchExpressions.
+func (f LabelFix) Apply(selector metav1.LabelSelector) metav1.LabelSelector {
+	out := selector.DeepCopy()
+	if value, found := out.MatchLabels[f.Key]; found && value == f.Value {
+		delete(out.MatchLabels, f.Key)
+		out.MatchLabels[f.NewKey] = f.NewValue
+	}
+	for idx, expr := range out.MatchExpressions {
+		if expr.Key != f.Key || expr.Operator != metav1.LabelSelectorOpIn {
+			continue
+		}
+		for valueIdx, value := range expr.Values {
+			if value == f.Value {
+				out.MatchExpressions[idx].Key = f.NewKey
+				out.MatchExpressions[idx].Values[valueIdx] = f.NewValue
+			}
+		}
+	}
+	return *out
+}
+
+func (f LabelFix) String() string {
+	return labels.Set{f.Key: f.Value}.String() + " with " + labels.Set{f.NewKey: f.NewValue}.String()
+}
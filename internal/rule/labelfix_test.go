@@ -0,0 +1,74 @@
+package rule_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/aszecowka/netpolvalidator/internal/model"
+	"github.com/aszecowka/netpolvalidator/internal/rule"
+)
+
+func TestSuggestPodSelectorFix(t *testing.T) {
+	givenPodCandidates := []model.PodCandidate{
+		{Owner: model.Owner{Kind: "deployment", Namespace: nsOrders, Name: "orders-a"}, Labels: map[string]string{labelApp: "orders-a"}},
+		{Owner: model.Owner{Kind: "deployment", Namespace: nsOrders, Name: "orders-b"}, Labels: map[string]string{labelApp: "orders-b"}},
+	}
+	testCases := map[string]struct {
+		givenLabels   map[string]string
+		expectedFix   rule.LabelFix
+		expectedFound bool
+	}{
+		"single closest label": {
+			givenLabels:   map[string]string{labelApp: "order-a"},
+			expectedFix:   rule.LabelFix{Key: labelApp, Value: "order-a", NewKey: labelApp, NewValue: "orders-a"},
+			expectedFound: true,
+		},
+		"equally close labels": {
+			givenLabels: map[string]string{labelApp: "orders-c"},
+		},
+		"no close labels": {
+			givenLabels: map[string]string{labelApp: "payments"},
+		},
+		"selector matching pods": {
+			givenLabels: map[string]string{labelApp: "orders-a"},
+		},
+	}
+
+	for tn, tc := range testCases {
+		t.Run(tn, func(t *testing.T) {
+			// GIVEN
+			givenSelector := metav1.LabelSelector{MatchLabels: tc.givenLabels}
+			// WHEN
+			actualFix, actualFound := rule.SuggestPodSelectorFix(givenSelector, givenPodCandidates)
+			// THEN
+			assert.Equal(t, tc.expectedFound, actualFound)
+			assert.Equal(t, tc.expectedFix, actualFix)
+		})
+	}
+}
+
+func TestLabelFixApply(t *testing.T) {
+	// GIVEN
+	givenFix := rule.LabelFix{Key: "ap", Value: "web", NewKey: labelApp, NewValue: "web"}
+	givenSelector := metav1.LabelSelector{
+		MatchLabels: map[string]string{"ap": "web", "tier": "frontend"},
+		MatchExpressions: []metav1.LabelSelectorRequirement{
+			{Key: "ap", Operator: metav1.LabelSelectorOpIn, Values: []string{"web", "api"}},
+			{Key: "ap", Operator: metav1.LabelSelectorOpNotIn, Values: []string{"web"}},
+		},
+	}
+	// WHEN
+	actual := givenFix.Apply(givenSelector)
+	// THEN
+	assert.Equal(t, metav1.LabelSelector{
+		MatchLabels: map[string]string{labelApp: "web", "tier": "frontend"},
+		MatchExpressions: []metav1.LabelSelectorRequirement{
+			{Key: labelApp, Operator: metav1.LabelSelectorOpIn, Values: []string{"web", "api"}},
+			{Key: "ap", Operator: metav1.LabelSelectorOpNotIn, Values: []string{"web"}},
+		},
+	}, actual)
+	assert.Equal(t, map[string]string{"ap": "web", "tier": "frontend"}, givenSelector.MatchLabels)
+	assert.Equal(t, "ap=web with app=web", givenFix.String())
+}
@@ -11,8 +11,27 @@ const (
 	msgNoNsMatchingLabelsForIngressRulePattern              = "no namespaces matching labels for %s rule [%s]"
 	msgNoPodsMatchingLabelsForIngressRulePattern            = "no pods matching labels for %s rule [%s]"
 	msgNoPodsInNamespaceMatchingLabelsForIngressRulePattern = "no pods in namespaces matching labels for %s rule: [%s]"
+	msgOnlyInactiveWorkloadsMatchingPodSelectorPattern      = "only inactive workloads matching pod selector: %s"
+	msgOnlyInactiveWorkloadsMatchingLabelsPattern           = "only inactive workloads matching labels for %s rule [%s]: %s"
+	msgIgnoredRulesPattern                                  = "%[1]s rules are ignored, because policyTypes does not include %[1]s"
+)
+
+// reasons of violations, see model.Violation.Reason
+const (
+	reasonNoMatchingPods             = "NoMatchingPods"
+	reasonNoMatchingNamespaces       = "NoMatchingNamespaces"
+	reasonNoMatchingPeerPods         = "NoMatchingPeerPods"
+	reasonNoPodsInMatchingNamespaces = "NoPodsInMatchingNamespaces"
+	reasonOnlyInactivePods           = "OnlyInactivePods"
+	reasonOnlyInactivePeerPods       = "OnlyInactivePeerPods"
+	reasonIgnoredRules               = "IgnoredRules"
 )
 
 func getViolationMessageWithTypeAndPosition(pattern string, ruleType model.RuleType, position string) string {
 	return fmt.Sprintf(pattern, ruleType, position)
 }
+
+// peerPosition returns the position of a peer of an ingress or egress rule, see model.Violation.Position.
+func peerPosition(ruleType model.RuleType, position string) string {
+	return fmt.Sprintf("%s [%s]", ruleType, position)
+}
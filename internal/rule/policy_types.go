@@ -0,0 +1,59 @@
+package rule
+
+import (
+	"fmt"
+
+	netv1 "k8s.io/api/networking/v1"
+
+	"github.com/aszecowka/netpolvalidator/internal/model"
+)
+
+// policyTypes reports ingress and egress rules that have no effect, because policyTypes of the network policy
+// is set explicitly and does not include their direction.
+type policyTypes struct{}
+
+func NewPolicyTypes() *policyTypes {
+	return &policyTypes{}
+}
+
+func (pt *policyTypes) Validate(state model.ClusterState) ([]model.Violation, error) {
+	var allViolations []model.Violation
+	for _, policiesForGivenNamespace := range state.NetworkPolicies {
+		for _, np := range policiesForGivenNamespace {
+			for _, policyType := range IgnoredPolicyTypes(np) {
+				allViolations = append(allViolations, model.NewViolationAt(np, string(policyType), reasonIgnoredRules, fmt.Sprintf(msgIgnoredRulesPattern, policyType), model.ViolationIgnoredRules))
+			}
+		}
+	}
+	return allViolations, nil
+}
+
+// DependsOn tells that violations depend on network policies and on namespaces, as namespace labels decide
+// which network policies are in the validation scope.
+func (pt *policyTypes) DependsOn() []string {
+	return []string{model.KindNamespace, model.KindNetworkPolicy}
+}
+
+// IgnoredPolicyTypes returns directions, in which the network policy has rules, but policyTypes does not include them.
+func IgnoredPolicyTypes(np netv1.NetworkPolicy) []netv1.PolicyType {
+	if len(np.Spec.PolicyTypes) == 0 {
+		return nil
+	}
+	var out []netv1.PolicyType
+	if len(np.Spec.Ingress) > 0 && !hasPolicyType(np, netv1.PolicyTypeIngress) {
+		out = append(out, netv1.PolicyTypeIngress)
+	}
+	if len(np.Spec.Egress) > 0 && !hasPolicyType(np, netv1.PolicyTypeEgress) {
+		out = append(out, netv1.PolicyTypeEgress)
+	}
+	return out
+}
+
+func hasPolicyType(np netv1.NetworkPolicy, policyType netv1.PolicyType) bool {
+	for _, pt := range np.Spec.PolicyTypes {
+		if pt == policyType {
+			return true
+		}
+	}
+	return false
+}
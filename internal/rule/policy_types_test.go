@@ -0,0 +1,90 @@
+package rule_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	netv1 "k8s.io/api/networking/v1"
+
+	"github.com/aszecowka/netpolvalidator/internal/model"
+	"github.com/aszecowka/netpolvalidator/internal/rule"
+)
+
+func TestPolicyTypes(t *testing.T) {
+	testCases := map[string]struct {
+		givenNetPol string
+		// expectedViolations holds messages by position
+		expectedViolations map[string]string
+	}{
+		"default policy types": {
+			givenNetPol: `
+metadata:
+  name: web
+  namespace: orders
+spec:
+  podSelector: {}
+  egress:
+  - to:
+    - podSelector: {}
+`,
+		},
+		"egress rules without Egress policy type": {
+			givenNetPol: `
+metadata:
+  name: web
+  namespace: orders
+spec:
+  podSelector: {}
+  policyTypes: [Ingress]
+  egress:
+  - to:
+    - podSelector: {}
+`,
+			expectedViolations: map[string]string{"Egress": "Egress rules are ignored, because policyTypes does not include Egress"},
+		},
+		"ingress rules without Ingress policy type": {
+			givenNetPol: `
+metadata:
+  name: web
+  namespace: orders
+spec:
+  podSelector: {}
+  policyTypes: [Egress]
+  ingress:
+  - from:
+    - podSelector: {}
+`,
+			expectedViolations: map[string]string{"Ingress": "Ingress rules are ignored, because policyTypes does not include Ingress"},
+		},
+		"deny all egress": {
+			givenNetPol: `
+metadata:
+  name: web
+  namespace: orders
+spec:
+  podSelector: {}
+  policyTypes: [Ingress, Egress]
+`,
+		},
+	}
+
+	for tn, tc := range testCases {
+		t.Run(tn, func(t *testing.T) {
+			// GIVEN
+			givenNetPol := getNetPol(t, tc.givenNetPol)
+			givenState := model.ClusterState{
+				NetworkPolicies: map[string][]netv1.NetworkPolicy{nsOrders: {givenNetPol}},
+			}
+			// WHEN
+			actual, err := rule.NewPolicyTypes().Validate(givenState)
+			// THEN
+			require.NoError(t, err)
+			var expected []model.Violation
+			for position, message := range tc.expectedViolations {
+				expected = append(expected, model.NewViolationAt(givenNetPol, position, "IgnoredRules", message, model.ViolationIgnoredRules))
+			}
+			assert.ElementsMatch(t, expected, actual)
+		})
+	}
+}
@@ -0,0 +1,193 @@
+package rule
+
+import (
+	"fmt"
+	"sort"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/selection"
+
+	"github.com/aszecowka/netpolvalidator/internal/model"
+)
+
+// maxSuggestionDistance is the maximum edit distance between a label of a selector and an existing label,
+// for the existing label to be suggested as a fix of a typo.
+const maxSuggestionDistance = 2
+
+// labelled is a pod candidate or a namespace a selector may be corrected to match.
+type labelled struct {
+	// kind is used in messages, e.g. pods or namespaces.
+	kind    string
+	name    string
+	labels  map[string]string
+	matches func(selector labels.Selector) bool
+}
+
+// suggestion is an existing label that makes a selector match, if it replaces one of the selector's labels.
+type suggestion struct {
+	// replacedKey and replacedValue identify the label of the selector to replace.
+	replacedKey, replacedValue string
+	key, value                 string
+	name                       string
+	distance                   int
+	// ambiguous tells that other labels are as close as this one.
+	ambiguous bool
+}
+
+func (s suggestion) String() string {
+	return fmt.Sprintf("did you mean %s=%s (%s)?", s.key, s.value, s.name)
+}
+
+func labelledPodCandidates(podCandidates []model.PodCandidate) []labelled {
+	var out []labelled
+	for _, pc := range podCandidates {
+		out = append(out, labelled{kind: "pods", name: pc.OwnerName(), labels: pc.Labels, matches: pc.Matches})
+	}
+	return out
+}
+
+func labelledNamespaces(namespaces []v1.Namespace) []labelled {
+	var out []labelled
+	for _, ns := range namespaces {
+		nsLabels := labels.Set(ns.Labels)
+		out = append(out, labelled{
+			kind:   "namespaces",
+			name:   fmt.Sprintf("namespace/%s", ns.Name),
+			labels: ns.Labels,
+			matches: func(selector labels.Selector) bool {
+				return selector.Matches(nsLabels)
+			},
+		})
+	}
+	return out
+}
+
+// suggest finds a label that is a typo away from a label of the selector, on an object that satisfies all the other
+// requirements of the selector. Only equality requirements, like these from matchLabels, are corrected.
+func suggest(selector labels.Selector, in []labelled) (suggestion, bool) {
+	requirements, selectable := selector.Requirements()
+	if !selectable {
+		return suggestion{}, false
+	}
+	sorted := make([]labelled, len(in))
+	copy(sorted, in)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].name < sorted[j].name
+	})
+
+	var candidates []suggestion
+	for _, obj := range sorted {
+		failing := -1
+		for idx, r := range requirements {
+			if obj.matches(labels.NewSelector().Add(r)) {
+				continue
+			}
+			if failing >= 0 {
+				failing = -1
+				break
+			}
+			failing = idx
+		}
+		if failing < 0 || !isEquality(requirements[failing]) {
+			continue
+		}
+		for key, value := range obj.labels {
+			for _, want := range requirements[failing].Values().List() {
+				keyDistance, keyClose := typoDistance(requirements[failing].Key(), key)
+				valueDistance, valueClose := typoDistance(want, value)
+				distance := keyDistance + valueDistance
+				if !keyClose || !valueClose || distance == 0 || distance > maxSuggestionDistance {
+					continue
+				}
+				candidates = append(candidates, suggestion{
+					replacedKey:   requirements[failing].Key(),
+					replacedValue: want,
+					key:           key,
+					value:         value,
+					name:          obj.name,
+					distance:      distance,
+				})
+			}
+		}
+	}
+	if len(candidates) == 0 {
+		return suggestion{}, false
+	}
+
+	best := candidates[0]
+	for _, c := range candidates[1:] {
+		if isBetter(c, best) {
+			best = c
+		}
+	}
+	for _, c := range candidates {
+		if c.distance == best.distance && !best.sameFix(c) {
+			best.ambiguous = true
+		}
+	}
+	return best, true
+}
+
+// sameFix reports whether both suggestions replace the same label with the same existing label.
+func (s suggestion) sameFix(other suggestion) bool {
+	return s.replacedKey == other.replacedKey && s.replacedValue == other.replacedValue && s.key == other.key && s.value == other.value
+}
+
+func isEquality(r labels.Requirement) bool {
+	switch r.Operator() {
+	case selection.Equals, selection.DoubleEquals, selection.In:
+		return true
+	default:
+		return false
+	}
+}
+
+// isBetter prefers closer labels, and breaks ties by the label, so that suggestions do not depend on map ordering.
+func isBetter(s, than suggestion) bool {
+	if s.distance != than.distance {
+		return s.distance < than.distance
+	}
+	if s.name != than.name {
+		return false
+	}
+	if s.key != than.key {
+		return s.key < than.key
+	}
+	return s.value < than.value
+}
+
+// typoDistance returns the edit distance between the wanted and the existing string, and reports whether the existing
+// string is close enough to be a typo fix. Short strings, like "a" and "b", are never close.
+func typoDistance(wanted, existing string) (int, bool) {
+	distance := editDistance(wanted, existing)
+	return distance, distance*3 <= len(wanted)
+}
+
+// editDistance returns the Levenshtein distance between a and b.
+func editDistance(a, b string) int {
+	previous := make([]int, len(b)+1)
+	current := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current[j] = minInt(previous[j]+1, minInt(current[j-1]+1, previous[j-1]+cost))
+		}
+		previous, current = current, previous
+	}
+	return previous[len(b)]
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
@@ -5,3 +5,9 @@ import "github.com/aszecowka/netpolvalidator/internal/model"
 type Validator interface {
 	Validate(state model.ClusterState) ([]model.Violation, error)
 }
+
+// Dependent is implemented by validators whose results depend only on some kinds of resources, e.g. model.KindNetworkPolicy.
+// In watch mode, validators that do not implement it are re-run after every change in the cluster.
+type Dependent interface {
+	DependsOn() []string
+}
@@ -0,0 +1,74 @@
+package scope
+
+import (
+	"fmt"
+	"path"
+
+	v1 "k8s.io/api/core/v1"
+	netv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	"github.com/aszecowka/netpolvalidator/internal/model"
+)
+
+// Scope selects namespaces whose network policies are validated.
+// Zero value selects all namespaces.
+type Scope struct {
+	namespaces map[string]struct{}
+	selector   labels.Selector
+	exclude    []string
+}
+
+// New creates a Scope. Empty namespaces and selector select all namespaces. Namespaces with names matching
+// any of the exclude globs, e.g. kube-*, are not selected even if they are listed in namespaces.
+func New(namespaces []string, selector string, exclude []string) (Scope, error) {
+	out := Scope{exclude: exclude}
+	if len(namespaces) > 0 {
+		out.namespaces = make(map[string]struct{}, len(namespaces))
+		for _, ns := range namespaces {
+			out.namespaces[ns] = struct{}{}
+		}
+	}
+	if selector != "" {
+		parsed, err := labels.Parse(selector)
+		if err != nil {
+			return Scope{}, fmt.Errorf("while parsing namespace selector %q: %w", selector, err)
+		}
+		out.selector = parsed
+	}
+	for _, pattern := range exclude {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return Scope{}, fmt.Errorf("while parsing exclude pattern %q: %w", pattern, err)
+		}
+	}
+	return out, nil
+}
+
+// Includes reports whether network policies from the namespace are validated.
+func (s Scope) Includes(ns v1.Namespace) bool {
+	if _, found := s.namespaces[ns.Name]; s.namespaces != nil && !found {
+		return false
+	}
+	if s.selector != nil && !s.selector.Matches(labels.Set(ns.Labels)) {
+		return false
+	}
+	for _, pattern := range s.exclude {
+		if matched, _ := path.Match(pattern, ns.Name); matched {
+			return false
+		}
+	}
+	return true
+}
+
+// Apply returns a copy of the cluster state with network policies from namespaces in scope only.
+// All namespaces and pod candidates are kept, so that peer selectors of the remaining policies are resolved correctly.
+func (s Scope) Apply(clusterState model.ClusterState) model.ClusterState {
+	policies := make(map[string][]netv1.NetworkPolicy)
+	for _, ns := range clusterState.Namespaces {
+		if nps, found := clusterState.NetworkPolicies[ns.Name]; found && s.Includes(ns) {
+			policies[ns.Name] = nps
+		}
+	}
+	clusterState.NetworkPolicies = policies
+	return clusterState
+}
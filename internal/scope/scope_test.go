@@ -0,0 +1,114 @@
+package scope_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	netv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/aszecowka/netpolvalidator/internal/model"
+	"github.com/aszecowka/netpolvalidator/internal/scope"
+)
+
+func TestScopeApply(t *testing.T) {
+	testCases := map[string]struct {
+		givenNamespaces []string
+		givenSelector   string
+		givenExclude    []string
+		expected        []string
+	}{
+		"all namespaces": {
+			expected: []string{"kube-system", "orders", "payments"},
+		},
+		"listed namespaces": {
+			givenNamespaces: []string{"orders", "kube-system"},
+			expected:        []string{"kube-system", "orders"},
+		},
+		"namespace selector": {
+			givenSelector: "team=shop",
+			expected:      []string{"orders", "payments"},
+		},
+		"exclude globs": {
+			givenExclude: []string{"kube-*"},
+			expected:     []string{"orders", "payments"},
+		},
+		"exclude wins over listed namespaces": {
+			givenNamespaces: []string{"orders", "kube-system"},
+			givenExclude:    []string{"kube-*"},
+			expected:        []string{"orders"},
+		},
+		"all criteria": {
+			givenNamespaces: []string{"orders", "payments", "kube-system"},
+			givenSelector:   "team in (shop)",
+			givenExclude:    []string{"pay*"},
+			expected:        []string{"orders"},
+		},
+	}
+
+	for tn, tc := range testCases {
+		t.Run(tn, func(t *testing.T) {
+			// GIVEN
+			givenState := fixState()
+			sut, err := scope.New(tc.givenNamespaces, tc.givenSelector, tc.givenExclude)
+			require.NoError(t, err)
+			// WHEN
+			actual := sut.Apply(givenState)
+			// THEN
+			var actualNamespaces []string
+			for _, ns := range actual.Namespaces {
+				if _, found := actual.NetworkPolicies[ns.Name]; found {
+					actualNamespaces = append(actualNamespaces, ns.Name)
+				}
+			}
+			assert.Equal(t, tc.expected, actualNamespaces)
+			assert.Equal(t, givenState.Namespaces, actual.Namespaces)
+			assert.Equal(t, givenState.PodCandidates, actual.PodCandidates)
+		})
+	}
+}
+
+func TestNewScope(t *testing.T) {
+	t.Run("invalid selector", func(t *testing.T) {
+		// WHEN
+		_, err := scope.New(nil, "team in shop", nil)
+		// THEN
+		require.Error(t, err)
+	})
+
+	t.Run("invalid exclude pattern", func(t *testing.T) {
+		// WHEN
+		_, err := scope.New(nil, "", []string{"kube-["})
+		// THEN
+		require.EqualError(t, err, `while parsing exclude pattern "kube-[": syntax error in pattern`)
+	})
+}
+
+func fixState() model.ClusterState {
+	return model.ClusterState{
+		Namespaces: []v1.Namespace{
+			fixNamespace("kube-system", nil),
+			fixNamespace("orders", map[string]string{"team": "shop"}),
+			fixNamespace("payments", map[string]string{"team": "shop"}),
+		},
+		NetworkPolicies: map[string][]netv1.NetworkPolicy{
+			"kube-system": {fixNetPol("kube-system")},
+			"orders":      {fixNetPol("orders")},
+			"payments":    {fixNetPol("payments")},
+		},
+		PodCandidates: map[string][]model.PodCandidate{
+			"kube-system": {{Owner: model.Owner{Kind: "deployment", Namespace: "kube-system", Name: "coredns"}, Labels: map[string]string{"k8s-app": "kube-dns"}}},
+			"orders":      {{Owner: model.Owner{Kind: "deployment", Namespace: "orders", Name: "a"}, Labels: map[string]string{"app": "a"}}},
+		},
+	}
+}
+
+func fixNamespace(name string, labels map[string]string) v1.Namespace {
+	return v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: name, Labels: labels}}
+}
+
+func fixNetPol(namespace string) netv1.NetworkPolicy {
+	return netv1.NetworkPolicy{ObjectMeta: metav1.ObjectMeta{Name: "default-deny", Namespace: namespace}}
+}
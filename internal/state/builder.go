@@ -3,9 +3,15 @@ package state
 import (
 	"context"
 	"fmt"
+	"sort"
 
 	v1 "k8s.io/api/core/v1"
 	netv1 "k8s.io/api/networking/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/util/workqueue"
 
 	"github.com/aszecowka/netpolvalidator/internal/model"
 )
@@ -25,11 +31,60 @@ type PodCandidatesProvider interface {
 	GetPodCandidatesForNamespace(ctx context.Context, ns string) ([]model.PodCandidate, error)
 }
 
-func NewBuilder(nsProvider NamespacesProvider, netPolProvider NetworkPoliciesProvider, podCandidatesProviders map[string]PodCandidatesProvider) *Builder {
+// AllNamespacesNetworkPoliciesProvider is implemented by providers able to list network policies with a single cluster-scoped call.
+//
+//go:generate ${GOBIN}/mockery -name=AllNamespacesNetworkPoliciesProvider -output=automcock -outpkg=automock -case=underscore
+type AllNamespacesNetworkPoliciesProvider interface {
+	GetNetworkPoliciesForAllNamespaces(ctx context.Context) (map[string][]netv1.NetworkPolicy, error)
+}
+
+// AllNamespacesPodCandidatesProvider is implemented by providers able to list pod candidates with a single cluster-scoped call.
+//
+//go:generate ${GOBIN}/mockery -name=AllNamespacesPodCandidatesProvider -output=automcock -outpkg=automock -case=underscore
+type AllNamespacesPodCandidatesProvider interface {
+	GetPodCandidatesForAllNamespaces(ctx context.Context) (map[string][]model.PodCandidate, error)
+}
+
+// ServicesProvider provides services of a namespace. Services are fetched only by builders created with WithServices.
+//
+//go:generate ${GOBIN}/mockery -name=ServicesProvider -output=automcock -outpkg=automock -case=underscore
+type ServicesProvider interface {
+	GetServicesForNamespace(ctx context.Context, ns string) ([]v1.Service, error)
+}
+
+// AllNamespacesServicesProvider is implemented by providers able to list services with a single cluster-scoped call.
+//
+//go:generate ${GOBIN}/mockery -name=AllNamespacesServicesProvider -output=automcock -outpkg=automock -case=underscore
+type AllNamespacesServicesProvider interface {
+	GetServicesForAllNamespaces(ctx context.Context) (map[string][]v1.Service, error)
+}
+
+type Options struct {
+	// Workers limits how many requests are sent to the API server at the same time.
+	Workers int
+	// ClusterWideLists makes the Builder list every resource type with one cluster-scoped call.
+	// Resource types that the caller is not allowed to list cluster-wide are fetched namespace by namespace.
+	ClusterWideLists bool
+	// PartialResults makes the Builder record resource types it is not allowed to list or the API server
+	// does not serve as coverage gaps, instead of failing the whole build.
+	PartialResults bool
+	// Retry controls how calls failed with transient errors, like throttling or server errors, are retried.
+	// Zero value means no retries.
+	Retry wait.Backoff
+}
+
+func NewBuilder(nsProvider NamespacesProvider, netPolProvider NetworkPoliciesProvider, podCandidatesProviders map[string]PodCandidatesProvider, opts Options) *Builder {
+	if opts.Workers < 1 {
+		opts.Workers = 1
+	}
+	if opts.Retry.Steps < 1 {
+		opts.Retry.Steps = 1
+	}
 	return &Builder{
 		nsProvider:             nsProvider,
 		netPolProvider:         netPolProvider,
 		podCandidatesProviders: podCandidatesProviders,
+		opts:                   opts,
 	}
 }
 
@@ -37,38 +92,256 @@ type Builder struct {
 	nsProvider             NamespacesProvider
 	netPolProvider         NetworkPoliciesProvider
 	podCandidatesProviders map[string]PodCandidatesProvider
+	servicesProvider       ServicesProvider
+	opts                   Options
+}
+
+// WithServices makes the Builder fetch services too, with the same retries, fallbacks and coverage gaps
+// as other resources.
+func (b *Builder) WithServices(provider ServicesProvider) *Builder {
+	b.servicesProvider = provider
+	return b
+}
+
+// fetchedState holds results of all fetches. Network policies are the source with index 0,
+// pod candidates providers follow in the order of providerNames.
+type fetchedState struct {
+	namespaces    []v1.Namespace
+	providerNames []string
+	policies      [][]netv1.NetworkPolicy
+	podCandidates [][]model.PodCandidate
+}
+
+func (fs *fetchedState) sourcesPerNs() int {
+	return 1 + len(fs.providerNames)
+}
+
+func (fs *fetchedState) sourceName(source int) string {
+	if source == 0 {
+		return model.KindNetworkPolicy
+	}
+	return fs.providerNames[source-1]
+}
+
+func (fs *fetchedState) podCandidatesIdx(nsIdx, providerIdx int) int {
+	return nsIdx*len(fs.providerNames) + providerIdx
 }
 
 func (b *Builder) Build(ctx context.Context) (*model.ClusterState, error) {
 	out := &model.ClusterState{}
-	namespaces, err := b.nsProvider.GetAllNamespaces(ctx)
+	var namespaces []v1.Namespace
+	err := b.withRetry(ctx, func() (err error) {
+		namespaces, err = b.nsProvider.GetAllNamespaces(ctx)
+		return err
+	})
 	if err != nil {
 		return nil, fmt.Errorf("while getting all namespaces: %w", err)
 	}
 	out.Namespaces = namespaces
-	out.NetworkPolicies = make(map[string][]netv1.NetworkPolicy)
 
-	for _, ns := range namespaces {
-		policies, err := b.netPolProvider.GetNetworkPoliciesForNamespace(ctx, ns.Name)
-		if err != nil {
-			return nil, fmt.Errorf("while getting network policies for namespace: %s: %w", ns.Name, err)
-		}
-		out.NetworkPolicies[ns.Name] = policies
+	providerNames := b.sortedProviderNames()
+	fetched := &fetchedState{
+		namespaces:    namespaces,
+		providerNames: providerNames,
+		policies:      make([][]netv1.NetworkPolicy, len(namespaces)),
+		podCandidates: make([][]model.PodCandidate, len(namespaces)*len(providerNames)),
 	}
 
-	out.PodCandidates = make(map[string][]model.PodCandidate)
-	for podCandidateStrategyName, strategy := range b.podCandidatesProviders {
-		for _, ns := range namespaces {
-			podCandidates, err := strategy.GetPodCandidatesForNamespace(ctx, ns.Name)
-			if err != nil {
-				return nil, fmt.Errorf("while getting pod candidates for namespace: %s, strategy: %s: %w", ns.Name, podCandidateStrategyName, err)
+	perNamespace := make([]bool, fetched.sourcesPerNs())
+	for source := range perNamespace {
+		perNamespace[source] = true
+	}
+	if b.opts.ClusterWideLists {
+		errs := make([]error, len(perNamespace))
+		workqueue.ParallelizeUntil(ctx, b.opts.Workers, len(perNamespace), func(source int) {
+			perNamespace[source], errs[source] = b.fetchFromAllNamespaces(ctx, fetched, source)
+		})
+		for source, err := range errs {
+			if b.isCoverageGap(err) {
+				out.CoverageGaps = append(out.CoverageGaps, model.NewCoverageGap(metav1.NamespaceAll, fetched.sourceName(source), err))
+				perNamespace[source], errs[source] = false, nil
 			}
-			if out.PodCandidates[ns.Name] == nil {
-				out.PodCandidates[ns.Name] = podCandidates
-			} else {
-				out.PodCandidates[ns.Name] = append(out.PodCandidates[ns.Name], podCandidates...)
+		}
+		if err := utilerrors.NewAggregate(errs); err != nil {
+			return nil, err
+		}
+	}
+
+	var tasks []int
+	for nsIdx := range namespaces {
+		for source := range perNamespace {
+			if perNamespace[source] {
+				tasks = append(tasks, nsIdx*fetched.sourcesPerNs()+source)
 			}
 		}
 	}
+	errs := make([]error, len(tasks))
+	workqueue.ParallelizeUntil(ctx, b.opts.Workers, len(tasks), func(piece int) {
+		nsIdx, source := tasks[piece]/fetched.sourcesPerNs(), tasks[piece]%fetched.sourcesPerNs()
+		errs[piece] = b.fetchFromNamespace(ctx, fetched, nsIdx, source)
+	})
+	for piece, err := range errs {
+		if b.isCoverageGap(err) {
+			nsIdx, source := tasks[piece]/fetched.sourcesPerNs(), tasks[piece]%fetched.sourcesPerNs()
+			out.CoverageGaps = append(out.CoverageGaps, model.NewCoverageGap(namespaces[nsIdx].Name, fetched.sourceName(source), err))
+			errs[piece] = nil
+		}
+	}
+	if err := utilerrors.NewAggregate(errs); err != nil {
+		return nil, err
+	}
+	if b.servicesProvider != nil {
+		if err := b.fetchServices(ctx, out); err != nil {
+			return nil, err
+		}
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, fmt.Errorf("while building cluster state: %w", err)
+	}
+
+	out.NetworkPolicies = make(map[string][]netv1.NetworkPolicy)
+	out.PodCandidates = make(map[string][]model.PodCandidate)
+	for nsIdx, ns := range namespaces {
+		out.NetworkPolicies[ns.Name] = fetched.policies[nsIdx]
+		var candidatesInNs []model.PodCandidate
+		for providerIdx := range providerNames {
+			candidatesInNs = append(candidatesInNs, fetched.podCandidates[fetched.podCandidatesIdx(nsIdx, providerIdx)]...)
+		}
+		out.PodCandidates[ns.Name] = foldControlledPods(candidatesInNs)
+	}
 	return out, nil
 }
+
+// foldControlledPods removes pods controlled by other pod candidates from the namespace and counts them as live replicas
+// of those candidates. Pods whose controllers are not among the candidates, e.g. because they could not be listed, are kept.
+func foldControlledPods(in []model.PodCandidate) []model.PodCandidate {
+	if len(in) == 0 {
+		return in
+	}
+	out := make([]model.PodCandidate, 0, len(in))
+	idxByOwner := make(map[string]int)
+	var controlled []model.PodCandidate
+	for _, pc := range in {
+		if len(pc.ControlledBy) > 0 {
+			controlled = append(controlled, pc)
+			continue
+		}
+		idxByOwner[pc.OwnerName()] = len(out)
+		out = append(out, pc)
+	}
+
+	for _, pc := range controlled {
+		folded := false
+		for _, ownerName := range pc.ControlledBy {
+			if idx, found := idxByOwner[ownerName]; found {
+				out[idx].LiveReplicas += pc.LiveReplicas
+				folded = true
+				break
+			}
+		}
+		if !folded {
+			out = append(out, pc)
+		}
+	}
+	return out
+}
+
+// fetchFromAllNamespaces fetches given source with a cluster-scoped call. It reports whether the source
+// has to be fetched namespace by namespace, because its provider does not support cluster-scoped calls
+// or the caller is not allowed to make them.
+func (b *Builder) fetchFromAllNamespaces(ctx context.Context, fetched *fetchedState, source int) (bool, error) {
+	if source == 0 {
+		provider, ok := b.netPolProvider.(AllNamespacesNetworkPoliciesProvider)
+		if !ok {
+			return true, nil
+		}
+		var policies map[string][]netv1.NetworkPolicy
+		err := b.withRetry(ctx, func() (err error) {
+			policies, err = provider.GetNetworkPoliciesForAllNamespaces(ctx)
+			return err
+		})
+		if apierrors.IsForbidden(err) {
+			return true, nil
+		}
+		if err != nil {
+			return false, fmt.Errorf("while getting network policies for all namespaces: %w", err)
+		}
+		for nsIdx, ns := range fetched.namespaces {
+			fetched.policies[nsIdx] = policies[ns.Name]
+		}
+		return false, nil
+	}
+
+	providerIdx := source - 1
+	providerName := fetched.providerNames[providerIdx]
+	provider, ok := b.podCandidatesProviders[providerName].(AllNamespacesPodCandidatesProvider)
+	if !ok {
+		return true, nil
+	}
+	var podCandidates map[string][]model.PodCandidate
+	err := b.withRetry(ctx, func() (err error) {
+		podCandidates, err = provider.GetPodCandidatesForAllNamespaces(ctx)
+		return err
+	})
+	if apierrors.IsForbidden(err) {
+		return true, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("while getting pod candidates for all namespaces, strategy: %s: %w", providerName, err)
+	}
+	for nsIdx, ns := range fetched.namespaces {
+		fetched.podCandidates[fetched.podCandidatesIdx(nsIdx, providerIdx)] = podCandidates[ns.Name]
+	}
+	return false, nil
+}
+
+func (b *Builder) fetchFromNamespace(ctx context.Context, fetched *fetchedState, nsIdx, source int) error {
+	ns := fetched.namespaces[nsIdx].Name
+	if source == 0 {
+		var policies []netv1.NetworkPolicy
+		err := b.withRetry(ctx, func() (err error) {
+			policies, err = b.netPolProvider.GetNetworkPoliciesForNamespace(ctx, ns)
+			return err
+		})
+		if err != nil {
+			return fmt.Errorf("while getting network policies for namespace: %s: %w", ns, err)
+		}
+		fetched.policies[nsIdx] = policies
+		return nil
+	}
+
+	providerIdx := source - 1
+	providerName := fetched.providerNames[providerIdx]
+	var podCandidates []model.PodCandidate
+	err := b.withRetry(ctx, func() (err error) {
+		podCandidates, err = b.podCandidatesProviders[providerName].GetPodCandidatesForNamespace(ctx, ns)
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("while getting pod candidates for namespace: %s, strategy: %s: %w", ns, providerName, err)
+	}
+	fetched.podCandidates[fetched.podCandidatesIdx(nsIdx, providerIdx)] = podCandidates
+	return nil
+}
+
+// isCoverageGap reports whether err means that a resource type cannot be inspected at all
+// and should be recorded as a coverage gap rather than fail the build.
+func (b *Builder) isCoverageGap(err error) bool {
+	if err == nil || !b.opts.PartialResults {
+		return false
+	}
+	return apierrors.IsForbidden(err) ||
+		apierrors.IsUnauthorized(err) ||
+		apierrors.IsNotFound(err) ||
+		apierrors.IsMethodNotSupported(err) ||
+		apierrors.IsServiceUnavailable(err)
+}
+
+func (b *Builder) sortedProviderNames() []string {
+	names := make([]string, 0, len(b.podCandidatesProviders))
+	for name := range b.podCandidatesProviders {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
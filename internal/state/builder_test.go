@@ -3,14 +3,21 @@ package state_test
 import (
 	"context"
 	"errors"
+	"fmt"
+	"sync/atomic"
+	"syscall"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 	v1 "k8s.io/api/core/v1"
 	v13 "k8s.io/api/networking/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	v12 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/wait"
 
 	"github.com/aszecowka/netpolvalidator/internal/model"
 	"github.com/aszecowka/netpolvalidator/internal/state"
@@ -44,7 +51,7 @@ func TestBuildClusterState(t *testing.T) {
 			"cronjob": mockCronjobProvider,
 		}
 
-		sut := state.NewBuilder(mockNsProvider, mockNetPolProvider, podCandidatesProviders)
+		sut := state.NewBuilder(mockNsProvider, mockNetPolProvider, podCandidatesProviders, state.Options{Workers: 2})
 		// WHEN
 		actual, err := sut.Build(context.Background())
 		// THEN
@@ -62,13 +69,53 @@ func TestBuildClusterState(t *testing.T) {
 		assert.Contains(t, actual.PodCandidates["b"], fixPodCandidate("deploy-b"))
 	})
 
+	t.Run("pods are folded into their controllers", func(t *testing.T) {
+		// GIVEN
+		mockNsProvider := &automock.NamespacesProvider{}
+		defer mockNsProvider.AssertExpectations(t)
+		mockNsProvider.On("GetAllNamespaces", mock.Anything).Return([]v1.Namespace{fixNsWithName("a")}, nil).Once()
+
+		mockNetPolProvider := &automock.NetworkPoliciesProvider{}
+		defer mockNetPolProvider.AssertExpectations(t)
+		mockNetPolProvider.On("GetNetworkPoliciesForNamespace", mock.Anything, "a").Return(nil, nil).Once()
+
+		mockDeploymentsProvider := &automock.PodCandidatesProvider{}
+		defer mockDeploymentsProvider.AssertExpectations(t)
+		mockDeploymentsProvider.On("GetPodCandidatesForNamespace", mock.Anything, "a").Return([]model.PodCandidate{fixOwnedPodCandidate("deployment", "a", "web")}, nil).Once()
+
+		podOfDeployment := fixOwnedPodCandidate("pod", "a", "web-5d4f8b7c9-x2x7z")
+		podOfDeployment.ControlledBy = []string{"replicaset/a/web-5d4f8b7c9", "deployment/a/web"}
+		podOfDeployment.LiveReplicas = 1
+		podOfUnknownController := fixOwnedPodCandidate("pod", "a", "worker-0")
+		podOfUnknownController.ControlledBy = []string{"sparkapplication/a/worker"}
+		podOfUnknownController.LiveReplicas = 1
+		standalonePod := fixOwnedPodCandidate("pod", "a", "debug")
+		standalonePod.LiveReplicas = 1
+		mockPodsProvider := &automock.PodCandidatesProvider{}
+		defer mockPodsProvider.AssertExpectations(t)
+		mockPodsProvider.On("GetPodCandidatesForNamespace", mock.Anything, "a").Return([]model.PodCandidate{podOfDeployment, podOfDeployment, podOfUnknownController, standalonePod}, nil).Once()
+
+		podCandidatesProviders := map[string]state.PodCandidatesProvider{
+			"deploy": mockDeploymentsProvider,
+			"pod":    mockPodsProvider,
+		}
+		sut := state.NewBuilder(mockNsProvider, mockNetPolProvider, podCandidatesProviders, state.Options{Workers: 1})
+		// WHEN
+		actual, err := sut.Build(context.Background())
+		// THEN
+		require.NoError(t, err)
+		expectedDeployment := fixOwnedPodCandidate("deployment", "a", "web")
+		expectedDeployment.LiveReplicas = 2
+		assert.Equal(t, []model.PodCandidate{expectedDeployment, standalonePod, podOfUnknownController}, actual.PodCandidates["a"])
+	})
+
 	t.Run("got error on getting namespaces", func(t *testing.T) {
 		// GIVEN
 		mockNsProvider := &automock.NamespacesProvider{}
 		defer mockNsProvider.AssertExpectations(t)
 
 		mockNsProvider.On("GetAllNamespaces", mock.Anything).Return(nil, errors.New("some error")).Once()
-		sut := state.NewBuilder(mockNsProvider, nil, nil)
+		sut := state.NewBuilder(mockNsProvider, nil, nil, state.Options{Workers: 1})
 		// WHEN
 		_, err := sut.Build(context.Background())
 		// THEN
@@ -85,7 +132,8 @@ func TestBuildClusterState(t *testing.T) {
 		mockNetPolProvider := &automock.NetworkPoliciesProvider{}
 		defer mockNetPolProvider.AssertExpectations(t)
 		mockNetPolProvider.On("GetNetworkPoliciesForNamespace", mock.Anything, "a").Return(nil, errors.New("some error")).Once()
-		sut := state.NewBuilder(mockNsProvider, mockNetPolProvider, nil)
+		mockNetPolProvider.On("GetNetworkPoliciesForNamespace", mock.Anything, "b").Return(nil, nil).Once()
+		sut := state.NewBuilder(mockNsProvider, mockNetPolProvider, nil, state.Options{Workers: 1})
 		// WHEN
 		_, err := sut.Build(context.Background())
 		// THEN
@@ -108,11 +156,454 @@ func TestBuildClusterState(t *testing.T) {
 		providers := map[string]state.PodCandidatesProvider{
 			"deploy": mockDeploymentsProvider,
 		}
-		sut := state.NewBuilder(mockNsProvider, mockNetPolProvider, providers)
+		sut := state.NewBuilder(mockNsProvider, mockNetPolProvider, providers, state.Options{Workers: 1})
 		// WHEN
 		_, err := sut.Build(context.Background())
 		require.EqualError(t, err, "while getting pod candidates for namespace: a, strategy: deploy: some error")
 	})
+
+	t.Run("aggregates errors from all namespaces", func(t *testing.T) {
+		// GIVEN
+		mockNsProvider := &automock.NamespacesProvider{}
+		defer mockNsProvider.AssertExpectations(t)
+		mockNsProvider.On("GetAllNamespaces", mock.Anything).Return([]v1.Namespace{fixNsWithName("a"), fixNsWithName("b")}, nil).Once()
+
+		mockNetPolProvider := &automock.NetworkPoliciesProvider{}
+		defer mockNetPolProvider.AssertExpectations(t)
+		mockNetPolProvider.On("GetNetworkPoliciesForNamespace", mock.Anything, "a").Return(nil, errors.New("some error")).Once()
+		mockNetPolProvider.On("GetNetworkPoliciesForNamespace", mock.Anything, "b").Return(nil, nil).Once()
+		mockDeploymentsProvider := &automock.PodCandidatesProvider{}
+		defer mockDeploymentsProvider.AssertExpectations(t)
+		mockDeploymentsProvider.On("GetPodCandidatesForNamespace", mock.Anything, "a").Return(nil, nil).Once()
+		mockDeploymentsProvider.On("GetPodCandidatesForNamespace", mock.Anything, "b").Return(nil, errors.New("other error")).Once()
+		providers := map[string]state.PodCandidatesProvider{
+			"deploy": mockDeploymentsProvider,
+		}
+		sut := state.NewBuilder(mockNsProvider, mockNetPolProvider, providers, state.Options{Workers: 3})
+		// WHEN
+		_, err := sut.Build(context.Background())
+		// THEN
+		require.EqualError(t, err, "[while getting network policies for namespace: a: some error, while getting pod candidates for namespace: b, strategy: deploy: other error]")
+	})
+
+	t.Run("keeps pod candidates ordered by provider name", func(t *testing.T) {
+		// GIVEN
+		mockNsProvider := &automock.NamespacesProvider{}
+		defer mockNsProvider.AssertExpectations(t)
+		mockNsProvider.On("GetAllNamespaces", mock.Anything).Return([]v1.Namespace{fixNsWithName("a")}, nil).Once()
+
+		mockNetPolProvider := &automock.NetworkPoliciesProvider{}
+		defer mockNetPolProvider.AssertExpectations(t)
+		mockNetPolProvider.On("GetNetworkPoliciesForNamespace", mock.Anything, "a").Return(nil, nil).Once()
+
+		providers := make(map[string]state.PodCandidatesProvider)
+		for _, name := range []string{"statefulset", "cronjob", "pod", "deploy"} {
+			mockProvider := &automock.PodCandidatesProvider{}
+			defer mockProvider.AssertExpectations(t)
+			mockProvider.On("GetPodCandidatesForNamespace", mock.Anything, "a").Return([]model.PodCandidate{fixPodCandidate(name)}, nil).Once()
+			providers[name] = mockProvider
+		}
+		sut := state.NewBuilder(mockNsProvider, mockNetPolProvider, providers, state.Options{Workers: 4})
+		// WHEN
+		actual, err := sut.Build(context.Background())
+		// THEN
+		require.NoError(t, err)
+		assert.Equal(t, []model.PodCandidate{
+			fixPodCandidate("cronjob"),
+			fixPodCandidate("deploy"),
+			fixPodCandidate("pod"),
+			fixPodCandidate("statefulset"),
+		}, actual.PodCandidates["a"])
+	})
+
+	t.Run("does not exceed the number of workers", func(t *testing.T) {
+		// GIVEN
+		givenNamespaces := []v1.Namespace{fixNsWithName("a"), fixNsWithName("b"), fixNsWithName("c"), fixNsWithName("d")}
+		mockNsProvider := &automock.NamespacesProvider{}
+		defer mockNsProvider.AssertExpectations(t)
+		mockNsProvider.On("GetAllNamespaces", mock.Anything).Return(givenNamespaces, nil).Once()
+
+		var inFlight, maxInFlight int32
+		trackConcurrency := func(mock.Arguments) {
+			current := atomic.AddInt32(&inFlight, 1)
+			for {
+				observed := atomic.LoadInt32(&maxInFlight)
+				if current <= observed || atomic.CompareAndSwapInt32(&maxInFlight, observed, current) {
+					break
+				}
+			}
+			time.Sleep(10 * time.Millisecond)
+			atomic.AddInt32(&inFlight, -1)
+		}
+
+		mockNetPolProvider := &automock.NetworkPoliciesProvider{}
+		defer mockNetPolProvider.AssertExpectations(t)
+		mockNetPolProvider.On("GetNetworkPoliciesForNamespace", mock.Anything, mock.Anything).Run(trackConcurrency).Return(nil, nil).Times(len(givenNamespaces))
+		mockDeploymentsProvider := &automock.PodCandidatesProvider{}
+		defer mockDeploymentsProvider.AssertExpectations(t)
+		mockDeploymentsProvider.On("GetPodCandidatesForNamespace", mock.Anything, mock.Anything).Run(trackConcurrency).Return(nil, nil).Times(len(givenNamespaces))
+		providers := map[string]state.PodCandidatesProvider{
+			"deploy": mockDeploymentsProvider,
+		}
+		sut := state.NewBuilder(mockNsProvider, mockNetPolProvider, providers, state.Options{Workers: 2})
+		// WHEN
+		_, err := sut.Build(context.Background())
+		// THEN
+		require.NoError(t, err)
+		assert.LessOrEqual(t, atomic.LoadInt32(&maxInFlight), int32(2))
+	})
+}
+
+func TestBuildClusterStateWithClusterWideLists(t *testing.T) {
+	t.Run("uses cluster-scoped calls", func(t *testing.T) {
+		// GIVEN
+		mockNsProvider := &automock.NamespacesProvider{}
+		defer mockNsProvider.AssertExpectations(t)
+		mockNsProvider.On("GetAllNamespaces", mock.Anything).Return([]v1.Namespace{fixNsWithName("a"), fixNsWithName("b")}, nil).Once()
+
+		mockNetPolProvider := newClusterWideNetPolProvider()
+		defer mockNetPolProvider.NetworkPoliciesProvider.AssertExpectations(t)
+		defer mockNetPolProvider.AllNamespacesNetworkPoliciesProvider.AssertExpectations(t)
+		mockNetPolProvider.AllNamespacesNetworkPoliciesProvider.On("GetNetworkPoliciesForAllNamespaces", mock.Anything).Return(map[string][]v13.NetworkPolicy{
+			"a": {fixFixNetPol("a", "ingress-deny-all")},
+		}, nil).Once()
+
+		mockDeploymentsProvider := newClusterWidePodCandidatesProvider()
+		defer mockDeploymentsProvider.PodCandidatesProvider.AssertExpectations(t)
+		defer mockDeploymentsProvider.AllNamespacesPodCandidatesProvider.AssertExpectations(t)
+		mockDeploymentsProvider.AllNamespacesPodCandidatesProvider.On("GetPodCandidatesForAllNamespaces", mock.Anything).Return(map[string][]model.PodCandidate{
+			"a": {fixPodCandidate("deploy-a")},
+			"b": {fixPodCandidate("deploy-b")},
+		}, nil).Once()
+
+		providers := map[string]state.PodCandidatesProvider{
+			"deploy": mockDeploymentsProvider,
+		}
+		sut := state.NewBuilder(mockNsProvider, mockNetPolProvider, providers, state.Options{Workers: 2, ClusterWideLists: true})
+		// WHEN
+		actual, err := sut.Build(context.Background())
+		// THEN
+		require.NoError(t, err)
+		assert.Len(t, actual.NetworkPolicies, 2)
+		assert.Equal(t, []v13.NetworkPolicy{fixFixNetPol("a", "ingress-deny-all")}, actual.NetworkPolicies["a"])
+		assert.Equal(t, []v13.NetworkPolicy(nil), actual.NetworkPolicies["b"])
+		assert.Equal(t, []model.PodCandidate{fixPodCandidate("deploy-a")}, actual.PodCandidates["a"])
+		assert.Equal(t, []model.PodCandidate{fixPodCandidate("deploy-b")}, actual.PodCandidates["b"])
+	})
+
+	t.Run("falls back to namespaced calls when cluster-scoped call is forbidden or not supported", func(t *testing.T) {
+		// GIVEN
+		mockNsProvider := &automock.NamespacesProvider{}
+		defer mockNsProvider.AssertExpectations(t)
+		mockNsProvider.On("GetAllNamespaces", mock.Anything).Return([]v1.Namespace{fixNsWithName("a"), fixNsWithName("b")}, nil).Once()
+
+		mockNetPolProvider := newClusterWideNetPolProvider()
+		defer mockNetPolProvider.NetworkPoliciesProvider.AssertExpectations(t)
+		defer mockNetPolProvider.AllNamespacesNetworkPoliciesProvider.AssertExpectations(t)
+		mockNetPolProvider.AllNamespacesNetworkPoliciesProvider.On("GetNetworkPoliciesForAllNamespaces", mock.Anything).Return(nil, fixForbiddenError("networkpolicies")).Once()
+		mockNetPolProvider.NetworkPoliciesProvider.On("GetNetworkPoliciesForNamespace", mock.Anything, "a").Return([]v13.NetworkPolicy{fixFixNetPol("a", "ingress-deny-all")}, nil).Once()
+		mockNetPolProvider.NetworkPoliciesProvider.On("GetNetworkPoliciesForNamespace", mock.Anything, "b").Return(nil, nil).Once()
+
+		mockCronjobProvider := &automock.PodCandidatesProvider{}
+		defer mockCronjobProvider.AssertExpectations(t)
+		mockCronjobProvider.On("GetPodCandidatesForNamespace", mock.Anything, "a").Return([]model.PodCandidate{fixPodCandidate("cronjob-a")}, nil).Once()
+		mockCronjobProvider.On("GetPodCandidatesForNamespace", mock.Anything, "b").Return(nil, nil).Once()
+
+		providers := map[string]state.PodCandidatesProvider{
+			"cronjob": mockCronjobProvider,
+		}
+		sut := state.NewBuilder(mockNsProvider, mockNetPolProvider, providers, state.Options{Workers: 2, ClusterWideLists: true})
+		// WHEN
+		actual, err := sut.Build(context.Background())
+		// THEN
+		require.NoError(t, err)
+		assert.Equal(t, []v13.NetworkPolicy{fixFixNetPol("a", "ingress-deny-all")}, actual.NetworkPolicies["a"])
+		assert.Equal(t, []model.PodCandidate{fixPodCandidate("cronjob-a")}, actual.PodCandidates["a"])
+	})
+
+	t.Run("got error on cluster-scoped call", func(t *testing.T) {
+		// GIVEN
+		mockNsProvider := &automock.NamespacesProvider{}
+		defer mockNsProvider.AssertExpectations(t)
+		mockNsProvider.On("GetAllNamespaces", mock.Anything).Return([]v1.Namespace{fixNsWithName("a")}, nil).Once()
+
+		mockNetPolProvider := newClusterWideNetPolProvider()
+		defer mockNetPolProvider.AllNamespacesNetworkPoliciesProvider.AssertExpectations(t)
+		mockNetPolProvider.AllNamespacesNetworkPoliciesProvider.On("GetNetworkPoliciesForAllNamespaces", mock.Anything).Return(nil, errors.New("some error")).Once()
+
+		sut := state.NewBuilder(mockNsProvider, mockNetPolProvider, nil, state.Options{Workers: 1, ClusterWideLists: true})
+		// WHEN
+		_, err := sut.Build(context.Background())
+		// THEN
+		require.EqualError(t, err, "while getting network policies for all namespaces: some error")
+	})
+}
+
+func TestBuildClusterStateWithPartialResults(t *testing.T) {
+	t.Run("records forbidden resources as coverage gaps", func(t *testing.T) {
+		// GIVEN
+		mockNsProvider := &automock.NamespacesProvider{}
+		defer mockNsProvider.AssertExpectations(t)
+		mockNsProvider.On("GetAllNamespaces", mock.Anything).Return([]v1.Namespace{fixNsWithName("a"), fixNsWithName("b")}, nil).Once()
+
+		mockNetPolProvider := &automock.NetworkPoliciesProvider{}
+		defer mockNetPolProvider.AssertExpectations(t)
+		mockNetPolProvider.On("GetNetworkPoliciesForNamespace", mock.Anything, "a").Return([]v13.NetworkPolicy{fixFixNetPol("a", "ingress-deny-all")}, nil).Once()
+		mockNetPolProvider.On("GetNetworkPoliciesForNamespace", mock.Anything, "b").Return(nil, fixForbiddenError("networkpolicies")).Once()
+
+		mockCronjobProvider := &automock.PodCandidatesProvider{}
+		defer mockCronjobProvider.AssertExpectations(t)
+		mockCronjobProvider.On("GetPodCandidatesForNamespace", mock.Anything, "a").Return(nil, fixForbiddenError("cronjobs")).Once()
+		mockCronjobProvider.On("GetPodCandidatesForNamespace", mock.Anything, "b").Return([]model.PodCandidate{fixPodCandidate("cronjob-b")}, nil).Once()
+
+		providers := map[string]state.PodCandidatesProvider{
+			"cronjob": mockCronjobProvider,
+		}
+		sut := state.NewBuilder(mockNsProvider, mockNetPolProvider, providers, state.Options{Workers: 2, PartialResults: true})
+		// WHEN
+		actual, err := sut.Build(context.Background())
+		// THEN
+		require.NoError(t, err)
+		assert.Equal(t, []v13.NetworkPolicy{fixFixNetPol("a", "ingress-deny-all")}, actual.NetworkPolicies["a"])
+		assert.Equal(t, []model.PodCandidate{fixPodCandidate("cronjob-b")}, actual.PodCandidates["b"])
+		assert.Equal(t, []model.CoverageGap{
+			{Namespace: "a", Kind: "cronjob", Reason: "Forbidden"},
+			{Namespace: "b", Kind: model.KindNetworkPolicy, Reason: "Forbidden"},
+		}, actual.CoverageGaps)
+	})
+
+	t.Run("records resources not served by the cluster as coverage gaps for all namespaces", func(t *testing.T) {
+		// GIVEN
+		mockNsProvider := &automock.NamespacesProvider{}
+		defer mockNsProvider.AssertExpectations(t)
+		mockNsProvider.On("GetAllNamespaces", mock.Anything).Return([]v1.Namespace{fixNsWithName("a")}, nil).Once()
+
+		mockNetPolProvider := &automock.NetworkPoliciesProvider{}
+		defer mockNetPolProvider.AssertExpectations(t)
+		mockNetPolProvider.On("GetNetworkPoliciesForNamespace", mock.Anything, "a").Return(nil, nil).Once()
+
+		mockCronjobProvider := newClusterWidePodCandidatesProvider()
+		defer mockCronjobProvider.AllNamespacesPodCandidatesProvider.AssertExpectations(t)
+		defer mockCronjobProvider.PodCandidatesProvider.AssertExpectations(t)
+		mockCronjobProvider.AllNamespacesPodCandidatesProvider.On("GetPodCandidatesForAllNamespaces", mock.Anything).Return(nil, apierrors.NewNotFound(schema.GroupResource{Resource: "cronjobs"}, "")).Once()
+
+		providers := map[string]state.PodCandidatesProvider{
+			"cronjob": mockCronjobProvider,
+		}
+		sut := state.NewBuilder(mockNsProvider, mockNetPolProvider, providers, state.Options{Workers: 2, ClusterWideLists: true, PartialResults: true})
+		// WHEN
+		actual, err := sut.Build(context.Background())
+		// THEN
+		require.NoError(t, err)
+		assert.Equal(t, []model.CoverageGap{{Kind: "cronjob", Reason: "NotFound"}}, actual.CoverageGaps)
+	})
+
+	t.Run("fails on errors other than missing access", func(t *testing.T) {
+		// GIVEN
+		mockNsProvider := &automock.NamespacesProvider{}
+		defer mockNsProvider.AssertExpectations(t)
+		mockNsProvider.On("GetAllNamespaces", mock.Anything).Return([]v1.Namespace{fixNsWithName("a")}, nil).Once()
+
+		mockNetPolProvider := &automock.NetworkPoliciesProvider{}
+		defer mockNetPolProvider.AssertExpectations(t)
+		mockNetPolProvider.On("GetNetworkPoliciesForNamespace", mock.Anything, "a").Return(nil, errors.New("some error")).Once()
+
+		sut := state.NewBuilder(mockNsProvider, mockNetPolProvider, nil, state.Options{Workers: 1, PartialResults: true})
+		// WHEN
+		_, err := sut.Build(context.Background())
+		// THEN
+		require.EqualError(t, err, "while getting network policies for namespace: a: some error")
+	})
+}
+
+func TestBuildClusterStateWithRetries(t *testing.T) {
+	givenRetry := wait.Backoff{Steps: 3, Duration: time.Millisecond}
+
+	t.Run("retries calls failed with transient errors", func(t *testing.T) {
+		// GIVEN
+		mockNsProvider := &automock.NamespacesProvider{}
+		defer mockNsProvider.AssertExpectations(t)
+		mockNsProvider.On("GetAllNamespaces", mock.Anything).Return(nil, apierrors.NewServiceUnavailable("overloaded")).Once()
+		mockNsProvider.On("GetAllNamespaces", mock.Anything).Return([]v1.Namespace{fixNsWithName("a")}, nil).Once()
+
+		mockNetPolProvider := &automock.NetworkPoliciesProvider{}
+		defer mockNetPolProvider.AssertExpectations(t)
+		mockNetPolProvider.On("GetNetworkPoliciesForNamespace", mock.Anything, "a").Return(nil, apierrors.NewTooManyRequests("slow down", 1)).Twice()
+		mockNetPolProvider.On("GetNetworkPoliciesForNamespace", mock.Anything, "a").Return([]v13.NetworkPolicy{fixFixNetPol("a", "ingress-deny-all")}, nil).Once()
+
+		mockDeploymentsProvider := &automock.PodCandidatesProvider{}
+		defer mockDeploymentsProvider.AssertExpectations(t)
+		mockDeploymentsProvider.On("GetPodCandidatesForNamespace", mock.Anything, "a").Return(nil, fmt.Errorf("while listing: %w", syscall.ECONNRESET)).Once()
+		mockDeploymentsProvider.On("GetPodCandidatesForNamespace", mock.Anything, "a").Return([]model.PodCandidate{fixPodCandidate("deploy-a")}, nil).Once()
+		providers := map[string]state.PodCandidatesProvider{
+			"deploy": mockDeploymentsProvider,
+		}
+		sut := state.NewBuilder(mockNsProvider, mockNetPolProvider, providers, state.Options{Workers: 1, Retry: givenRetry})
+		// WHEN
+		actual, err := sut.Build(context.Background())
+		// THEN
+		require.NoError(t, err)
+		assert.Equal(t, []v13.NetworkPolicy{fixFixNetPol("a", "ingress-deny-all")}, actual.NetworkPolicies["a"])
+		assert.Equal(t, []model.PodCandidate{fixPodCandidate("deploy-a")}, actual.PodCandidates["a"])
+	})
+
+	t.Run("gives up after configured number of attempts", func(t *testing.T) {
+		// GIVEN
+		mockNsProvider := &automock.NamespacesProvider{}
+		defer mockNsProvider.AssertExpectations(t)
+		mockNsProvider.On("GetAllNamespaces", mock.Anything).Return(nil, apierrors.NewInternalError(errors.New("etcd unavailable"))).Times(3)
+		sut := state.NewBuilder(mockNsProvider, nil, nil, state.Options{Workers: 1, Retry: givenRetry})
+		// WHEN
+		_, err := sut.Build(context.Background())
+		// THEN
+		require.EqualError(t, err, "while getting all namespaces: Internal error occurred: etcd unavailable")
+	})
+
+	t.Run("does not retry other errors", func(t *testing.T) {
+		// GIVEN
+		mockNsProvider := &automock.NamespacesProvider{}
+		defer mockNsProvider.AssertExpectations(t)
+		mockNsProvider.On("GetAllNamespaces", mock.Anything).Return(nil, fixForbiddenError("namespaces")).Once()
+		sut := state.NewBuilder(mockNsProvider, nil, nil, state.Options{Workers: 1, Retry: givenRetry})
+		// WHEN
+		_, err := sut.Build(context.Background())
+		// THEN
+		require.Error(t, err)
+		assert.True(t, apierrors.IsForbidden(err))
+	})
+}
+
+func TestBuildClusterStateWithServices(t *testing.T) {
+	givenRetry := wait.Backoff{Steps: 3, Duration: time.Millisecond}
+
+	t.Run("fetches services namespace by namespace with retries", func(t *testing.T) {
+		// GIVEN
+		mockNsProvider := &automock.NamespacesProvider{}
+		defer mockNsProvider.AssertExpectations(t)
+		mockNsProvider.On("GetAllNamespaces", mock.Anything).Return([]v1.Namespace{fixNsWithName("a"), fixNsWithName("b")}, nil).Once()
+
+		mockNetPolProvider := &automock.NetworkPoliciesProvider{}
+		mockNetPolProvider.On("GetNetworkPoliciesForNamespace", mock.Anything, mock.Anything).Return(nil, nil)
+
+		mockServicesProvider := &automock.ServicesProvider{}
+		defer mockServicesProvider.AssertExpectations(t)
+		mockServicesProvider.On("GetServicesForNamespace", mock.Anything, "a").Return(nil, apierrors.NewTooManyRequests("slow down", 0)).Once()
+		mockServicesProvider.On("GetServicesForNamespace", mock.Anything, "a").Return([]v1.Service{fixService("a", "web")}, nil).Once()
+		mockServicesProvider.On("GetServicesForNamespace", mock.Anything, "b").Return(nil, nil).Once()
+
+		sut := state.NewBuilder(mockNsProvider, mockNetPolProvider, nil, state.Options{Workers: 2, Retry: givenRetry}).WithServices(mockServicesProvider)
+		// WHEN
+		actual, err := sut.Build(context.Background())
+		// THEN
+		require.NoError(t, err)
+		assert.Equal(t, map[string][]v1.Service{"a": {fixService("a", "web")}}, actual.Services)
+	})
+
+	t.Run("falls back to namespaced calls when cluster-scoped call is forbidden", func(t *testing.T) {
+		// GIVEN
+		mockNsProvider := &automock.NamespacesProvider{}
+		defer mockNsProvider.AssertExpectations(t)
+		mockNsProvider.On("GetAllNamespaces", mock.Anything).Return([]v1.Namespace{fixNsWithName("a")}, nil).Once()
+
+		mockNetPolProvider := &automock.NetworkPoliciesProvider{}
+		mockNetPolProvider.On("GetNetworkPoliciesForNamespace", mock.Anything, "a").Return(nil, nil)
+
+		mockServicesProvider := newClusterWideServicesProvider()
+		defer mockServicesProvider.ServicesProvider.AssertExpectations(t)
+		defer mockServicesProvider.AllNamespacesServicesProvider.AssertExpectations(t)
+		mockServicesProvider.AllNamespacesServicesProvider.On("GetServicesForAllNamespaces", mock.Anything).Return(nil, fixForbiddenError("services")).Once()
+		mockServicesProvider.ServicesProvider.On("GetServicesForNamespace", mock.Anything, "a").Return([]v1.Service{fixService("a", "web")}, nil).Once()
+
+		sut := state.NewBuilder(mockNsProvider, mockNetPolProvider, nil, state.Options{Workers: 1, ClusterWideLists: true}).WithServices(mockServicesProvider)
+		// WHEN
+		actual, err := sut.Build(context.Background())
+		// THEN
+		require.NoError(t, err)
+		assert.Equal(t, map[string][]v1.Service{"a": {fixService("a", "web")}}, actual.Services)
+	})
+
+	t.Run("records forbidden services as coverage gaps", func(t *testing.T) {
+		// GIVEN
+		mockNsProvider := &automock.NamespacesProvider{}
+		defer mockNsProvider.AssertExpectations(t)
+		mockNsProvider.On("GetAllNamespaces", mock.Anything).Return([]v1.Namespace{fixNsWithName("a"), fixNsWithName("b")}, nil).Once()
+
+		mockNetPolProvider := &automock.NetworkPoliciesProvider{}
+		mockNetPolProvider.On("GetNetworkPoliciesForNamespace", mock.Anything, mock.Anything).Return(nil, nil)
+
+		mockServicesProvider := &automock.ServicesProvider{}
+		defer mockServicesProvider.AssertExpectations(t)
+		mockServicesProvider.On("GetServicesForNamespace", mock.Anything, "a").Return([]v1.Service{fixService("a", "web")}, nil).Once()
+		mockServicesProvider.On("GetServicesForNamespace", mock.Anything, "b").Return(nil, fixForbiddenError("services")).Once()
+
+		sut := state.NewBuilder(mockNsProvider, mockNetPolProvider, nil, state.Options{Workers: 2, PartialResults: true}).WithServices(mockServicesProvider)
+		// WHEN
+		actual, err := sut.Build(context.Background())
+		// THEN
+		require.NoError(t, err)
+		assert.Equal(t, map[string][]v1.Service{"a": {fixService("a", "web")}}, actual.Services)
+		assert.Equal(t, []model.CoverageGap{{Namespace: "b", Kind: model.KindService, Reason: "Forbidden"}}, actual.CoverageGaps)
+	})
+
+	t.Run("fails on forbidden services without partial results", func(t *testing.T) {
+		// GIVEN
+		mockNsProvider := &automock.NamespacesProvider{}
+		defer mockNsProvider.AssertExpectations(t)
+		mockNsProvider.On("GetAllNamespaces", mock.Anything).Return([]v1.Namespace{fixNsWithName("a")}, nil).Once()
+
+		mockNetPolProvider := &automock.NetworkPoliciesProvider{}
+		mockNetPolProvider.On("GetNetworkPoliciesForNamespace", mock.Anything, "a").Return(nil, nil)
+
+		mockServicesProvider := &automock.ServicesProvider{}
+		defer mockServicesProvider.AssertExpectations(t)
+		mockServicesProvider.On("GetServicesForNamespace", mock.Anything, "a").Return(nil, fixForbiddenError("services")).Once()
+
+		sut := state.NewBuilder(mockNsProvider, mockNetPolProvider, nil, state.Options{Workers: 1}).WithServices(mockServicesProvider)
+		// WHEN
+		_, err := sut.Build(context.Background())
+		// THEN
+		require.EqualError(t, err, "while getting services for namespace: a: "+fixForbiddenError("services").Error())
+	})
+}
+
+type clusterWideServicesProvider struct {
+	*automock.ServicesProvider
+	*automock.AllNamespacesServicesProvider
+}
+
+func newClusterWideServicesProvider() *clusterWideServicesProvider {
+	return &clusterWideServicesProvider{
+		ServicesProvider:              &automock.ServicesProvider{},
+		AllNamespacesServicesProvider: &automock.AllNamespacesServicesProvider{},
+	}
+}
+
+type clusterWideNetPolProvider struct {
+	*automock.NetworkPoliciesProvider
+	*automock.AllNamespacesNetworkPoliciesProvider
+}
+
+func newClusterWideNetPolProvider() *clusterWideNetPolProvider {
+	return &clusterWideNetPolProvider{
+		NetworkPoliciesProvider:              &automock.NetworkPoliciesProvider{},
+		AllNamespacesNetworkPoliciesProvider: &automock.AllNamespacesNetworkPoliciesProvider{},
+	}
+}
+
+type clusterWidePodCandidatesProvider struct {
+	*automock.PodCandidatesProvider
+	*automock.AllNamespacesPodCandidatesProvider
+}
+
+func newClusterWidePodCandidatesProvider() *clusterWidePodCandidatesProvider {
+	return &clusterWidePodCandidatesProvider{
+		PodCandidatesProvider:              &automock.PodCandidatesProvider{},
+		AllNamespacesPodCandidatesProvider: &automock.AllNamespacesPodCandidatesProvider{},
+	}
+}
+
+func fixForbiddenError(resource string) error {
+	return apierrors.NewForbidden(schema.GroupResource{Resource: resource}, "", errors.New("not allowed"))
 }
 
 func fixNsWithName(name string) v1.Namespace {
@@ -132,8 +623,23 @@ func fixFixNetPol(namespace, name string) v13.NetworkPolicy {
 	}
 }
 
-func fixPodCandidate(ownerName string) model.PodCandidate {
+func fixService(namespace, name string) v1.Service {
+	return v1.Service{
+		ObjectMeta: v12.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+		},
+	}
+}
+
+func fixPodCandidate(name string) model.PodCandidate {
+	return model.PodCandidate{
+		Owner: model.Owner{Name: name},
+	}
+}
+
+func fixOwnedPodCandidate(kind, namespace, name string) model.PodCandidate {
 	return model.PodCandidate{
-		OwnerName: ownerName,
+		Owner: model.Owner{Kind: kind, Namespace: namespace, Name: name},
 	}
 }
@@ -0,0 +1,92 @@
+package state
+
+import (
+	"fmt"
+
+	"k8s.io/client-go/dynamic/dynamicinformer"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/aszecowka/netpolvalidator/internal/model"
+	"github.com/aszecowka/netpolvalidator/internal/netpol"
+	"github.com/aszecowka/netpolvalidator/internal/ns"
+	"github.com/aszecowka/netpolvalidator/internal/podcandidate"
+)
+
+// NewCachedBuilder creates a Builder that reads the cluster state from informers of the given factories instead of
+// calling the API server. Like NewFetchers, it uses the newest version of every workload type served by the API server.
+// Pod candidates of the given custom resources are read from dynamic informers too, custom resources have to be served.
+// Informers are registered in the factories and returned by kind, the caller has to start the factories and wait for cache sync.
+func NewCachedBuilder(factory informers.SharedInformerFactory, dynamicFactory dynamicinformer.DynamicSharedInformerFactory, served podcandidate.ServedResources, customResources []podcandidate.CustomResource) (*Builder, map[string]cache.SharedIndexInformer, error) {
+	nsInformer := factory.Core().V1().Namespaces()
+	netpolInformer := factory.Networking().V1().NetworkPolicies()
+	informersByKind := map[string]cache.SharedIndexInformer{
+		model.KindNamespace:     nsInformer.Informer(),
+		model.KindNetworkPolicy: netpolInformer.Informer(),
+	}
+	podCandidateProviders := make(map[string]PodCandidatesProvider)
+	register := func(workloadType podcandidate.WorkloadType, informer cache.SharedIndexInformer, provider PodCandidatesProvider) {
+		informersByKind[string(workloadType)] = informer
+		podCandidateProviders[string(workloadType)] = provider
+	}
+
+	switch {
+	case served.Serves(podcandidate.CronjobsV1Resource):
+		informer := dynamicFactory.ForResource(podcandidate.CronjobsV1Resource)
+		register(podcandidate.WorkloadCronjob, informer.Informer(), podcandidate.NewCachedCronjobV1Fetcher(informer.Lister()))
+	case served.Serves(podcandidate.CronjobsV1beta1Resource):
+		informer := factory.Batch().V1beta1().CronJobs()
+		register(podcandidate.WorkloadCronjob, informer.Informer(), podcandidate.NewCachedCronjobFetcher(informer.Lister()))
+	}
+	if served.Serves(podcandidate.DaemonsetsResource) {
+		informer := factory.Apps().V1().DaemonSets()
+		register(podcandidate.WorkloadDaemonset, informer.Informer(), podcandidate.NewCachedDaemonsetFetcher(informer.Lister()))
+	}
+	if served.Serves(podcandidate.DeploymentsResource) {
+		informer := factory.Apps().V1().Deployments()
+		register(podcandidate.WorkloadDeployment, informer.Informer(), podcandidate.NewCachedDeploymentsFetcher(informer.Lister()))
+	}
+	if served.Serves(podcandidate.JobsResource) {
+		informer := factory.Batch().V1().Jobs()
+		register(podcandidate.WorkloadJob, informer.Informer(), podcandidate.NewCachedJobFetcher(informer.Lister()))
+	}
+	if served.Serves(podcandidate.PodsResource) {
+		informer := factory.Core().V1().Pods()
+		register(podcandidate.WorkloadPod, informer.Informer(), podcandidate.NewCachedPodsFetcher(informer.Lister()))
+	}
+	if served.Serves(podcandidate.ReplicasetsResource) {
+		informer := factory.Apps().V1().ReplicaSets()
+		register(podcandidate.WorkloadReplicaset, informer.Informer(), podcandidate.NewCachedReplicasetFetcher(informer.Lister()))
+	}
+	if served.Serves(podcandidate.ReplicationControllersResource) {
+		informer := factory.Core().V1().ReplicationControllers()
+		register(podcandidate.WorkloadReplicationController, informer.Informer(), podcandidate.NewCachedReplicationControllerFetcher(informer.Lister()))
+	}
+	if served.Serves(podcandidate.RolloutsResource) {
+		informer := dynamicFactory.ForResource(podcandidate.RolloutsResource)
+		register(podcandidate.WorkloadRollout, informer.Informer(), podcandidate.NewCachedRolloutFetcher(informer.Lister()))
+	}
+	if served.Serves(podcandidate.StatefulsetsResource) {
+		informer := factory.Apps().V1().StatefulSets()
+		register(podcandidate.WorkloadStatefulset, informer.Informer(), podcandidate.NewCachedStatefulsetsFetcher(informer.Lister()))
+	}
+
+	for _, cr := range customResources {
+		if _, found := informersByKind[cr.Name]; found {
+			return nil, nil, fmt.Errorf("custom resource %q has the same name as a built-in workload type", cr.Name)
+		}
+		// an informer of a resource that is not served would never sync
+		if !served.Serves(cr.GroupVersionResource()) {
+			return nil, nil, fmt.Errorf("custom resource %q: %s is not served by the API server", cr.Name, cr.GroupVersionResource().String())
+		}
+		informer := dynamicFactory.ForResource(cr.GroupVersionResource())
+		provider, err := podcandidate.NewCachedCustomResourceFetcher(informer.Lister(), cr)
+		if err != nil {
+			return nil, nil, err
+		}
+		register(podcandidate.WorkloadType(cr.Name), informer.Informer(), provider)
+	}
+
+	builder := NewBuilder(ns.NewCached(nsInformer.Lister()), netpol.NewCachedService(netpolInformer.Lister()), podCandidateProviders, Options{Workers: 1})
+	return builder, informersByKind, nil
+}
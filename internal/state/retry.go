@@ -0,0 +1,40 @@
+package state
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	utilnet "k8s.io/apimachinery/pkg/util/net"
+	"k8s.io/client-go/util/retry"
+)
+
+func (b *Builder) withRetry(ctx context.Context, fn func() error) error {
+	return retry.OnError(b.opts.Retry, func(err error) bool {
+		return ctx.Err() == nil && isTransient(err)
+	}, fn)
+}
+
+// isTransient reports whether err is likely to disappear when the call is repeated:
+// the API server throttles or is overloaded, or the connection was interrupted.
+func isTransient(err error) bool {
+	if apierrors.IsTooManyRequests(err) ||
+		apierrors.IsServerTimeout(err) ||
+		apierrors.IsTimeout(err) ||
+		apierrors.IsInternalError(err) ||
+		apierrors.IsServiceUnavailable(err) ||
+		apierrors.IsUnexpectedServerError(err) {
+		return true
+	}
+	var status apierrors.APIStatus
+	if errors.As(err, &status) && status.Status().Code >= http.StatusInternalServerError {
+		return true
+	}
+	return utilnet.IsConnectionReset(err) ||
+		utilnet.IsConnectionRefused(err) ||
+		utilnet.IsProbableEOF(err) ||
+		errors.Is(err, io.EOF) ||
+		errors.Is(err, io.ErrUnexpectedEOF)
+}
@@ -0,0 +1,67 @@
+package state
+
+import (
+	"context"
+	"fmt"
+
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/client-go/util/workqueue"
+
+	"github.com/aszecowka/netpolvalidator/internal/model"
+)
+
+// fetchServices fetches services of all namespaces of the cluster state the same way as network policies:
+// with a cluster-scoped call when enabled and allowed, otherwise namespace by namespace.
+func (b *Builder) fetchServices(ctx context.Context, out *model.ClusterState) error {
+	out.Services = make(map[string][]v1.Service)
+	if provider, ok := b.servicesProvider.(AllNamespacesServicesProvider); ok && b.opts.ClusterWideLists {
+		var services map[string][]v1.Service
+		err := b.withRetry(ctx, func() (err error) {
+			services, err = provider.GetServicesForAllNamespaces(ctx)
+			return err
+		})
+		switch {
+		case err == nil:
+			for _, ns := range out.Namespaces {
+				if inNs, found := services[ns.Name]; found {
+					out.Services[ns.Name] = inNs
+				}
+			}
+			return nil
+		case apierrors.IsForbidden(err):
+			// fall back to namespaced calls
+		case b.isCoverageGap(err):
+			out.CoverageGaps = append(out.CoverageGaps, model.NewCoverageGap(metav1.NamespaceAll, model.KindService, err))
+			return nil
+		default:
+			return fmt.Errorf("while getting services for all namespaces: %w", err)
+		}
+	}
+
+	services := make([][]v1.Service, len(out.Namespaces))
+	errs := make([]error, len(out.Namespaces))
+	workqueue.ParallelizeUntil(ctx, b.opts.Workers, len(out.Namespaces), func(nsIdx int) {
+		ns := out.Namespaces[nsIdx].Name
+		err := b.withRetry(ctx, func() (err error) {
+			services[nsIdx], err = b.servicesProvider.GetServicesForNamespace(ctx, ns)
+			return err
+		})
+		if err != nil {
+			errs[nsIdx] = fmt.Errorf("while getting services for namespace: %s: %w", ns, err)
+		}
+	})
+	for nsIdx, ns := range out.Namespaces {
+		if b.isCoverageGap(errs[nsIdx]) {
+			out.CoverageGaps = append(out.CoverageGaps, model.NewCoverageGap(ns.Name, model.KindService, errs[nsIdx]))
+			errs[nsIdx] = nil
+			continue
+		}
+		if len(services[nsIdx]) > 0 {
+			out.Services[ns.Name] = services[nsIdx]
+		}
+	}
+	return utilerrors.NewAggregate(errs)
+}
@@ -0,0 +1,59 @@
+package svc
+
+import (
+	"context"
+	"fmt"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	typedcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+)
+
+const listPageSize int64 = 500
+
+type service struct {
+	client typedcorev1.ServicesGetter
+}
+
+func NewService(client typedcorev1.ServicesGetter) *service {
+	return &service{client: client}
+}
+
+// GetServicesForNamespace lists services from the given namespace.
+func (s *service) GetServicesForNamespace(ctx context.Context, ns string) ([]v1.Service, error) {
+	out, err := s.list(ctx, ns)
+	if err != nil {
+		return nil, fmt.Errorf("while listing services from namespace %s: %w", ns, err)
+	}
+	return out, nil
+}
+
+// GetServicesForAllNamespaces lists services with a single cluster-scoped call and groups them by namespace.
+func (s *service) GetServicesForAllNamespaces(ctx context.Context) (map[string][]v1.Service, error) {
+	all, err := s.list(ctx, metav1.NamespaceAll)
+	if err != nil {
+		return nil, fmt.Errorf("while listing services from all namespaces: %w", err)
+	}
+	out := make(map[string][]v1.Service)
+	for _, item := range all {
+		out[item.Namespace] = append(out[item.Namespace], item)
+	}
+	return out, nil
+}
+
+func (s *service) list(ctx context.Context, ns string) ([]v1.Service, error) {
+	var out []v1.Service
+	continueOption := ""
+	for {
+		list, err := s.client.Services(ns).List(ctx, metav1.ListOptions{Limit: listPageSize, Continue: continueOption})
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, list.Items...)
+		continueOption = list.Continue
+		if continueOption == "" {
+			break
+		}
+	}
+	return out, nil
+}
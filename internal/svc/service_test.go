@@ -0,0 +1,55 @@
+package svc_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/aszecowka/netpolvalidator/internal/svc"
+)
+
+func TestGetServicesForAllNamespaces(t *testing.T) {
+	// GIVEN
+	svcOrders := fixService("orders", "web")
+	svcPayments := fixService("payments", "api")
+	fakeClientset := fake.NewSimpleClientset(&svcOrders, &svcPayments)
+	sut := svc.NewService(fakeClientset.CoreV1())
+	// WHEN
+	actual, err := sut.GetServicesForAllNamespaces(context.Background())
+	// THEN
+	require.NoError(t, err)
+	assert.Equal(t, map[string][]v1.Service{
+		"orders":   {svcOrders},
+		"payments": {svcPayments},
+	}, actual)
+}
+
+func TestGetServicesForNamespace(t *testing.T) {
+	// GIVEN
+	svcOrders := fixService("orders", "web")
+	svcPayments := fixService("payments", "api")
+	fakeClientset := fake.NewSimpleClientset(&svcOrders, &svcPayments)
+	sut := svc.NewService(fakeClientset.CoreV1())
+	// WHEN
+	actual, err := sut.GetServicesForNamespace(context.Background(), "orders")
+	// THEN
+	require.NoError(t, err)
+	assert.Equal(t, []v1.Service{svcOrders}, actual)
+}
+
+func fixService(namespace, name string) v1.Service {
+	return v1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+		},
+		Spec: v1.ServiceSpec{
+			Selector: map[string]string{"app": name},
+		},
+	}
+}
@@ -0,0 +1,22 @@
+package watch
+
+import "log"
+
+type logListener struct {
+	logger *log.Logger
+}
+
+// NewLogListener creates a Listener that logs new and resolved violations.
+func NewLogListener(logger *log.Logger) *logListener {
+	return &logListener{logger: logger}
+}
+
+func (l *logListener) OnScan(scan Scan) {
+	for _, v := range scan.Added {
+		l.logger.Printf("new violation: %s", v)
+	}
+	for _, v := range scan.Resolved {
+		l.logger.Printf("resolved violation: %s", v)
+	}
+	l.logger.Printf("validated cluster state in %s, found %d violations", scan.Duration, len(scan.Violations))
+}
@@ -0,0 +1,292 @@
+package watch
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"reflect"
+	"sort"
+	"sync"
+	"time"
+
+	netv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/dynamic/dynamicinformer"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/aszecowka/netpolvalidator/internal/model"
+	"github.com/aszecowka/netpolvalidator/internal/podcandidate"
+	"github.com/aszecowka/netpolvalidator/internal/rule"
+	"github.com/aszecowka/netpolvalidator/internal/scope"
+	"github.com/aszecowka/netpolvalidator/internal/state"
+)
+
+// Scan is the result of validating the cluster state after a change.
+type Scan struct {
+	State      model.ClusterState
+	Violations []model.Violation
+	// ViolationsByValidator holds the same violations as Violations, grouped by the name of the validator that reported them.
+	ViolationsByValidator map[string][]model.Violation
+	// Added holds violations that were not reported by the previous scan.
+	Added []model.Violation
+	// Resolved holds violations reported by the previous scan that are gone now.
+	Resolved []model.Violation
+	Finished time.Time
+	Duration time.Duration
+}
+
+type Listener interface {
+	OnScan(scan Scan)
+}
+
+// Watcher keeps the cluster state up to date with shared informers and re-runs validators when it changes.
+type Watcher struct {
+	factory        informers.SharedInformerFactory
+	dynamicFactory dynamicinformer.DynamicSharedInformerFactory
+	builder        *state.Builder
+	validators     map[string]rule.Validator
+	scope          scope.Scope
+	listeners      []Listener
+	debounce       time.Duration
+	logger         *log.Logger
+
+	mu      sync.Mutex
+	changed map[string]struct{}
+	notify  chan struct{}
+
+	violations map[string][]model.Violation
+	previous   []model.Violation
+}
+
+// New creates a Watcher. Pod candidates of the given custom resources are watched together with built-in workloads.
+// Validators check network policies from namespaces in the given scope only.
+// Changes are collected for the debounce period before validators are re-run. Failed re-validations are logged with the logger.
+func New(clientset kubernetes.Interface, dynamicClient dynamic.Interface, customResources []podcandidate.CustomResource, validators map[string]rule.Validator, validationScope scope.Scope, debounce time.Duration, logger *log.Logger, listeners ...Listener) (*Watcher, error) {
+	served, err := podcandidate.DiscoverServedResources(clientset.Discovery())
+	if err != nil {
+		return nil, err
+	}
+	w := &Watcher{
+		factory:        informers.NewSharedInformerFactory(clientset, 0),
+		dynamicFactory: dynamicinformer.NewDynamicSharedInformerFactory(dynamicClient, 0),
+		validators:     validators,
+		scope:          validationScope,
+		listeners:      listeners,
+		debounce:       debounce,
+		logger:         logger,
+		changed:        make(map[string]struct{}),
+		notify:         make(chan struct{}, 1),
+		violations:     make(map[string][]model.Violation),
+	}
+
+	builder, informersByKind, err := state.NewCachedBuilder(w.factory, w.dynamicFactory, served, customResources)
+	if err != nil {
+		return nil, err
+	}
+	for kind, informer := range informersByKind {
+		informer.AddEventHandler(w.handlerFor(kind, changeDetectorFor(kind)))
+	}
+	w.builder = builder
+
+	return w, nil
+}
+
+// changeDetectorFor returns the function telling whether an update of an object of the given kind is relevant for validators.
+func changeDetectorFor(kind string) func(oldObj, newObj interface{}) bool {
+	switch kind {
+	case model.KindNamespace:
+		return labelsChanged
+	case model.KindNetworkPolicy:
+		// annotations on network policies are written by the watcher itself, they must not trigger another scan
+		return networkPolicyChanged
+	case string(podcandidate.WorkloadPod):
+		// pods change status all the time, only their labels matter for validators
+		return labelsChanged
+	default:
+		return resourceVersionChanged
+	}
+}
+
+// Run starts informers, validates the whole cluster once and then re-validates it after every relevant change, until ctx is done.
+func (w *Watcher) Run(ctx context.Context) error {
+	w.factory.Start(ctx.Done())
+	w.dynamicFactory.Start(ctx.Done())
+	for informerType, synced := range w.factory.WaitForCacheSync(ctx.Done()) {
+		if !synced {
+			return fmt.Errorf("while waiting for %v informer cache to sync", informerType)
+		}
+	}
+	for resource, synced := range w.dynamicFactory.WaitForCacheSync(ctx.Done()) {
+		if !synced {
+			return fmt.Errorf("while waiting for %s informer cache to sync", resource.String())
+		}
+	}
+
+	// objects delivered during the initial sync are covered by the first scan
+	w.takeChanged()
+	if err := w.scan(ctx, nil); err != nil {
+		return fmt.Errorf("while validating cluster state: %w", err)
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-w.notify:
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(w.debounce):
+		}
+
+		changedKinds := w.takeChanged()
+		if len(changedKinds) == 0 {
+			continue
+		}
+		if err := w.scan(ctx, changedKinds); err != nil {
+			w.logger.Printf("while validating cluster state after change of %v: %s", sortedKinds(changedKinds), err)
+		}
+	}
+}
+
+// scan re-runs validators affected by changedKinds. Nil changedKinds re-runs all validators.
+func (w *Watcher) scan(ctx context.Context, changedKinds map[string]struct{}) error {
+	started := time.Now()
+	built, err := w.builder.Build(ctx)
+	if err != nil {
+		return err
+	}
+	clusterState := w.scope.Apply(*built)
+
+	for _, name := range w.sortedValidatorNames() {
+		validator := w.validators[name]
+		if changedKinds != nil && !isAffected(validator, changedKinds) {
+			continue
+		}
+		violations, err := validator.Validate(clusterState)
+		if err != nil {
+			return fmt.Errorf("while running %s validator: %w", name, err)
+		}
+		w.violations[name] = violations
+	}
+
+	var all []model.Violation
+	byValidator := make(map[string][]model.Violation, len(w.violations))
+	for _, name := range w.sortedValidatorNames() {
+		all = append(all, w.violations[name]...)
+		byValidator[name] = w.violations[name]
+	}
+	finished := time.Now()
+	scan := Scan{
+		State:                 clusterState,
+		Violations:            all,
+		ViolationsByValidator: byValidator,
+		Added:                 model.Subtract(all, w.previous),
+		Resolved:              model.Subtract(w.previous, all),
+		Finished:              finished,
+		Duration:              finished.Sub(started),
+	}
+	w.previous = all
+
+	for _, listener := range w.listeners {
+		listener.OnScan(scan)
+	}
+	return nil
+}
+
+func (w *Watcher) handlerFor(kind string, relevant func(oldObj, newObj interface{}) bool) cache.ResourceEventHandler {
+	return cache.ResourceEventHandlerFuncs{
+		AddFunc: func(interface{}) {
+			w.markChanged(kind)
+		},
+		UpdateFunc: func(oldObj, newObj interface{}) {
+			if relevant(oldObj, newObj) {
+				w.markChanged(kind)
+			}
+		},
+		DeleteFunc: func(interface{}) {
+			w.markChanged(kind)
+		},
+	}
+}
+
+func (w *Watcher) markChanged(kind string) {
+	w.mu.Lock()
+	w.changed[kind] = struct{}{}
+	w.mu.Unlock()
+
+	select {
+	case w.notify <- struct{}{}:
+	default:
+	}
+}
+
+func (w *Watcher) takeChanged() map[string]struct{} {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	out := w.changed
+	w.changed = make(map[string]struct{})
+	return out
+}
+
+func isAffected(validator rule.Validator, changedKinds map[string]struct{}) bool {
+	dependent, ok := validator.(rule.Dependent)
+	if !ok {
+		return true
+	}
+	for _, kind := range dependent.DependsOn() {
+		if _, found := changedKinds[kind]; found {
+			return true
+		}
+	}
+	return false
+}
+
+func resourceVersionChanged(oldObj, newObj interface{}) bool {
+	oldMeta, oldErr := meta.Accessor(oldObj)
+	newMeta, newErr := meta.Accessor(newObj)
+	if oldErr != nil || newErr != nil {
+		return true
+	}
+	return oldMeta.GetResourceVersion() != newMeta.GetResourceVersion()
+}
+
+func networkPolicyChanged(oldObj, newObj interface{}) bool {
+	oldNetPol, oldOk := oldObj.(*netv1.NetworkPolicy)
+	newNetPol, newOk := newObj.(*netv1.NetworkPolicy)
+	if !oldOk || !newOk {
+		return true
+	}
+	return !reflect.DeepEqual(oldNetPol.Spec, newNetPol.Spec) || !reflect.DeepEqual(oldNetPol.Labels, newNetPol.Labels)
+}
+
+func labelsChanged(oldObj, newObj interface{}) bool {
+	oldMeta, oldErr := meta.Accessor(oldObj)
+	newMeta, newErr := meta.Accessor(newObj)
+	if oldErr != nil || newErr != nil {
+		return true
+	}
+	return !reflect.DeepEqual(oldMeta.GetLabels(), newMeta.GetLabels())
+}
+
+func (w *Watcher) sortedValidatorNames() []string {
+	out := make([]string, 0, len(w.validators))
+	for name := range w.validators {
+		out = append(out, name)
+	}
+	sort.Strings(out)
+	return out
+}
+
+func sortedKinds(kinds map[string]struct{}) []string {
+	out := make([]string, 0, len(kinds))
+	for kind := range kinds {
+		out = append(out, kind)
+	}
+	sort.Strings(out)
+	return out
+}
@@ -0,0 +1,324 @@
+package watch_test
+
+import (
+	"context"
+	"io/ioutil"
+	"log"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	netv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/aszecowka/netpolvalidator/internal/model"
+	"github.com/aszecowka/netpolvalidator/internal/podcandidate"
+	"github.com/aszecowka/netpolvalidator/internal/rule"
+	"github.com/aszecowka/netpolvalidator/internal/scope"
+	"github.com/aszecowka/netpolvalidator/internal/watch"
+)
+
+func TestWatcher(t *testing.T) {
+	t.Run("reports new and resolved violations", func(t *testing.T) {
+		// GIVEN
+		fakeClientset := fake.NewSimpleClientset(fixNs("orders"), fixNetPol("orders", "ingress-to-a", "orders-a"))
+		listener := newChanListener()
+		validators := map[string]rule.Validator{
+			"label correctness": rule.NewLabelCorrectness(),
+		}
+		fakeClientset.Resources = fixServedResources()
+		sut, err := watch.New(fakeClientset, dynamicfake.NewSimpleDynamicClient(runtime.NewScheme()), nil, validators, scope.Scope{}, 10*time.Millisecond, log.New(ioutil.Discard, "", 0), listener)
+		require.NoError(t, err)
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		done := make(chan error)
+		go func() {
+			done <- sut.Run(ctx)
+		}()
+
+		// WHEN
+		initial := listener.next(t)
+		_, err = fakeClientset.AppsV1().Deployments("orders").Create(ctx, fixDeployment("orders", "orders-a"), metav1.CreateOptions{})
+		require.NoError(t, err)
+		afterFix := listener.next(t)
+		require.NoError(t, fakeClientset.AppsV1().Deployments("orders").Delete(ctx, "orders-a", metav1.DeleteOptions{}))
+		afterRegression := listener.next(t)
+
+		// THEN
+		expectedViolation := model.NewViolation(*fixNetPol("orders", "ingress-to-a", "orders-a"), "NoMatchingPods", "no pods matching pod selector", model.ViolationInvalidLabel)
+		assert.Equal(t, []model.Violation{expectedViolation}, initial.Violations)
+		assert.Equal(t, []model.Violation{expectedViolation}, initial.Added)
+
+		assert.Empty(t, afterFix.Violations)
+		assert.Empty(t, afterFix.Added)
+		assert.Equal(t, []model.Violation{expectedViolation}, afterFix.Resolved)
+
+		assert.Equal(t, []model.Violation{expectedViolation}, afterRegression.Added)
+		assert.Empty(t, afterRegression.Resolved)
+
+		cancel()
+		require.NoError(t, <-done)
+	})
+
+	t.Run("does not report again a violation whose message changed", func(t *testing.T) {
+		// GIVEN
+		netPol := fixNetPol("orders", "ingress-to-db", "orders")
+		netPol.Spec.PodSelector.MatchLabels["tier"] = "database"
+		fakeClientset := fake.NewSimpleClientset(fixNs("orders"), netPol, fixDeployment("orders", "orders"))
+		listener := newChanListener()
+		validators := map[string]rule.Validator{
+			"label correctness": rule.NewLabelCorrectness(),
+		}
+		fakeClientset.Resources = fixServedResources()
+		sut, err := watch.New(fakeClientset, dynamicfake.NewSimpleDynamicClient(runtime.NewScheme()), nil, validators, scope.Scope{}, 10*time.Millisecond, log.New(ioutil.Discard, "", 0), listener)
+		require.NoError(t, err)
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		done := make(chan error)
+		go func() {
+			done <- sut.Run(ctx)
+		}()
+
+		// WHEN
+		initial := listener.next(t)
+		_, err = fakeClientset.AppsV1().Deployments("orders").Create(ctx, fixDeployment("orders", "payments"), metav1.CreateOptions{})
+		require.NoError(t, err)
+		afterChange := listener.next(t)
+
+		// THEN
+		require.Len(t, initial.Added, 1)
+		require.Len(t, afterChange.Violations, 1)
+		assert.NotEqual(t, initial.Added[0].Message, afterChange.Violations[0].Message)
+		assert.Empty(t, afterChange.Added)
+		assert.Empty(t, afterChange.Resolved)
+
+		cancel()
+		require.NoError(t, <-done)
+	})
+
+	t.Run("re-runs only validators affected by the change", func(t *testing.T) {
+		// GIVEN
+		fakeClientset := fake.NewSimpleClientset(fixNs("orders"))
+		listener := newChanListener()
+		policiesOnly := &dependentValidator{dependsOn: []string{model.KindNetworkPolicy}}
+		everything := &countingValidator{}
+		validators := map[string]rule.Validator{
+			"policies only": policiesOnly,
+			"everything":    everything,
+		}
+		fakeClientset.Resources = fixServedResources()
+		sut, err := watch.New(fakeClientset, dynamicfake.NewSimpleDynamicClient(runtime.NewScheme()), nil, validators, scope.Scope{}, 10*time.Millisecond, log.New(ioutil.Discard, "", 0), listener)
+		require.NoError(t, err)
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		done := make(chan error)
+		go func() {
+			done <- sut.Run(ctx)
+		}()
+
+		// WHEN
+		listener.next(t)
+		_, err = fakeClientset.AppsV1().Deployments("orders").Create(ctx, fixDeployment("orders", "orders-a"), metav1.CreateOptions{})
+		require.NoError(t, err)
+		listener.next(t)
+
+		// THEN
+		assert.Equal(t, 1, policiesOnly.calls)
+		assert.Equal(t, 2, everything.calls)
+
+		cancel()
+		require.NoError(t, <-done)
+	})
+
+	t.Run("watches pod candidates of custom resources", func(t *testing.T) {
+		// GIVEN
+		fakeClientset := fake.NewSimpleClientset(fixNs("orders"), fixNetPol("orders", "ingress-to-a", "orders-a"))
+		fakeClientset.Resources = append(fixServedResources(), &metav1.APIResourceList{GroupVersion: "serving.knative.dev/v1", APIResources: []metav1.APIResource{{Name: "services"}}})
+		dynamicClient := dynamicfake.NewSimpleDynamicClient(runtime.NewScheme(), fixKnativeService("orders", "orders-a"))
+		listener := newChanListener()
+		validators := map[string]rule.Validator{
+			"label correctness": rule.NewLabelCorrectness(),
+		}
+		sut, err := watch.New(fakeClientset, dynamicClient, []podcandidate.CustomResource{fixKnativeServiceDefinition()}, validators, scope.Scope{}, 10*time.Millisecond, log.New(ioutil.Discard, "", 0), listener)
+		require.NoError(t, err)
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		done := make(chan error)
+		go func() {
+			done <- sut.Run(ctx)
+		}()
+
+		// WHEN
+		initial := listener.next(t)
+		require.NoError(t, dynamicClient.Resource(fixKnativeServiceDefinition().GroupVersionResource()).Namespace("orders").Delete(ctx, "orders-a", metav1.DeleteOptions{}))
+		afterDelete := listener.next(t)
+
+		// THEN
+		assert.Empty(t, initial.Violations)
+		require.Len(t, afterDelete.Added, 1)
+		assert.Equal(t, "ingress-to-a", afterDelete.Added[0].NetworkPolicyName)
+
+		cancel()
+		require.NoError(t, <-done)
+	})
+
+	t.Run("re-runs validators of network policies when a namespace enters the scope", func(t *testing.T) {
+		// GIVEN
+		netPol := fixNetPol("orders", "ingress-to-a", "orders-a")
+		netPol.Spec.PolicyTypes = []netv1.PolicyType{netv1.PolicyTypeEgress}
+		netPol.Spec.Ingress = []netv1.NetworkPolicyIngressRule{{}}
+		fakeClientset := fake.NewSimpleClientset(fixNs("orders"), netPol)
+		listener := newChanListener()
+		validators := map[string]rule.Validator{
+			"policy types": rule.NewPolicyTypes(),
+		}
+		validationScope, err := scope.New(nil, "team=orders", nil)
+		require.NoError(t, err)
+		fakeClientset.Resources = fixServedResources()
+		sut, err := watch.New(fakeClientset, dynamicfake.NewSimpleDynamicClient(runtime.NewScheme()), nil, validators, validationScope, 10*time.Millisecond, log.New(ioutil.Discard, "", 0), listener)
+		require.NoError(t, err)
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		done := make(chan error)
+		go func() {
+			done <- sut.Run(ctx)
+		}()
+
+		// WHEN
+		initial := listener.next(t)
+		relabeled := fixNs("orders")
+		relabeled.Labels = map[string]string{"team": "orders"}
+		_, err = fakeClientset.CoreV1().Namespaces().Update(ctx, relabeled, metav1.UpdateOptions{})
+		require.NoError(t, err)
+		afterRelabel := listener.next(t)
+
+		// THEN
+		assert.Empty(t, initial.Violations)
+		require.Len(t, afterRelabel.Added, 1)
+		assert.Equal(t, "ingress-to-a", afterRelabel.Added[0].NetworkPolicyName)
+		assert.Equal(t, model.ViolationIgnoredRules, afterRelabel.Added[0].Type)
+
+		cancel()
+		require.NoError(t, <-done)
+	})
+}
+
+type chanListener struct {
+	scans chan watch.Scan
+}
+
+func newChanListener() *chanListener {
+	return &chanListener{scans: make(chan watch.Scan, 10)}
+}
+
+func (l *chanListener) OnScan(scan watch.Scan) {
+	l.scans <- scan
+}
+
+func (l *chanListener) next(t *testing.T) watch.Scan {
+	t.Helper()
+	select {
+	case scan := <-l.scans:
+		return scan
+	case <-time.After(5 * time.Second):
+		require.FailNow(t, "timeout while waiting for scan")
+		return watch.Scan{}
+	}
+}
+
+type countingValidator struct {
+	calls int
+}
+
+func (v *countingValidator) Validate(model.ClusterState) ([]model.Violation, error) {
+	v.calls++
+	return nil, nil
+}
+
+type dependentValidator struct {
+	countingValidator
+	dependsOn []string
+}
+
+func (v *dependentValidator) DependsOn() []string {
+	return v.dependsOn
+}
+
+func fixNs(name string) *v1.Namespace {
+	return &v1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: name,
+		},
+	}
+}
+
+func fixNetPol(namespace, name, app string) *netv1.NetworkPolicy {
+	return &netv1.NetworkPolicy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+		},
+		Spec: netv1.NetworkPolicySpec{
+			PodSelector: metav1.LabelSelector{
+				MatchLabels: map[string]string{"app": app},
+			},
+		},
+	}
+}
+
+func fixDeployment(namespace, app string) *appsv1.Deployment {
+	return &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      app,
+			Namespace: namespace,
+		},
+		Spec: appsv1.DeploymentSpec{
+			Template: v1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: map[string]string{"app": app},
+				},
+			},
+		},
+	}
+}
+
+func fixKnativeServiceDefinition() podcandidate.CustomResource {
+	return podcandidate.CustomResource{
+		Name:       "knative-service",
+		Group:      "serving.knative.dev",
+		Version:    "v1",
+		Resource:   "services",
+		LabelsPath: "{.spec.template.metadata.labels}",
+	}
+}
+
+func fixKnativeService(namespace, app string) *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "serving.knative.dev/v1",
+		"kind":       "Service",
+		"metadata": map[string]interface{}{
+			"namespace": namespace,
+			"name":      app,
+		},
+		"spec": map[string]interface{}{
+			"template": map[string]interface{}{
+				"metadata": map[string]interface{}{"labels": map[string]interface{}{"app": app}},
+			},
+		},
+	}}
+}
+
+func fixServedResources() []*metav1.APIResourceList {
+	return []*metav1.APIResourceList{
+		{GroupVersion: "v1", APIResources: []metav1.APIResource{{Name: "namespaces"}, {Name: "pods"}}},
+		{GroupVersion: "apps/v1", APIResources: []metav1.APIResource{{Name: "deployments"}, {Name: "deployments/scale"}}},
+		{GroupVersion: "batch/v1", APIResources: []metav1.APIResource{{Name: "cronjobs"}, {Name: "jobs"}}},
+	}
+}
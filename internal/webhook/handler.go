@@ -0,0 +1,174 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"sort"
+	"strings"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	v1 "k8s.io/api/core/v1"
+	netv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/aszecowka/netpolvalidator/internal/model"
+	"github.com/aszecowka/netpolvalidator/internal/rule"
+)
+
+var networkPolicyResource = metav1.GroupVersionResource{Group: "networking.k8s.io", Version: "v1", Resource: "networkpolicies"}
+
+type StateBuilder interface {
+	Build(ctx context.Context) (*model.ClusterState, error)
+}
+
+// Handler serves AdmissionReview requests of a ValidatingAdmissionWebhook. It validates the incoming network policy
+// against the current cluster state and denies it when it has violations of the deny severity or more serious ones.
+// Other violations are returned as admission warnings. Updates of workloads are never denied, but they get warnings
+// naming network policies that would stop matching any pods because of changed pod template labels.
+type Handler struct {
+	builder    StateBuilder
+	validators map[string]rule.Validator
+	// denySeverity set to empty value means that violations never deny the request.
+	denySeverity model.Severity
+	logger       *log.Logger
+}
+
+func NewHandler(builder StateBuilder, validators map[string]rule.Validator, denySeverity model.Severity, logger *log.Logger) *Handler {
+	return &Handler{
+		builder:      builder,
+		validators:   validators,
+		denySeverity: denySeverity,
+		logger:       logger,
+	}
+}
+
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "only POST is supported", http.StatusMethodNotAllowed)
+		return
+	}
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("while reading request body: %s", err), http.StatusBadRequest)
+		return
+	}
+	review := admissionv1.AdmissionReview{}
+	if err := json.Unmarshal(body, &review); err != nil {
+		http.Error(w, fmt.Sprintf("while decoding admission review: %s", err), http.StatusBadRequest)
+		return
+	}
+	if review.Request == nil {
+		http.Error(w, "admission review does not contain request", http.StatusBadRequest)
+		return
+	}
+
+	response, err := h.review(r.Context(), review.Request)
+	if err != nil {
+		// the API server applies failurePolicy of the webhook configuration when the webhook call fails
+		h.logger.Printf("while reviewing %s %s/%s: %s", review.Request.Operation, review.Request.Namespace, review.Request.Name, err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	response.UID = review.Request.UID
+	review.Response = response
+	review.Request = nil
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(review); err != nil {
+		h.logger.Printf("while writing admission review: %s", err)
+	}
+}
+
+func (h *Handler) review(ctx context.Context, req *admissionv1.AdmissionRequest) (*admissionv1.AdmissionResponse, error) {
+	if req.Resource == networkPolicyResource && (req.Operation == admissionv1.Create || req.Operation == admissionv1.Update) {
+		return h.reviewNetworkPolicy(ctx, req)
+	}
+	if workloadType, found := templateOwnerResources[req.Resource]; found && req.Operation == admissionv1.Update {
+		return h.reviewTemplateOwner(ctx, req, workloadType)
+	}
+	return &admissionv1.AdmissionResponse{Allowed: true}, nil
+}
+
+func (h *Handler) reviewNetworkPolicy(ctx context.Context, req *admissionv1.AdmissionRequest) (*admissionv1.AdmissionResponse, error) {
+	allowed := &admissionv1.AdmissionResponse{Allowed: true}
+
+	np := netv1.NetworkPolicy{}
+	if err := json.Unmarshal(req.Object.Raw, &np); err != nil {
+		return nil, fmt.Errorf("while decoding network policy: %w", err)
+	}
+	if np.Namespace == "" {
+		np.Namespace = req.Namespace
+	}
+
+	clusterState, err := h.builder.Build(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("while building cluster state: %w", err)
+	}
+	violations, err := h.validate(withNetworkPolicy(*clusterState, np))
+	if err != nil {
+		return nil, err
+	}
+
+	var denied []string
+	for _, v := range violations {
+		if v.Namespace != np.Namespace || v.NetworkPolicyName != np.Name {
+			continue
+		}
+		if h.denySeverity != "" && v.Type.Severity().AtLeast(h.denySeverity) {
+			denied = append(denied, v.Message)
+			continue
+		}
+		allowed.Warnings = append(allowed.Warnings, fmt.Sprintf("%s: %s", v.Type, v.Message))
+	}
+	if len(denied) == 0 {
+		return allowed, nil
+	}
+	return &admissionv1.AdmissionResponse{
+		Allowed: false,
+		Result: &metav1.Status{
+			Status:  metav1.StatusFailure,
+			Code:    http.StatusForbidden,
+			Reason:  metav1.StatusReasonForbidden,
+			Message: fmt.Sprintf("network policy %s/%s has %d violation(s): %s", np.Namespace, np.Name, len(denied), strings.Join(denied, "; ")),
+		},
+		Warnings: allowed.Warnings,
+	}, nil
+}
+
+// validate runs all validators against the cluster state.
+func (h *Handler) validate(clusterState model.ClusterState) ([]model.Violation, error) {
+	names := make([]string, 0, len(h.validators))
+	for name := range h.validators {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var out []model.Violation
+	for _, name := range names {
+		violations, err := h.validators[name].Validate(clusterState)
+		if err != nil {
+			return nil, fmt.Errorf("while running %s validator: %w", name, err)
+		}
+		out = append(out, violations...)
+	}
+	return out, nil
+}
+
+// withNetworkPolicy returns a copy of the cluster state in which np is the only network policy. Validators check every
+// network policy on its own, so the other ones are left out to keep the cost of a review independent of the cluster size.
+func withNetworkPolicy(clusterState model.ClusterState, np netv1.NetworkPolicy) model.ClusterState {
+	clusterState.NetworkPolicies = map[string][]netv1.NetworkPolicy{np.Namespace: {np}}
+
+	for _, ns := range clusterState.Namespaces {
+		if ns.Name == np.Namespace {
+			return clusterState
+		}
+	}
+	// the namespace may be so new that it is not cached yet
+	clusterState.Namespaces = append(append([]v1.Namespace(nil), clusterState.Namespaces...), v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: np.Namespace}})
+	return clusterState
+}
@@ -0,0 +1,329 @@
+package webhook_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	admissionv1 "k8s.io/api/admission/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	netv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/aszecowka/netpolvalidator/internal/model"
+	"github.com/aszecowka/netpolvalidator/internal/rule"
+	"github.com/aszecowka/netpolvalidator/internal/webhook"
+)
+
+func TestHandler(t *testing.T) {
+	givenState := model.ClusterState{
+		Namespaces: []v1.Namespace{{ObjectMeta: metav1.ObjectMeta{Name: "orders"}}},
+		NetworkPolicies: map[string][]netv1.NetworkPolicy{
+			"orders": {fixNetPol("ingress-to-a", "b")},
+		},
+		PodCandidates: map[string][]model.PodCandidate{
+			"orders": {{Owner: model.Owner{Name: "a"}, Labels: map[string]string{"app": "a"}}},
+		},
+	}
+	validators := map[string]rule.Validator{"label correctness": rule.NewLabelCorrectness()}
+
+	t.Run("denies network policy with violations of deny severity", func(t *testing.T) {
+		// GIVEN
+		server := httptest.NewTLSServer(webhook.NewHandler(fixStateBuilder(givenState, nil), validators, model.SeverityError, fixLogger()))
+		defer server.Close()
+		// WHEN
+		actual := sendReview(t, server, fixReview(admissionv1.Create, fixNetPol("ingress-to-b", "b")))
+		// THEN
+		assert.Equal(t, types.UID("review-uid"), actual.UID)
+		assert.False(t, actual.Allowed)
+		require.NotNil(t, actual.Result)
+		assert.Equal(t, int32(http.StatusForbidden), actual.Result.Code)
+		assert.Equal(t, "network policy orders/ingress-to-b has 1 violation(s): no pods matching pod selector", actual.Result.Message)
+	})
+
+	t.Run("warns about violations below deny severity", func(t *testing.T) {
+		// GIVEN
+		server := httptest.NewTLSServer(webhook.NewHandler(fixStateBuilder(givenState, nil), validators, "", fixLogger()))
+		defer server.Close()
+		// WHEN
+		actual := sendReview(t, server, fixReview(admissionv1.Create, fixNetPol("ingress-to-b", "b")))
+		// THEN
+		assert.True(t, actual.Allowed)
+		assert.Equal(t, []string{"Invalid Label: no pods matching pod selector"}, actual.Warnings)
+	})
+
+	t.Run("allows update that fixes stored network policy", func(t *testing.T) {
+		// GIVEN
+		server := httptest.NewTLSServer(webhook.NewHandler(fixStateBuilder(givenState, nil), validators, model.SeverityError, fixLogger()))
+		defer server.Close()
+		// WHEN
+		actual := sendReview(t, server, fixReview(admissionv1.Update, fixNetPol("ingress-to-a", "a")))
+		// THEN
+		assert.True(t, actual.Allowed)
+		assert.Empty(t, actual.Warnings)
+	})
+
+	t.Run("denies update that breaks stored network policy", func(t *testing.T) {
+		// GIVEN
+		givenCorrectState := givenState
+		givenCorrectState.NetworkPolicies = map[string][]netv1.NetworkPolicy{"orders": {fixNetPol("ingress-to-a", "a")}}
+		server := httptest.NewTLSServer(webhook.NewHandler(fixStateBuilder(givenCorrectState, nil), validators, model.SeverityError, fixLogger()))
+		defer server.Close()
+		// WHEN
+		actual := sendReview(t, server, fixReview(admissionv1.Update, fixNetPol("ingress-to-a", "b")))
+		// THEN
+		assert.False(t, actual.Allowed)
+	})
+
+	t.Run("allows other resources", func(t *testing.T) {
+		// GIVEN
+		server := httptest.NewTLSServer(webhook.NewHandler(fixStateBuilder(givenState, nil), validators, model.SeverityError, fixLogger()))
+		defer server.Close()
+		givenReview := fixReview(admissionv1.Create, fixNetPol("ingress-to-b", "b"))
+		givenReview.Request.Resource = metav1.GroupVersionResource{Version: "v1", Resource: "pods"}
+		// WHEN
+		actual := sendReview(t, server, givenReview)
+		// THEN
+		assert.True(t, actual.Allowed)
+	})
+
+	t.Run("fails when cluster state cannot be built", func(t *testing.T) {
+		// GIVEN
+		server := httptest.NewTLSServer(webhook.NewHandler(fixStateBuilder(model.ClusterState{}, errors.New("some error")), validators, model.SeverityError, fixLogger()))
+		defer server.Close()
+		body, err := json.Marshal(fixReview(admissionv1.Create, fixNetPol("ingress-to-b", "b")))
+		require.NoError(t, err)
+		// WHEN
+		resp, err := server.Client().Post(server.URL, "application/json", bytes.NewReader(body))
+		// THEN
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
+	})
+
+	t.Run("validates only the submitted network policy", func(t *testing.T) {
+		// GIVEN
+		givenStateWithManyPolicies := givenState
+		givenStateWithManyPolicies.NetworkPolicies = map[string][]netv1.NetworkPolicy{
+			"orders":   {fixNetPol("ingress-to-a", "a"), fixNetPol("ingress-to-b", "b")},
+			"payments": {fixNetPol("ingress-to-c", "c")},
+		}
+		recorder := &recordingValidator{}
+		server := httptest.NewTLSServer(webhook.NewHandler(fixStateBuilder(givenStateWithManyPolicies, nil), map[string]rule.Validator{"recorder": recorder}, model.SeverityError, fixLogger()))
+		defer server.Close()
+		// WHEN
+		actual := sendReview(t, server, fixReview(admissionv1.Update, fixNetPol("ingress-to-a", "a")))
+		// THEN
+		assert.True(t, actual.Allowed)
+		assert.Equal(t, []string{"orders/ingress-to-a"}, recorder.validated)
+	})
+
+	t.Run("rejects malformed admission review", func(t *testing.T) {
+		// GIVEN
+		server := httptest.NewTLSServer(webhook.NewHandler(fixStateBuilder(givenState, nil), validators, model.SeverityError, fixLogger()))
+		defer server.Close()
+		// WHEN
+		resp, err := server.Client().Post(server.URL, "application/json", bytes.NewReader([]byte("{")))
+		// THEN
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+}
+
+func TestHandlerForWorkloads(t *testing.T) {
+	givenState := model.ClusterState{
+		Namespaces: []v1.Namespace{{ObjectMeta: metav1.ObjectMeta{Name: "orders"}}},
+		NetworkPolicies: map[string][]netv1.NetworkPolicy{
+			"orders": {fixNetPol("ingress-to-a", "a")},
+		},
+		PodCandidates: map[string][]model.PodCandidate{
+			"orders": {
+				{Owner: model.Owner{Kind: "deployment", Namespace: "orders", Name: "a"}, Labels: map[string]string{"app": "a"}},
+				{Owner: model.Owner{Kind: "pod", Namespace: "orders", Name: "a-5d8f7"}, Labels: map[string]string{"app": "a", "pod-template-hash": "5d8f7"}},
+			},
+		},
+	}
+	validators := map[string]rule.Validator{"label correctness": rule.NewLabelCorrectness()}
+
+	t.Run("warns about network policies orphaned by changed pod template labels", func(t *testing.T) {
+		// GIVEN
+		server := httptest.NewTLSServer(webhook.NewHandler(fixStateBuilder(givenState, nil), validators, model.SeverityError, fixLogger()))
+		defer server.Close()
+		// WHEN
+		actual := sendReview(t, server, fixDeploymentReview(fixDeployment("a"), fixDeployment("a-v2")))
+		// THEN
+		assert.True(t, actual.Allowed)
+		assert.Equal(t, []string{"network policy orders/ingress-to-a: no pods matching pod selector"}, actual.Warnings)
+	})
+
+	t.Run("does not warn when other workloads still match", func(t *testing.T) {
+		// GIVEN
+		givenStateWithOtherWorkload := givenState
+		givenStateWithOtherWorkload.PodCandidates = map[string][]model.PodCandidate{
+			"orders": append([]model.PodCandidate{{Owner: model.Owner{Kind: "statefulset", Namespace: "orders", Name: "b"}, Labels: map[string]string{"app": "a"}}}, givenState.PodCandidates["orders"]...),
+		}
+		server := httptest.NewTLSServer(webhook.NewHandler(fixStateBuilder(givenStateWithOtherWorkload, nil), validators, model.SeverityError, fixLogger()))
+		defer server.Close()
+		// WHEN
+		actual := sendReview(t, server, fixDeploymentReview(fixDeployment("a"), fixDeployment("a-v2")))
+		// THEN
+		assert.True(t, actual.Allowed)
+		assert.Empty(t, actual.Warnings)
+	})
+
+	t.Run("validates only network policies that may select pods from the namespace", func(t *testing.T) {
+		// GIVEN
+		givenStateWithManyNamespaces := givenState
+		givenStateWithManyNamespaces.Namespaces = []v1.Namespace{
+			{ObjectMeta: metav1.ObjectMeta{Name: "orders", Labels: map[string]string{"team": "orders"}}},
+			{ObjectMeta: metav1.ObjectMeta{Name: "payments"}},
+		}
+		fromOrders := fixNetPol("ingress-from-orders", "c")
+		fromOrders.Spec.Ingress = []netv1.NetworkPolicyIngressRule{{From: []netv1.NetworkPolicyPeer{{NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"team": "orders"}}}}}}
+		fromShipping := fixNetPol("ingress-from-shipping", "c")
+		fromShipping.Spec.Ingress = []netv1.NetworkPolicyIngressRule{{From: []netv1.NetworkPolicyPeer{{NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"team": "shipping"}}}}}}
+		givenStateWithManyNamespaces.NetworkPolicies = map[string][]netv1.NetworkPolicy{
+			"orders":   {fixNetPol("ingress-to-a", "a")},
+			"payments": {fixNetPol("ingress-to-c", "c"), fromOrders, fromShipping},
+		}
+		recorder := &recordingValidator{}
+		server := httptest.NewTLSServer(webhook.NewHandler(fixStateBuilder(givenStateWithManyNamespaces, nil), map[string]rule.Validator{"recorder": recorder}, model.SeverityError, fixLogger()))
+		defer server.Close()
+		// WHEN
+		actual := sendReview(t, server, fixDeploymentReview(fixDeployment("a"), fixDeployment("a-v2")))
+		// THEN
+		assert.True(t, actual.Allowed)
+		assert.ElementsMatch(t, []string{"orders/ingress-to-a", "payments/ingress-from-orders", "orders/ingress-to-a", "payments/ingress-from-orders"}, recorder.validated)
+	})
+
+	t.Run("does not build cluster state when pod template labels are the same", func(t *testing.T) {
+		// GIVEN
+		server := httptest.NewTLSServer(webhook.NewHandler(fixStateBuilder(model.ClusterState{}, errors.New("some error")), validators, model.SeverityError, fixLogger()))
+		defer server.Close()
+		// WHEN
+		actual := sendReview(t, server, fixDeploymentReview(fixDeployment("a"), fixDeployment("a")))
+		// THEN
+		assert.True(t, actual.Allowed)
+		assert.Empty(t, actual.Warnings)
+	})
+}
+
+// recordingValidator records network policies it was asked to validate, in the namespace/name format.
+type recordingValidator struct {
+	validated []string
+}
+
+func (r *recordingValidator) Validate(state model.ClusterState) ([]model.Violation, error) {
+	for ns, policies := range state.NetworkPolicies {
+		for _, np := range policies {
+			r.validated = append(r.validated, ns+"/"+np.Name)
+		}
+	}
+	return nil, nil
+}
+
+type stateBuilderFunc func(ctx context.Context) (*model.ClusterState, error)
+
+func (f stateBuilderFunc) Build(ctx context.Context) (*model.ClusterState, error) {
+	return f(ctx)
+}
+
+func fixStateBuilder(clusterState model.ClusterState, err error) webhook.StateBuilder {
+	return stateBuilderFunc(func(context.Context) (*model.ClusterState, error) {
+		if err != nil {
+			return nil, err
+		}
+		return &clusterState, nil
+	})
+}
+
+func sendReview(t *testing.T, server *httptest.Server, review admissionv1.AdmissionReview) *admissionv1.AdmissionResponse {
+	body, err := json.Marshal(review)
+	require.NoError(t, err)
+	resp, err := server.Client().Post(server.URL, "application/json", bytes.NewReader(body))
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	actual := admissionv1.AdmissionReview{}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&actual))
+	require.NotNil(t, actual.Response)
+	return actual.Response
+}
+
+func fixReview(operation admissionv1.Operation, np netv1.NetworkPolicy) admissionv1.AdmissionReview {
+	raw, err := json.Marshal(np)
+	if err != nil {
+		panic(err)
+	}
+	return admissionv1.AdmissionReview{
+		TypeMeta: metav1.TypeMeta{APIVersion: "admission.k8s.io/v1", Kind: "AdmissionReview"},
+		Request: &admissionv1.AdmissionRequest{
+			UID:       "review-uid",
+			Resource:  metav1.GroupVersionResource{Group: "networking.k8s.io", Version: "v1", Resource: "networkpolicies"},
+			Namespace: np.Namespace,
+			Name:      np.Name,
+			Operation: operation,
+			Object:    runtime.RawExtension{Raw: raw},
+		},
+	}
+}
+
+func fixDeploymentReview(oldDeploy, newDeploy appsv1.Deployment) admissionv1.AdmissionReview {
+	oldRaw, err := json.Marshal(oldDeploy)
+	if err != nil {
+		panic(err)
+	}
+	newRaw, err := json.Marshal(newDeploy)
+	if err != nil {
+		panic(err)
+	}
+	return admissionv1.AdmissionReview{
+		TypeMeta: metav1.TypeMeta{APIVersion: "admission.k8s.io/v1", Kind: "AdmissionReview"},
+		Request: &admissionv1.AdmissionRequest{
+			UID:       "review-uid",
+			Resource:  metav1.GroupVersionResource{Group: "apps", Version: "v1", Resource: "deployments"},
+			Namespace: newDeploy.Namespace,
+			Name:      newDeploy.Name,
+			Operation: admissionv1.Update,
+			Object:    runtime.RawExtension{Raw: newRaw},
+			OldObject: runtime.RawExtension{Raw: oldRaw},
+		},
+	}
+}
+
+func fixDeployment(app string) appsv1.Deployment {
+	return appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "a", Namespace: "orders"},
+		Spec: appsv1.DeploymentSpec{
+			Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": app}},
+			Template: v1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"app": app}},
+			},
+		},
+	}
+}
+
+func fixNetPol(name, app string) netv1.NetworkPolicy {
+	return netv1.NetworkPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "orders"},
+		Spec: netv1.NetworkPolicySpec{
+			PodSelector: metav1.LabelSelector{MatchLabels: map[string]string{"app": app}},
+		},
+	}
+}
+
+func fixLogger() *log.Logger {
+	return log.New(&bytes.Buffer{}, "", 0)
+}
@@ -0,0 +1,128 @@
+package webhook
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	netv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	"github.com/aszecowka/netpolvalidator/internal/model"
+	"github.com/aszecowka/netpolvalidator/internal/podcandidate"
+)
+
+var templateOwnerResources = map[metav1.GroupVersionResource]podcandidate.WorkloadType{
+	{Group: "apps", Version: "v1", Resource: "deployments"}:  podcandidate.WorkloadDeployment,
+	{Group: "apps", Version: "v1", Resource: "statefulsets"}: podcandidate.WorkloadStatefulset,
+	{Group: "apps", Version: "v1", Resource: "daemonsets"}:   podcandidate.WorkloadDaemonset,
+}
+
+// reviewTemplateOwner warns about network policies that get new violations when pods of the workload are
+// replaced by pods created from the new pod template.
+func (h *Handler) reviewTemplateOwner(ctx context.Context, req *admissionv1.AdmissionRequest, workloadType podcandidate.WorkloadType) (*admissionv1.AdmissionResponse, error) {
+	allowed := &admissionv1.AdmissionResponse{Allowed: true}
+	oldOwner, err := podcandidate.DecodeTemplateOwner(workloadType, req.OldObject.Raw)
+	if err != nil {
+		return nil, err
+	}
+	newOwner, err := podcandidate.DecodeTemplateOwner(workloadType, req.Object.Raw)
+	if err != nil {
+		return nil, err
+	}
+	if reflect.DeepEqual(oldOwner.Candidate.Labels, newOwner.Candidate.Labels) {
+		return allowed, nil
+	}
+
+	clusterState, err := h.builder.Build(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("while building cluster state: %w", err)
+	}
+	clusterState.NetworkPolicies = policiesReferencing(*clusterState, req.Namespace)
+	before, err := h.validate(withTemplateOwner(*clusterState, req.Namespace, oldOwner, oldOwner.Candidate))
+	if err != nil {
+		return nil, err
+	}
+	after, err := h.validate(withTemplateOwner(*clusterState, req.Namespace, oldOwner, newOwner.Candidate))
+	if err != nil {
+		return nil, err
+	}
+	for _, v := range model.Subtract(after, before) {
+		allowed.Warnings = append(allowed.Warnings, fmt.Sprintf("network policy %s/%s: %s", v.Namespace, v.NetworkPolicyName, v.Message))
+	}
+	return allowed, nil
+}
+
+// withTemplateOwner returns a copy of the cluster state in which candidate replaces the pod template of the owner
+// and the pods created by the owner, recognized by its selector.
+func withTemplateOwner(clusterState model.ClusterState, namespace string, owner podcandidate.TemplateOwner, candidate model.PodCandidate) model.ClusterState {
+	selector, err := metav1.LabelSelectorAsSelector(owner.Selector)
+	if err != nil || owner.Selector == nil {
+		selector = labels.Nothing()
+	}
+
+	candidates := make(map[string][]model.PodCandidate, len(clusterState.PodCandidates)+1)
+	for ns, pcs := range clusterState.PodCandidates {
+		candidates[ns] = pcs
+	}
+	var inNamespace []model.PodCandidate
+	for _, pc := range candidates[namespace] {
+		if pc.Owner == owner.Candidate.Owner {
+			continue
+		}
+		if podcandidate.IsPod(pc) && selector.Matches(labels.Set(pc.Labels)) {
+			continue
+		}
+		inNamespace = append(inNamespace, pc)
+	}
+	candidates[namespace] = append(inNamespace, candidate)
+	clusterState.PodCandidates = candidates
+	return clusterState
+}
+
+// policiesReferencing returns network policies that may select pods from the namespace: policies from the namespace
+// itself and policies from other namespaces with a peer whose namespace selector matches it.
+func policiesReferencing(clusterState model.ClusterState, namespace string) map[string][]netv1.NetworkPolicy {
+	var namespaceLabels labels.Set
+	for _, ns := range clusterState.Namespaces {
+		if ns.Name == namespace {
+			namespaceLabels = ns.Labels
+		}
+	}
+
+	out := map[string][]netv1.NetworkPolicy{namespace: clusterState.NetworkPolicies[namespace]}
+	for ns, policies := range clusterState.NetworkPolicies {
+		if ns == namespace {
+			continue
+		}
+		for _, np := range policies {
+			if hasPeerInNamespace(np, namespaceLabels) {
+				out[ns] = append(out[ns], np)
+			}
+		}
+	}
+	return out
+}
+
+func hasPeerInNamespace(np netv1.NetworkPolicy, namespaceLabels labels.Set) bool {
+	var peers []netv1.NetworkPolicyPeer
+	for _, rule := range np.Spec.Ingress {
+		peers = append(peers, rule.From...)
+	}
+	for _, rule := range np.Spec.Egress {
+		peers = append(peers, rule.To...)
+	}
+	for _, peer := range peers {
+		if peer.NamespaceSelector == nil {
+			continue
+		}
+		selector, err := metav1.LabelSelectorAsSelector(peer.NamespaceSelector)
+		// invalid selectors are reported by validators, so the network policy is kept for them
+		if err != nil || selector.Matches(namespaceLabels) {
+			return true
+		}
+	}
+	return false
+}